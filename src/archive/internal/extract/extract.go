@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package extract implements the parts of extracting archives into
+// an [os.Root] shared by archive/tar and archive/zip, in particular
+// the handling of entry names and link targets.
+package extract
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// An Extractor holds the state of extracting an archive into Root.
+type Extractor struct {
+	Root         *os.Root
+	MaxFileSize  int64 // if positive, the largest file that may be written
+	MaxTotalSize int64 // if positive, the largest total size of files written
+
+	Total int64 // total size of regular files written by WriteFile
+
+	dirs []dir // directories to update in Finish
+}
+
+// A dir is a directory whose mode and times are set
+// after its contents have been extracted.
+type dir struct {
+	name         string
+	perm         fs.FileMode
+	chmod        bool
+	atime, mtime time.Time
+}
+
+// Limit returns the largest number of bytes that may be written
+// to the next file, or -1 if there is no limit.
+func (x *Extractor) Limit() int64 {
+	limit := int64(-1)
+	if x.MaxFileSize > 0 {
+		limit = x.MaxFileSize
+	}
+	if x.MaxTotalSize > 0 && (limit < 0 || x.MaxTotalSize-x.Total < limit) {
+		limit = x.MaxTotalSize - x.Total
+	}
+	return limit
+}
+
+// Mkdir creates the directory name and its parents, reusing an
+// existing directory. The directory is created writable, and Finish
+// sets its permissions to perm and its times to atime and mtime.
+func (x *Extractor) Mkdir(name string, perm fs.FileMode, atime, mtime time.Time) error {
+	if err := x.mkdirParent(name); err != nil {
+		return err
+	}
+	if err := x.Root.Mkdir(name, perm|0o700); err != nil {
+		if info, lerr := x.Root.Lstat(name); lerr != nil || !info.IsDir() {
+			return err
+		}
+	}
+	x.dirs = append(x.dirs, dir{
+		name:  name,
+		perm:  perm,
+		chmod: perm&0o700 != 0o700,
+		atime: atime,
+		mtime: mtime,
+	})
+	return nil
+}
+
+// Finish sets the mode and times of the directories created by Mkdir,
+// in reverse order so that subdirectories usually come first.
+func (x *Extractor) Finish() error {
+	for i := len(x.dirs) - 1; i >= 0; i-- {
+		d := x.dirs[i]
+		if d.chmod {
+			if err := x.Root.Chmod(d.name, d.perm); err != nil {
+				return err
+			}
+		}
+		if err := x.Chtimes(d.name, d.atime, d.mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Chtimes sets the times of name, unless mtime is zero.
+func (x *Extractor) Chtimes(name string, atime, mtime time.Time) error {
+	if mtime.IsZero() {
+		return nil
+	}
+	return x.Root.Chtimes(name, atime, mtime)
+}
+
+// mkdirParent creates the parent directories of name.
+func (x *Extractor) mkdirParent(name string) error {
+	if dir := filepath.Dir(name); dir != "." {
+		return x.Root.MkdirAll(dir, 0o777)
+	}
+	return nil
+}
+
+// Replace prepares to create the file name, creating its parent
+// directories and removing any existing file other than a directory.
+func (x *Extractor) Replace(name string) error {
+	if err := x.mkdirParent(name); err != nil {
+		return err
+	}
+	info, err := x.Root.Lstat(name)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+	return x.Root.Remove(name)
+}
+
+// WriteFile creates the regular file name with permissions perm,
+// replacing any existing file, and copies the contents of the entry
+// entryName from r to it. size is the size of the entry as recorded
+// in the archive.
+//
+// If size exceeds Limit, WriteFile returns an [fs.PathError] wrapping
+// errLimit and leaves any existing file in place. If r holds more
+// than Limit bytes, or cannot be read, WriteFile removes the file and
+// returns an error.
+func (x *Extractor) WriteFile(name, entryName string, perm fs.FileMode, size int64, r io.Reader, errLimit error) error {
+	limit := x.Limit()
+	if limit >= 0 && size > limit {
+		return &fs.PathError{Op: "extract", Path: entryName, Err: errLimit}
+	}
+	if err := x.Replace(name); err != nil {
+		return err
+	}
+	f, err := x.Root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if limit >= 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(f, r)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	x.Total += n
+	if err == nil && limit >= 0 && n > limit {
+		err = &fs.PathError{Op: "extract", Path: entryName, Err: errLimit}
+	}
+	if err != nil {
+		x.Root.Remove(name)
+		return err
+	}
+	return nil
+}
+
+// Localize converts the slash-separated name of an entry to a local
+// operating system path. It returns an [fs.PathError] wrapping
+// errInsecure if name is not a local path.
+func Localize(name string, errInsecure error) (string, error) {
+	local, err := filepath.Localize(path.Clean(name))
+	if err != nil {
+		return "", &fs.PathError{Op: "extract", Path: name, Err: errInsecure}
+	}
+	return local, nil
+}
+
+// LocalLinkTarget reports whether the symbolic link name,
+// a local operating system path, with the slash-separated
+// target refers to a location within the same tree.
+func LocalLinkTarget(name, target string) bool {
+	if target == "" || path.IsAbs(target) || filepath.IsAbs(filepath.FromSlash(target)) {
+		return false
+	}
+	return filepath.IsLocal(filepath.Join(filepath.Dir(name), filepath.FromSlash(target)))
+}
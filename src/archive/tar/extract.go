@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"archive/internal/extract"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrExtractLimit is returned by [Reader.Extract] when the archive
+// exceeds one of the limits set in [ExtractOptions].
+var ErrExtractLimit = errors.New("archive/tar: extraction limit exceeded")
+
+// ExtractOptions controls the behavior of [Reader.Extract].
+// The zero value extracts every entry with no limits.
+type ExtractOptions struct {
+	// Filter, if non-nil, is called with the header of each entry
+	// before it is extracted. Filter may modify the header to rewrite
+	// the entry, for example by changing its Name, Linkname, Mode,
+	// or ModTime. If Filter returns false, the entry is skipped.
+	// If Filter returns an error, Extract stops and returns that error.
+	Filter func(hdr *Header) (bool, error)
+
+	// MaxFileSize, if positive, is the largest regular file
+	// that Extract will write.
+	MaxFileSize int64
+
+	// MaxTotalSize, if positive, is the largest total size
+	// of all regular files that Extract will write.
+	MaxTotalSize int64
+
+	// MaxEntries, if positive, is the largest number of entries
+	// that Extract will read from the archive, including skipped entries.
+	MaxEntries int
+}
+
+// Extract reads the remaining entries of the archive and creates
+// the files, directories, and links they describe in root.
+// Because all files are created through root, no entry can create
+// or modify a file outside of it.
+//
+// Each entry name must be a local, slash-separated path, as reported
+// by [filepath.IsLocal] after cleaning; otherwise Extract returns an
+// error wrapping [ErrInsecurePath]. The target of a symbolic link
+// must also be relative and must not refer to a location outside of
+// root, as determined lexically. The target of a hard link must be
+// the name of another file in root.
+//
+// Extract sets the permission bits and the modification and access
+// times of each file and directory from its header. The permissions
+// of files are subject to the umask. Directory permissions and
+// times are set once all entries have been extracted, so that a
+// read-only directory in the archive can still be populated.
+// Ownership, extended attributes, and the setuid, setgid, and sticky
+// bits are not restored. Device files, FIFOs, and other entry types
+// are skipped.
+//
+// An existing file with the same name as an entry is replaced,
+// without following it if it is a symbolic link. Existing
+// directories are reused.
+//
+// If the archive exceeds a limit in opts, Extract returns an error
+// wrapping [ErrExtractLimit]. A nil opts is equivalent to a zero
+// [ExtractOptions].
+func (tr *Reader) Extract(root *os.Root, opts *ExtractOptions) error {
+	if opts == nil {
+		opts = new(ExtractOptions)
+	}
+	x := &extract.Extractor{
+		Root:         root,
+		MaxFileSize:  opts.MaxFileSize,
+		MaxTotalSize: opts.MaxTotalSize,
+	}
+	for entries := 0; ; entries++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != ErrInsecurePath {
+			return err
+		}
+		if opts.MaxEntries > 0 && entries >= opts.MaxEntries {
+			return &fs.PathError{Op: "extract", Path: hdr.Name, Err: ErrExtractLimit}
+		}
+		if opts.Filter != nil {
+			keep, err := opts.Filter(hdr)
+			if err != nil {
+				return err
+			}
+			if !keep {
+				continue
+			}
+		}
+		if err := extractFile(x, hdr, tr); err != nil {
+			return err
+		}
+	}
+	return x.Finish()
+}
+
+func extractFile(x *extract.Extractor, hdr *Header, r io.Reader) error {
+	name, err := extract.Localize(hdr.Name, ErrInsecurePath)
+	if err != nil {
+		return err
+	}
+	perm := fs.FileMode(hdr.Mode) & fs.ModePerm
+
+	switch hdr.Typeflag {
+	case TypeDir:
+		if name == "." {
+			return nil
+		}
+		return x.Mkdir(name, perm, hdr.AccessTime, hdr.ModTime)
+
+	case TypeReg, TypeGNUSparse:
+		if err := x.WriteFile(name, hdr.Name, perm, hdr.Size, r, ErrExtractLimit); err != nil {
+			return err
+		}
+		return x.Chtimes(name, hdr.AccessTime, hdr.ModTime)
+
+	case TypeSymlink:
+		if !extract.LocalLinkTarget(name, hdr.Linkname) {
+			return &fs.PathError{Op: "extract", Path: hdr.Name, Err: ErrInsecurePath}
+		}
+		if err := x.Replace(name); err != nil {
+			return err
+		}
+		return x.Root.Symlink(filepath.FromSlash(hdr.Linkname), name)
+
+	case TypeLink:
+		oldname, err := extract.Localize(hdr.Linkname, ErrInsecurePath)
+		if err != nil {
+			return err
+		}
+		if oldname == name {
+			return nil
+		}
+		if err := x.Replace(name); err != nil {
+			return err
+		}
+		return x.Root.Link(oldname, name)
+	}
+	return nil
+}
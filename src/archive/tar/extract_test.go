@@ -0,0 +1,278 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tar
+
+import (
+	"archive/internal/extract"
+	"bytes"
+	"errors"
+	"internal/testenv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+type extractEntry struct {
+	hdr  Header
+	data string
+}
+
+// extractArchive writes entries to a tar archive and extracts it
+// into a new directory, returning the directory and the error from
+// Extract.
+func extractArchive(t *testing.T, entries []extractEntry, opts *ExtractOptions) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	return dir, extractArchiveTo(t, dir, entries, opts)
+}
+
+// extractArchiveTo writes entries to a tar archive and extracts it
+// into dir.
+func extractArchiveTo(t *testing.T, dir string, entries []extractEntry, opts *ExtractOptions) error {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := NewWriter(&buf)
+	for _, e := range entries {
+		hdr := e.hdr
+		if hdr.Typeflag == TypeReg {
+			hdr.Size = int64(len(e.data))
+		}
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	return NewReader(&buf).Extract(root, opts)
+}
+
+func TestExtract(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	testenv.MustHaveLink(t)
+
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	dir, err := extractArchive(t, []extractEntry{
+		{hdr: Header{Typeflag: TypeDir, Name: "d/", Mode: 0o750, ModTime: mtime}},
+		{hdr: Header{Typeflag: TypeReg, Name: "d/f", Mode: 0o640, ModTime: mtime}, data: "file"},
+		{hdr: Header{Typeflag: TypeReg, Name: "implicit/g", Mode: 0o600}, data: "g"},
+		{hdr: Header{Typeflag: TypeSymlink, Name: "d/link", Linkname: "../implicit/g"}},
+		{hdr: Header{Typeflag: TypeLink, Name: "hard", Linkname: "d/f"}},
+		{hdr: Header{Typeflag: TypeFifo, Name: "fifo", Mode: 0o600}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"d/f":        "file",
+		"implicit/g": "g",
+		"d/link":     "g",
+		"hard":       "file",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+		} else if string(got) != want {
+			t.Errorf("%s contains %q, want %q", name, got, want)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "d", "link")); err != nil || target != filepath.FromSlash("../implicit/g") {
+		t.Errorf("Readlink(d/link) = %q, %v", target, err)
+	}
+	fi1, err1 := os.Stat(filepath.Join(dir, "d", "f"))
+	fi2, err2 := os.Stat(filepath.Join(dir, "hard"))
+	if err1 != nil || err2 != nil || !os.SameFile(fi1, fi2) {
+		t.Errorf("hard is not a hard link to d/f: %v, %v", err1, err2)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "fifo")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("fifo was extracted: %v", err)
+	}
+
+	for _, name := range []string{"d", "d/f"} {
+		fi, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fi.ModTime().Equal(mtime) {
+			t.Errorf("%s: mtime = %v, want %v", name, fi.ModTime(), mtime)
+		}
+		if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+			continue
+		}
+		if perm := fi.Mode().Perm(); perm&^0o750 != 0 {
+			t.Errorf("%s: mode = %v, want subset of 0o750", name, perm)
+		}
+	}
+}
+
+func TestExtractReadOnlyDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skipf("directory permissions not supported on %s", runtime.GOOS)
+	}
+	dir, err := extractArchive(t, []extractEntry{
+		{hdr: Header{Typeflag: TypeDir, Name: "ro/", Mode: 0o555}},
+		{hdr: Header{Typeflag: TypeReg, Name: "ro/f", Mode: 0o444}, data: "x"},
+	}, nil)
+	defer os.Chmod(filepath.Join(dir, "ro"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filepath.Join(dir, "ro"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o555 {
+		t.Errorf("ro: mode = %v, want %v", perm, os.FileMode(0o555))
+	}
+}
+
+func TestExtractInsecure(t *testing.T) {
+	for _, hdr := range []Header{
+		{Typeflag: TypeReg, Name: "../escape"},
+		{Typeflag: TypeReg, Name: "/abs"},
+		{Typeflag: TypeReg, Name: "a/../../escape"},
+		{Typeflag: TypeSymlink, Name: "link", Linkname: "../outside"},
+		{Typeflag: TypeSymlink, Name: "d/link", Linkname: "../../outside"},
+		{Typeflag: TypeSymlink, Name: "link", Linkname: "/etc/passwd"},
+		{Typeflag: TypeSymlink, Name: "link", Linkname: ""},
+		{Typeflag: TypeLink, Name: "hard", Linkname: "../outside"},
+	} {
+		_, err := extractArchive(t, []extractEntry{{hdr: hdr}}, nil)
+		if !errors.Is(err, ErrInsecurePath) {
+			t.Errorf("Extract(%v %q -> %q) = %v, want ErrInsecurePath", hdr.Typeflag, hdr.Name, hdr.Linkname, err)
+		}
+	}
+}
+
+func TestExtractReplaceSymlink(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+
+	// A symlink in the archive followed by a file of the same name
+	// must not write through the symlink.
+	dir, err := extractArchive(t, []extractEntry{
+		{hdr: Header{Typeflag: TypeReg, Name: "victim", Mode: 0o644}, data: "original"},
+		{hdr: Header{Typeflag: TypeSymlink, Name: "f", Linkname: "victim"}},
+		{hdr: Header{Typeflag: TypeReg, Name: "f", Mode: 0o644}, data: "replaced"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "victim")); string(got) != "original" {
+		t.Errorf("victim contains %q, want %q", got, "original")
+	}
+	if fi, err := os.Lstat(filepath.Join(dir, "f")); err != nil || !fi.Mode().IsRegular() {
+		t.Errorf("f is not a regular file: %v", err)
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	entries := []extractEntry{
+		{hdr: Header{Typeflag: TypeReg, Name: "a", Mode: 0o644}, data: "0123456789"},
+		{hdr: Header{Typeflag: TypeReg, Name: "b", Mode: 0o644}, data: "0123456789"},
+		{hdr: Header{Typeflag: TypeDir, Name: "c/", Mode: 0o755}},
+	}
+	for _, tt := range []struct {
+		opts ExtractOptions
+		ok   bool
+	}{
+		{ExtractOptions{}, true},
+		{ExtractOptions{MaxFileSize: 10, MaxTotalSize: 20, MaxEntries: 3}, true},
+		{ExtractOptions{MaxFileSize: 9}, false},
+		{ExtractOptions{MaxTotalSize: 19}, false},
+		{ExtractOptions{MaxEntries: 2}, false},
+	} {
+		_, err := extractArchive(t, entries, &tt.opts)
+		if tt.ok && err != nil {
+			t.Errorf("Extract with %+v = %v, want nil", tt.opts, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrExtractLimit) {
+			t.Errorf("Extract with %+v = %v, want ErrExtractLimit", tt.opts, err)
+		}
+	}
+}
+
+// An entry larger than the limit does not replace an existing file.
+func TestExtractLimitsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries := []extractEntry{
+		{hdr: Header{Typeflag: TypeReg, Name: "a", Mode: 0o644}, data: "0123456789"},
+	}
+	err := extractArchiveTo(t, dir, entries, &ExtractOptions{MaxFileSize: 9})
+	if !errors.Is(err, ErrExtractLimit) {
+		t.Errorf("Extract = %v, want ErrExtractLimit", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a")); string(data) != "old" {
+		t.Errorf("existing file after Extract: %q, %v; want %q", data, err, "old")
+	}
+}
+
+// A file whose data is longer than its header's size is removed
+// when the data exceeds the limit.
+func TestExtractLimitsHeaderSize(t *testing.T) {
+	dir := t.TempDir()
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	x := &extract.Extractor{Root: root, MaxFileSize: 5}
+	hdr := &Header{Typeflag: TypeReg, Name: "a", Mode: 0o644, Size: 1}
+	err = extractFile(x, hdr, strings.NewReader("0123456789"))
+	if !errors.Is(err, ErrExtractLimit) {
+		t.Errorf("extractFile = %v, want ErrExtractLimit", err)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "a")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("file exceeding limit was not removed: Lstat = %v", err)
+	}
+}
+
+func TestExtractFilter(t *testing.T) {
+	errStop := errors.New("stop")
+	opts := &ExtractOptions{
+		Filter: func(hdr *Header) (bool, error) {
+			switch hdr.Name {
+			case "skip":
+				return false, nil
+			case "rename":
+				hdr.Name = "renamed"
+			case "stop":
+				return false, errStop
+			}
+			return true, nil
+		},
+	}
+	dir, err := extractArchive(t, []extractEntry{
+		{hdr: Header{Typeflag: TypeReg, Name: "skip", Mode: 0o644}, data: "s"},
+		{hdr: Header{Typeflag: TypeReg, Name: "rename", Mode: 0o644}, data: "r"},
+		{hdr: Header{Typeflag: TypeReg, Name: "stop", Mode: 0o644}, data: "x"},
+		{hdr: Header{Typeflag: TypeReg, Name: "after", Mode: 0o644}, data: "a"},
+	}, opts)
+	if err != errStop {
+		t.Errorf("Extract = %v, want %v", err, errStop)
+	}
+	for name, want := range map[string]bool{"skip": false, "rename": false, "renamed": true, "after": false} {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		if got := err == nil; got != want {
+			t.Errorf("%s exists = %v, want %v", name, got, want)
+		}
+	}
+}
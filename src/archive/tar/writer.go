@@ -403,7 +403,46 @@ func (tw *Writer) writeRawHeader(blk *block, size int64, flag byte) error {
 // AddFS adds the files from fs.FS to the archive.
 // It walks the directory tree starting at the root of the filesystem
 // adding each file to the tar archive while maintaining the directory structure.
+// It is equivalent to AddFSWithOptions(fsys, nil).
 func (tw *Writer) AddFS(fsys fs.FS) error {
+	return tw.AddFSWithOptions(fsys, nil)
+}
+
+// AddFSOptions controls the behavior of [Writer.AddFSWithOptions].
+type AddFSOptions struct {
+	// Filter, if non-nil, is called with the path and header of each
+	// file before it is added. Filter may modify the header.
+	// If Filter returns false, the file is not added; if the file is
+	// a directory, none of its contents are added either.
+	// If Filter returns an error, AddFSWithOptions stops and returns that error.
+	Filter func(name string, hdr *Header) (bool, error)
+
+	// ModTime, if non-zero, is used as the modification time
+	// of every file.
+	ModTime time.Time
+
+	// Normalize, if true, removes metadata that depends on the system
+	// the files are read from, so that the same files produce the same
+	// archive anywhere. It sets the Uid and Gid of each file to zero,
+	// clears the Uname, Gname, AccessTime, ChangeTime, Xattrs, and
+	// PAXRecords fields, and sets the mode of each directory and each
+	// file with any execute permission to 0o755, of each other regular
+	// file to 0o644, and of each symbolic link to 0o777.
+	Normalize bool
+}
+
+// AddFSWithOptions adds the files from fs.FS to the archive, as described
+// by [Writer.AddFS], modified by opts. A nil opts is equivalent to a zero
+// [AddFSOptions].
+//
+// Files are always added in lexical order, so the same files with
+// the same metadata always produce the same archive. To produce the
+// same archive from files with different metadata, such as from
+// checkouts of the same source tree, set opts.ModTime and opts.Normalize.
+func (tw *Writer) AddFSWithOptions(fsys fs.FS, opts *AddFSOptions) error {
+	if opts == nil {
+		opts = new(AddFSOptions)
+	}
 	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -425,6 +464,10 @@ func (tw *Writer) AddFS(fsys fs.FS) error {
 		} else if !typ.IsRegular() && typ != fs.ModeDir {
 			return errors.New("tar: cannot add non-regular file")
 		}
+		if opts.Normalize {
+			// Avoid looking up user and group names.
+			info = noNamesFileInfo{info}
+		}
 		h, err := FileInfoHeader(info, linkTarget)
 		if err != nil {
 			return err
@@ -433,6 +476,24 @@ func (tw *Writer) AddFS(fsys fs.FS) error {
 		if d.IsDir() {
 			h.Name += "/"
 		}
+		if !opts.ModTime.IsZero() {
+			h.ModTime = opts.ModTime
+		}
+		if opts.Normalize {
+			normalizeHeader(h)
+		}
+		if opts.Filter != nil {
+			keep, err := opts.Filter(name, h)
+			if err != nil {
+				return err
+			}
+			if !keep {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
 		if err := tw.WriteHeader(h); err != nil {
 			return err
 		}
@@ -449,6 +510,32 @@ func (tw *Writer) AddFS(fsys fs.FS) error {
 	})
 }
 
+// normalizeHeader removes system-dependent metadata from h,
+// as described by [AddFSOptions.Normalize].
+func normalizeHeader(h *Header) {
+	h.Uid, h.Gid = 0, 0
+	h.Uname, h.Gname = "", ""
+	h.AccessTime, h.ChangeTime = time.Time{}, time.Time{}
+	h.Xattrs = nil
+	h.PAXRecords = nil
+	switch {
+	case h.Typeflag == TypeSymlink:
+		h.Mode = 0o777
+	case h.Typeflag == TypeDir, h.Mode&0o111 != 0:
+		h.Mode = 0o755
+	default:
+		h.Mode = 0o644
+	}
+}
+
+// noNamesFileInfo is an fs.FileInfo with no user or group names.
+type noNamesFileInfo struct {
+	fs.FileInfo
+}
+
+func (noNamesFileInfo) Uname() (string, error) { return "", nil }
+func (noNamesFileInfo) Gname() (string, error) { return "", nil }
+
 // splitUSTARPath splits a path according to USTAR prefix and suffix rules.
 // If the path is not splittable, then it will return ("", "", false).
 func splitUSTARPath(name string) (prefix, suffix string, ok bool) {
@@ -14,6 +14,7 @@ import (
 	"maps"
 	"os"
 	"path"
+	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
@@ -1435,6 +1436,94 @@ func TestWriterAddFS(t *testing.T) {
 	}
 }
 
+func TestWriterAddFSWithOptions(t *testing.T) {
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	opts := &AddFSOptions{
+		ModTime:   mtime,
+		Normalize: true,
+		Filter: func(name string, hdr *Header) (bool, error) {
+			if name == "skipdir" || name == "skip.txt" {
+				return false, nil
+			}
+			return true, nil
+		},
+	}
+	build := func(mode fs.FileMode, t0 time.Time) []byte {
+		fsys := fstest.MapFS{
+			"dir":              {Mode: fs.ModeDir | mode | 0o100, ModTime: t0},
+			"dir/file.txt":     {Data: []byte("hello, world"), Mode: mode, ModTime: t0},
+			"dir/run.sh":       {Data: []byte("#!/bin/sh"), Mode: mode | 0o100, ModTime: t0},
+			"link":             {Data: []byte("dir/file.txt"), Mode: fs.ModeSymlink | mode, ModTime: t0},
+			"skip.txt":         {Data: []byte("skipped")},
+			"skipdir/file.txt": {Data: []byte("skipped")},
+		}
+		var buf bytes.Buffer
+		tw := NewWriter(&buf)
+		if err := tw.AddFSWithOptions(fsys, opts); err != nil {
+			t.Fatal(err)
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	b1 := build(0o600, time.Now())
+	b2 := build(0o664, time.Unix(0, 0))
+	if !bytes.Equal(b1, b2) {
+		t.Errorf("archives of files with different metadata differ")
+	}
+
+	want := []struct {
+		name string
+		mode int64
+	}{
+		{"dir/", 0o755},
+		{"dir/file.txt", 0o644},
+		{"dir/run.sh", 0o755},
+		{"link", 0o777},
+	}
+	tr := NewReader(bytes.NewReader(b1))
+	for _, w := range want {
+		hdr, err := tr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name != w.name || hdr.Mode != w.mode || hdr.Uid != 0 || hdr.Gid != 0 || hdr.Uname != "" {
+			t.Errorf("got %s mode %o uid %d gid %d uname %q, want %s mode %o owned by 0",
+				hdr.Name, hdr.Mode, hdr.Uid, hdr.Gid, hdr.Uname, w.name, w.mode)
+		}
+		if !hdr.ModTime.Equal(mtime) {
+			t.Errorf("%s: ModTime = %v, want %v", hdr.Name, hdr.ModTime, mtime)
+		}
+	}
+	if hdr, err := tr.Next(); err != io.EOF {
+		t.Errorf("unexpected entry %v, %v", hdr, err)
+	}
+}
+
+func TestWriterAddFSNormalizeOS(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "f"), []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	tw := NewWriter(&buf)
+	if err := tw.AddFSWithOptions(os.DirFS(dir), &AddFSOptions{Normalize: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	hdr, err := NewReader(&buf).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hdr.Uid != 0 || hdr.Gid != 0 || hdr.Uname != "" || hdr.Gname != "" || hdr.Mode != 0o644 {
+		t.Errorf("header not normalized: %+v", hdr)
+	}
+}
+
 func TestWriterAddFSNonRegularFiles(t *testing.T) {
 	fsys := fstest.MapFS{
 		"device":  {Data: []byte("hello"), Mode: 0755 | fs.ModeDevice},
@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zip
+
+import (
+	"archive/internal/extract"
+	"errors"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrExtractLimit is returned by [Reader.Extract] when the archive
+// exceeds one of the limits set in [ExtractOptions].
+var ErrExtractLimit = errors.New("zip: extraction limit exceeded")
+
+// maxLinkTarget is the longest symbolic link target
+// that Extract will read from an archive.
+const maxLinkTarget = 4096
+
+// ExtractOptions controls the behavior of [Reader.Extract].
+// The zero value extracts every file with no limits.
+type ExtractOptions struct {
+	// Filter, if non-nil, is called with a copy of the header of each
+	// file before it is extracted. Filter may modify the header to
+	// rewrite the file, by changing its Name or Modified fields or by
+	// calling [FileHeader.SetMode]; changes to other fields are ignored.
+	// If Filter returns false, the file is skipped.
+	// If Filter returns an error, Extract stops and returns that error.
+	Filter func(fh *FileHeader) (bool, error)
+
+	// MaxFileSize, if positive, is the largest uncompressed file
+	// that Extract will write.
+	MaxFileSize int64
+
+	// MaxTotalSize, if positive, is the largest total uncompressed size
+	// of all files that Extract will write.
+	MaxTotalSize int64
+
+	// MaxEntries, if positive, is the largest number of files
+	// in the archive, including skipped files.
+	MaxEntries int
+}
+
+// Extract creates the files, directories, and symbolic links
+// in the archive in root. Because all files are created through root,
+// no file in the archive can create or modify a file outside of it.
+//
+// Each file name must be a local, slash-separated path, as reported
+// by [filepath.IsLocal] after cleaning; otherwise Extract returns an
+// error wrapping [ErrInsecurePath]. The target of a symbolic link
+// must also be relative and must not refer to a location outside of
+// root, as determined lexically.
+//
+// Extract sets the permission bits and the modification time of each
+// file and directory from its header. A file with no permission bits,
+// such as one created on MS-DOS, gets mode 0o666, or 0o777 for a
+// directory. The permissions of files are subject to the umask.
+// Directory permissions and times are set once all files have been
+// extracted, so that a read-only directory in the archive can still
+// be populated. The setuid, setgid, and sticky bits are not restored,
+// and other special files are skipped.
+//
+// An existing file with the same name as a file in the archive is
+// replaced, without following it if it is a symbolic link.
+// Existing directories are reused.
+//
+// Extract checks the limits in opts against the data it decompresses,
+// not the sizes recorded in the archive. If the archive exceeds a limit,
+// Extract returns an error wrapping [ErrExtractLimit]. A nil opts is
+// equivalent to a zero [ExtractOptions].
+func (r *Reader) Extract(root *os.Root, opts *ExtractOptions) error {
+	if opts == nil {
+		opts = new(ExtractOptions)
+	}
+	if opts.MaxEntries > 0 && len(r.File) > opts.MaxEntries {
+		return &fs.PathError{Op: "extract", Path: r.File[opts.MaxEntries].Name, Err: ErrExtractLimit}
+	}
+	x := &extract.Extractor{
+		Root:         root,
+		MaxFileSize:  opts.MaxFileSize,
+		MaxTotalSize: opts.MaxTotalSize,
+	}
+	for _, f := range r.File {
+		fh := f.FileHeader
+		if opts.Filter != nil {
+			keep, err := opts.Filter(&fh)
+			if err != nil {
+				return err
+			}
+			if !keep {
+				continue
+			}
+		}
+		if err := extractFile(x, f, &fh); err != nil {
+			return err
+		}
+	}
+	return x.Finish()
+}
+
+// extractFile extracts f, using fh in place of its header.
+func extractFile(x *extract.Extractor, f *File, fh *FileHeader) error {
+	name, err := extract.Localize(fh.Name, ErrInsecurePath)
+	if err != nil {
+		return err
+	}
+	mode := fh.Mode()
+	perm := mode.Perm()
+
+	switch mode.Type() {
+	case fs.ModeDir:
+		if name == "." {
+			return nil
+		}
+		if perm == 0 {
+			perm = 0o777
+		}
+		return x.Mkdir(name, perm, time.Time{}, fh.Modified)
+
+	case 0:
+		if perm == 0 {
+			perm = 0o666
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		size := int64(min(f.UncompressedSize64, math.MaxInt64))
+		if err := x.WriteFile(name, fh.Name, perm, size, rc, ErrExtractLimit); err != nil {
+			return err
+		}
+		return x.Chtimes(name, time.Time{}, fh.Modified)
+
+	case fs.ModeSymlink:
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		target, err := io.ReadAll(io.LimitReader(rc, maxLinkTarget+1))
+		if err != nil {
+			return err
+		}
+		if len(target) > maxLinkTarget || !extract.LocalLinkTarget(name, string(target)) {
+			return &fs.PathError{Op: "extract", Path: fh.Name, Err: ErrInsecurePath}
+		}
+		if err := x.Replace(name); err != nil {
+			return err
+		}
+		return x.Root.Symlink(filepath.FromSlash(string(target)), name)
+	}
+	return nil
+}
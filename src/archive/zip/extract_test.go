@@ -0,0 +1,248 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zip
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"internal/testenv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type extractEntry struct {
+	name string
+	mode fs.FileMode
+	data string
+}
+
+// extractArchive writes entries to a zip archive and extracts it
+// into a new directory, returning the directory and the error from
+// Extract.
+func extractArchive(t *testing.T, entries []extractEntry, opts *ExtractOptions) (string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	return dir, extractArchiveTo(t, dir, entries, opts)
+}
+
+// extractArchiveTo writes entries to a zip archive and extracts it
+// into dir.
+func extractArchiveTo(t *testing.T, dir string, entries []extractEntry, opts *ExtractOptions) error {
+	t.Helper()
+	mtime := time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC)
+	var buf bytes.Buffer
+	zw := NewWriter(&buf)
+	for _, e := range entries {
+		fh := &FileHeader{Name: e.name, Method: Deflate, Modified: mtime}
+		if e.mode != 0 {
+			fh.SetMode(e.mode)
+		}
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return extractZip(t, dir, buf.Bytes(), opts)
+}
+
+// extractZip extracts the zip archive data into dir.
+func extractZip(t *testing.T, dir string, data []byte, opts *ExtractOptions) error {
+	t.Helper()
+	zr, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil && err != ErrInsecurePath {
+		t.Fatal(err)
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	return zr.Extract(root, opts)
+}
+
+func TestExtract(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+
+	dir, err := extractArchive(t, []extractEntry{
+		{name: "d/", mode: fs.ModeDir | 0o750},
+		{name: "d/f", mode: 0o640, data: "file"},
+		{name: "implicit/g", data: "g"},
+		{name: "d/link", mode: fs.ModeSymlink | 0o777, data: "../implicit/g"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"d/f":        "file",
+		"implicit/g": "g",
+		"d/link":     "g",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+		} else if string(got) != want {
+			t.Errorf("%s contains %q, want %q", name, got, want)
+		}
+	}
+	if fi, err := os.Lstat(filepath.Join(dir, "d", "link")); err != nil || fi.Mode().Type() != fs.ModeSymlink {
+		t.Errorf("d/link is not a symbolic link: %v", err)
+	}
+
+	mtime := time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC)
+	for _, name := range []string{"d", "d/f"} {
+		fi, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fi.ModTime().Equal(mtime) {
+			t.Errorf("%s: mtime = %v, want %v", name, fi.ModTime(), mtime)
+		}
+		if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+			continue
+		}
+		if perm := fi.Mode().Perm(); perm&^0o750 != 0 {
+			t.Errorf("%s: mode = %v, want subset of 0o750", name, perm)
+		}
+	}
+}
+
+func TestExtractInsecure(t *testing.T) {
+	for _, e := range []extractEntry{
+		{name: "../escape"},
+		{name: "/abs"},
+		{name: "a/../../escape"},
+		{name: "link", mode: fs.ModeSymlink | 0o777, data: "../outside"},
+		{name: "d/link", mode: fs.ModeSymlink | 0o777, data: "../../outside"},
+		{name: "link", mode: fs.ModeSymlink | 0o777, data: "/etc/passwd"},
+	} {
+		_, err := extractArchive(t, []extractEntry{e}, nil)
+		if !errors.Is(err, ErrInsecurePath) {
+			t.Errorf("Extract(%q -> %q) = %v, want ErrInsecurePath", e.name, e.data, err)
+		}
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	entries := []extractEntry{
+		{name: "a", data: "0123456789"},
+		{name: "b", data: "0123456789"},
+		{name: "c/"},
+	}
+	for _, tt := range []struct {
+		opts ExtractOptions
+		ok   bool
+	}{
+		{ExtractOptions{}, true},
+		{ExtractOptions{MaxFileSize: 10, MaxTotalSize: 20, MaxEntries: 3}, true},
+		{ExtractOptions{MaxFileSize: 9}, false},
+		{ExtractOptions{MaxTotalSize: 19}, false},
+		{ExtractOptions{MaxEntries: 2}, false},
+	} {
+		_, err := extractArchive(t, entries, &tt.opts)
+		if tt.ok && err != nil {
+			t.Errorf("Extract with %+v = %v, want nil", tt.opts, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrExtractLimit) {
+			t.Errorf("Extract with %+v = %v, want ErrExtractLimit", tt.opts, err)
+		}
+	}
+}
+
+// An entry larger than the limit does not replace an existing file.
+func TestExtractLimitsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries := []extractEntry{{name: "a", data: "0123456789"}}
+	err := extractArchiveTo(t, dir, entries, &ExtractOptions{MaxFileSize: 9})
+	if !errors.Is(err, ErrExtractLimit) {
+		t.Errorf("Extract = %v, want ErrExtractLimit", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a")); string(data) != "old" {
+		t.Errorf("existing file after Extract: %q, %v; want %q", data, err, "old")
+	}
+}
+
+// A file whose data is longer than its header's size is removed.
+func TestExtractLimitsHeaderSize(t *testing.T) {
+	const data = "0123456789"
+	var buf bytes.Buffer
+	zw := NewWriter(&buf)
+	w, err := zw.CreateRaw(&FileHeader{
+		Name:               "a",
+		Method:             Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(data)),
+		CompressedSize64:   uint64(len(data)),
+		UncompressedSize64: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := extractZip(t, dir, buf.Bytes(), &ExtractOptions{MaxFileSize: 5}); err == nil {
+		t.Errorf("Extract of file longer than its header's size succeeded")
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "a")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("file longer than its header's size was not removed: Lstat = %v", err)
+	}
+}
+
+func TestExtractFilter(t *testing.T) {
+	opts := &ExtractOptions{
+		Filter: func(fh *FileHeader) (bool, error) {
+			switch fh.Name {
+			case "skip":
+				return false, nil
+			case "rename":
+				fh.Name = "renamed"
+				fh.SetMode(0o600)
+			}
+			return true, nil
+		},
+	}
+	dir, err := extractArchive(t, []extractEntry{
+		{name: "skip", data: "s"},
+		{name: "rename", data: "r"},
+	}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"skip": false, "rename": false, "renamed": true} {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		if got := err == nil; got != want {
+			t.Errorf("%s exists = %v, want %v", name, got, want)
+		}
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "plan9" {
+		fi, err := os.Stat(filepath.Join(dir, "renamed"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm != 0o600 {
+			t.Errorf("renamed: mode = %v, want %v", perm, fs.FileMode(0o600))
+		}
+	}
+}
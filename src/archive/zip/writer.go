@@ -13,6 +13,7 @@ import (
 	"io"
 	"io/fs"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -321,6 +322,9 @@ func (w *Writer) CreateHeader(fh *FileHeader) (io.Writer, error) {
 
 	fh.CreatorVersion = fh.CreatorVersion&0xff00 | zipVersion20 // preserve compatibility byte
 	fh.ReaderVersion = zipVersion20
+	if fh.Method == Zstd {
+		fh.ReaderVersion = zipVersion63
+	}
 
 	// If Modified is set, this takes precedence over MS-DOS timestamp fields.
 	if !fh.Modified.IsZero() {
@@ -560,7 +564,46 @@ func (w *Writer) RegisterCompressor(method uint16, comp Compressor) {
 // AddFS adds the files from fs.FS to the archive.
 // It walks the directory tree starting at the root of the filesystem
 // adding each file to the zip using deflate while maintaining the directory structure.
+// It is equivalent to AddFSWithOptions(fsys, nil).
 func (w *Writer) AddFS(fsys fs.FS) error {
+	return w.AddFSWithOptions(fsys, nil)
+}
+
+// AddFSOptions controls the behavior of [Writer.AddFSWithOptions].
+type AddFSOptions struct {
+	// Filter, if non-nil, is called with the path and header of each
+	// file before it is added. Filter may modify the header, for example
+	// by setting its Method to select a different compression method
+	// for the file, such as [Store] for data that is already compressed.
+	// If Filter returns false, the file is not added; if the file is
+	// a directory, none of its contents are added either.
+	// If Filter returns an error, AddFSWithOptions stops and returns that error.
+	Filter func(name string, fh *FileHeader) (bool, error)
+
+	// ModTime, if non-zero, is used as the modification time
+	// of every file. As with [FileHeader.Modified], its location
+	// determines the time zone of the legacy MS-DOS time fields.
+	ModTime time.Time
+
+	// Normalize, if true, removes metadata that depends on the system
+	// the files are read from, so that the same files produce the same
+	// archive anywhere. It sets the mode of each directory and each file
+	// with any execute permission to 0o755, and of each other file to 0o644.
+	Normalize bool
+}
+
+// AddFSWithOptions adds the files from fs.FS to the archive, as described
+// by [Writer.AddFS], modified by opts. A nil opts is equivalent to a zero
+// [AddFSOptions].
+//
+// Files are always added in lexical order, so the same files with
+// the same metadata always produce the same archive. To produce the
+// same archive from files with different metadata, such as from
+// checkouts of the same source tree, set opts.ModTime and opts.Normalize.
+func (w *Writer) AddFSWithOptions(fsys fs.FS, opts *AddFSOptions) error {
+	if opts == nil {
+		opts = new(AddFSOptions)
+	}
 	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -584,6 +627,31 @@ func (w *Writer) AddFS(fsys fs.FS) error {
 			h.Name += "/"
 		}
 		h.Method = Deflate
+		if !opts.ModTime.IsZero() {
+			h.Modified = opts.ModTime
+		}
+		if opts.Normalize {
+			switch mode := info.Mode(); {
+			case mode.IsDir():
+				h.SetMode(fs.ModeDir | 0o755)
+			case mode&0o111 != 0:
+				h.SetMode(0o755)
+			default:
+				h.SetMode(0o644)
+			}
+		}
+		if opts.Filter != nil {
+			keep, err := opts.Filter(name, h)
+			if err != nil {
+				return err
+			}
+			if !keep {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
 		fw, err := w.CreateHeader(h)
 		if err != nil {
 			return err
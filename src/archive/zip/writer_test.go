@@ -72,6 +72,12 @@ var writeTests = []WriteTest{
 		Method: Deflate,
 		Mode:   0755 | fs.ModeDevice | fs.ModeCharDevice,
 	},
+	{
+		Name:   "zstd",
+		Data:   []byte("zstd compressed file. zstd compressed file. zstd compressed file."),
+		Method: Zstd,
+		Mode:   0644,
+	},
 }
 
 func TestWriter(t *testing.T) {
@@ -551,6 +557,38 @@ func testCreate(t *testing.T, w *Writer, wt *WriteTest) {
 	}
 }
 
+func TestWriterZstd(t *testing.T) {
+	data := bytes.Repeat([]byte("zstd in a zip file. "), 1000)
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	fw, err := w.CreateHeader(&FileHeader{Name: "z.txt", Method: Zstd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := r.File[0]
+	if f.Method != Zstd {
+		t.Errorf("Method = %d, want %d", f.Method, Zstd)
+	}
+	if f.ReaderVersion != zipVersion63 {
+		t.Errorf("ReaderVersion = %d, want %d", f.ReaderVersion, zipVersion63)
+	}
+	if f.CompressedSize64 >= f.UncompressedSize64 {
+		t.Errorf("compressed size %d not less than uncompressed size %d", f.CompressedSize64, f.UncompressedSize64)
+	}
+	testReadFile(t, f, &WriteTest{Name: "z.txt", Data: data, Mode: 0666})
+}
+
 func testReadFile(t *testing.T, f *File, wt *WriteTest) {
 	if f.Name != wt.Name {
 		t.Fatalf("File name: got %q, want %q", f.Name, wt.Name)
@@ -649,6 +687,75 @@ func TestWriterAddFS(t *testing.T) {
 	}
 }
 
+func TestWriterAddFSWithOptions(t *testing.T) {
+	mtime := time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC)
+	opts := &AddFSOptions{
+		ModTime:   mtime,
+		Normalize: true,
+		Filter: func(name string, fh *FileHeader) (bool, error) {
+			switch name {
+			case "skipdir", "skip.txt":
+				return false, nil
+			case "stored.txt":
+				fh.Method = Store
+			}
+			return true, nil
+		},
+	}
+	build := func(mode fs.FileMode, t0 time.Time) []byte {
+		fsys := fstest.MapFS{
+			"dir":              {Mode: fs.ModeDir | mode | 0o100, ModTime: t0},
+			"dir/file.txt":     {Data: []byte("hello, world"), Mode: mode, ModTime: t0},
+			"dir/run.sh":       {Data: []byte("#!/bin/sh"), Mode: mode | 0o100, ModTime: t0},
+			"skip.txt":         {Data: []byte("skipped")},
+			"skipdir/file.txt": {Data: []byte("skipped")},
+			"stored.txt":       {Data: bytes.Repeat([]byte("stored "), 100), Mode: mode, ModTime: t0},
+		}
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		if err := w.AddFSWithOptions(fsys, opts); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	b1 := build(0o600, time.Now())
+	b2 := build(0o664, time.Unix(0, 0))
+	if !bytes.Equal(b1, b2) {
+		t.Errorf("archives of files with different metadata differ")
+	}
+
+	r, err := NewReader(bytes.NewReader(b1), int64(len(b1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name   string
+		mode   fs.FileMode
+		method uint16
+	}{
+		{"dir/", fs.ModeDir | 0o755, Store},
+		{"dir/file.txt", 0o644, Deflate},
+		{"dir/run.sh", 0o755, Deflate},
+		{"stored.txt", 0o644, Store},
+	}
+	if len(r.File) != len(want) {
+		t.Fatalf("archive has %d files, want %d", len(r.File), len(want))
+	}
+	for i, f := range r.File {
+		w := want[i]
+		if f.Name != w.name || f.Mode() != w.mode || f.Method != w.method {
+			t.Errorf("file %d: got %s %v method %d, want %s %v method %d", i, f.Name, f.Mode(), f.Method, w.name, w.mode, w.method)
+		}
+		if !f.Modified.Equal(mtime) {
+			t.Errorf("%s: Modified = %v, want %v", f.Name, f.Modified, mtime)
+		}
+	}
+}
+
 func TestIssue61875(t *testing.T) {
 	buf := new(bytes.Buffer)
 	w := NewWriter(buf)
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"iter"
 	"unicode/utf8"
 )
 
@@ -239,6 +240,25 @@ func (s *Scanner) Scan() bool {
 	}
 }
 
+// Tokens returns an iterator over the remaining tokens in the input,
+// calling [Scanner.Scan] to advance. Each token is yielded with a nil
+// error, as returned by [Scanner.Bytes]: the underlying array may point
+// to data that will be overwritten by the next iteration.
+// If scanning stops with an error, the iteration ends by yielding
+// a nil token and the error reported by [Scanner.Err].
+func (s *Scanner) Tokens() iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		for s.Scan() {
+			if !yield(s.Bytes(), nil) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
 // advance consumes n bytes of the buffer. It reports whether the advance was legal.
 func (s *Scanner) advance(n int) bool {
 	if n < 0 {
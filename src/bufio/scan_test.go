@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"slices"
 	"strings"
 	"testing"
 	"unicode"
@@ -594,3 +595,37 @@ func TestLargeReader(t *testing.T) {
 		t.Errorf("scanner.Err: got %v, want %v", got, want)
 	}
 }
+
+func TestScanTokens(t *testing.T) {
+	s := NewScanner(strings.NewReader("one two  three\n"))
+	s.Split(ScanWords)
+	var got []string
+	for tok, err := range s.Tokens() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(tok))
+	}
+	if want := []string{"one", "two", "three"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// An error is yielded at the end of the iteration.
+	s = NewScanner(largeReader{})
+	var lastErr error
+	for _, err := range s.Tokens() {
+		lastErr = err
+	}
+	if lastErr != ErrBadReadCount {
+		t.Errorf("last error = %v, want %v", lastErr, ErrBadReadCount)
+	}
+
+	// Breaking out of the loop leaves the remaining tokens.
+	s = NewScanner(strings.NewReader("a\nb\nc\n"))
+	for range s.Tokens() {
+		break
+	}
+	if !s.Scan() || s.Text() != "b" {
+		t.Errorf("Scan after break = %q, want %q", s.Text(), "b")
+	}
+}
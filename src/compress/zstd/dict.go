@@ -0,0 +1,97 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// dictMagic is the magic number at the start of a formatted dictionary.
+// RFC 5.
+const dictMagic = 0xec30a437
+
+// dictionary is a parsed zstd dictionary. RFC 5.
+type dictionary struct {
+	// The dictionary ID. This is zero for a raw content dictionary.
+	id uint32
+
+	// The content used as the initial history of each frame.
+	content []byte
+
+	// Whether the dictionary has entropy tables.
+	// This is false for a raw content dictionary.
+	hasTables bool
+
+	// The Huffman table used for literals.
+	huffmanTable     []uint16
+	huffmanTableBits int
+
+	// The FSE tables used for sequences.
+	seqTables    [3][]fseBaselineEntry
+	seqTableBits [3]uint8
+
+	// The initial repeated offsets.
+	repeatedOffsets [3]uint32
+}
+
+// parseDict parses a dictionary. A dictionary that does not start
+// with the dictionary magic number is treated as raw content.
+// The returned dictionary refers to, but does not modify, b.
+func (r *Reader) parseDict(b []byte) (*dictionary, error) {
+	d := &dictionary{
+		repeatedOffsets: [3]uint32{1, 4, 8},
+	}
+	if len(b) < 8 || binary.LittleEndian.Uint32(b) != dictMagic {
+		d.content = b
+		return d, nil
+	}
+
+	d.id = binary.LittleEndian.Uint32(b[4:])
+	if d.id == 0 {
+		return nil, errors.New("zstd: invalid dictionary: zero dictionary ID")
+	}
+
+	// The entropy tables use the same format as a compressed block.
+	// RFC 5, Entropy_Tables.
+	data := block(b[8:])
+
+	table := make([]uint16, 1<<maxHuffmanBits)
+	tableBits, off, err := r.readHuff(data, 0, table)
+	if err != nil {
+		return nil, fmt.Errorf("zstd: invalid dictionary: %w", err)
+	}
+	d.huffmanTable = table
+	d.huffmanTableBits = tableBits
+
+	for _, kind := range [...]seqCode{seqOffset, seqMatch, seqLiteral} {
+		off, err = r.setSeqTable(data, off, kind, 2)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: invalid dictionary: %w", err)
+		}
+		// Copy the table, as setSeqTable uses buffers
+		// that later blocks will overwrite.
+		d.seqTables[kind] = append([]fseBaselineEntry(nil), r.seqTables[kind]...)
+		d.seqTableBits[kind] = r.seqTableBits[kind]
+	}
+
+	if len(data)-off < 12 {
+		return nil, errors.New("zstd: invalid dictionary: missing repeated offsets")
+	}
+	for i := range d.repeatedOffsets {
+		d.repeatedOffsets[i] = binary.LittleEndian.Uint32(data[off:])
+		off += 4
+	}
+	d.content = data[off:]
+	for _, ro := range d.repeatedOffsets {
+		if ro == 0 || uint64(ro) > uint64(len(d.content)) {
+			return nil, errors.New("zstd: invalid dictionary: repeated offset out of range")
+		}
+	}
+
+	d.hasTables = true
+	return d, nil
+}
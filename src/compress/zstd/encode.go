@@ -0,0 +1,325 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"math/bits"
+)
+
+// maxBlockSize is the largest amount of data in a single block.
+// RFC 3.1.1.2.3.
+const maxBlockSize = 128 << 10
+
+// seq is a single sequence: some literals followed by a match.
+type seq struct {
+	litLen   uint32 // number of literals
+	matchLen uint32 // length of match; at least minMatch
+	offset   uint32 // distance back to the start of the match
+}
+
+// blockEncoder encodes the literals and sequences of a block.
+type blockEncoder struct {
+	huff  huffEncoder
+	fse   [3]fseEncoder // indexed by seqCode
+	codes [3][]uint8    // per-sequence codes, indexed by seqCode
+}
+
+// appendCompressedBlock appends the content of a Compressed_Block
+// holding lits and seqs to dst. RFC 3.1.1.3.
+func (e *blockEncoder) appendCompressedBlock(dst []byte, lits []byte, seqs []seq) []byte {
+	dst = e.appendLiterals(dst, lits)
+	return e.appendSequences(dst, seqs)
+}
+
+// appendLiterals appends the Literals_Section for lits to dst,
+// choosing the smallest representation. RFC 3.1.1.3.1.
+func (e *blockEncoder) appendLiterals(dst []byte, lits []byte) []byte {
+	if len(lits) < 32 {
+		return appendRawLiterals(dst, lits)
+	}
+
+	var hist [256]uint32
+	for _, c := range lits {
+		hist[c]++
+	}
+	maxSym, distinct := 0, 0
+	for i, c := range hist {
+		if c > 0 {
+			maxSym = i
+			distinct++
+		}
+	}
+	if distinct == 1 {
+		return appendLiteralsHeader(dst, 1, len(lits), lits[0])
+	}
+
+	h := &e.huff
+	h.build(&hist, maxSym)
+
+	streams := 1
+	if len(lits) > 1023 {
+		streams = 4
+	}
+	// Estimate whether Huffman coding is worthwhile
+	// before doing the work.
+	estimate := h.cost(&hist) + 6*(streams-1)
+	if estimate+(maxSym+1)/2+8 >= len(lits) {
+		return appendRawLiterals(dst, lits)
+	}
+
+	// Build the compressed literals after the largest possible header,
+	// and move them into place once the size is known.
+	const maxHeader = 5
+	start := len(dst)
+	out := append(dst, make([]byte, maxHeader)...)
+	out, ok := h.appendTable(out)
+	if !ok {
+		return appendRawLiterals(dst[:start], lits)
+	}
+	if streams == 1 {
+		out = h.appendStream(out, lits)
+	} else {
+		jump := len(out)
+		out = append(out, 0, 0, 0, 0, 0, 0)
+		size := (len(lits) + 3) / 4
+		for i := 0; i < 4; i++ {
+			end := min((i+1)*size, len(lits))
+			streamStart := len(out)
+			out = h.appendStream(out, lits[i*size:end])
+			if i < 3 {
+				n := len(out) - streamStart
+				if n > 0xffff {
+					return appendRawLiterals(dst[:start], lits)
+				}
+				out[jump+2*i] = byte(n)
+				out[jump+2*i+1] = byte(n >> 8)
+			}
+		}
+	}
+
+	compressed := len(out) - start - maxHeader
+	if streams == 1 && compressed > 1023 || compressed+maxHeader >= len(lits) {
+		return appendRawLiterals(out[:start], lits)
+	}
+
+	var hdr [maxHeader]byte
+	n := copy(out[start:], appendCompressedLiteralsHeader(hdr[:0], len(lits), compressed, streams))
+	copy(out[start+n:], out[start+maxHeader:])
+	return out[:len(out)-(maxHeader-n)]
+}
+
+// appendRawLiterals appends a Raw_Literals_Block to dst.
+func appendRawLiterals(dst, lits []byte) []byte {
+	dst = appendLiteralsHeader(dst, 0, len(lits), 0)
+	return append(dst, lits...)
+}
+
+// appendLiteralsHeader appends the header of a
+// Raw_Literals_Block (typ 0) or RLE_Literals_Block (typ 1)
+// to dst, followed by rle for a RLE_Literals_Block.
+// RFC 3.1.1.3.1.1.
+func appendLiteralsHeader(dst []byte, typ byte, size int, rle byte) []byte {
+	switch {
+	case size < 1<<5:
+		dst = append(dst, typ|byte(size)<<3)
+	case size < 1<<12:
+		dst = append(dst, typ|1<<2|byte(size)<<4, byte(size>>4))
+	default:
+		dst = append(dst, typ|3<<2|byte(size)<<4, byte(size>>4), byte(size>>12))
+	}
+	if typ == 1 {
+		dst = append(dst, rle)
+	}
+	return dst
+}
+
+// appendCompressedLiteralsHeader appends the header of a
+// Compressed_Literals_Block to dst. RFC 3.1.1.3.1.1.
+func appendCompressedLiteralsHeader(dst []byte, regenerated, compressed, streams int) []byte {
+	const typ = 2
+	switch {
+	case regenerated < 1<<10 && compressed < 1<<10:
+		var sf uint32
+		if streams == 4 {
+			sf = 1
+		}
+		v := typ | sf<<2 | uint32(regenerated)<<4 | uint32(compressed)<<14
+		return append(dst, byte(v), byte(v>>8), byte(v>>16))
+	case regenerated < 1<<14 && compressed < 1<<14:
+		v := typ | 2<<2 | uint32(regenerated)<<4 | uint32(compressed)<<18
+		return append(dst, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+	default:
+		v := typ | 3<<2 | uint64(regenerated)<<4 | uint64(compressed)<<22
+		return append(dst, byte(v), byte(v>>8), byte(v>>16), byte(v>>24), byte(v>>32))
+	}
+}
+
+// literalLengthCode returns the code for a literal length.
+// RFC 3.1.1.3.2.1.1.
+func literalLengthCode(ll uint32) uint8 {
+	if ll < literalLengthOffset {
+		return uint8(ll)
+	}
+	if ll >= 64 {
+		return uint8(bits.Len32(ll) + 18)
+	}
+	i := 8
+	for literalLengthBase[i]&0xffffff > ll {
+		i--
+	}
+	return uint8(literalLengthOffset + i)
+}
+
+// matchLengthCode returns the code for a match length.
+// RFC 3.1.1.3.2.1.1.
+func matchLengthCode(ml uint32) uint8 {
+	mb := ml - 3
+	if mb < matchLengthOffset {
+		return uint8(mb)
+	}
+	if mb >= 128 {
+		return uint8(bits.Len32(mb) + 35)
+	}
+	i := 10
+	for matchLengthBase[i]&0xffffff > ml {
+		i--
+	}
+	return uint8(matchLengthOffset + i)
+}
+
+// seqCodeExtra returns the baseline and number of extra bits for
+// a literal length or match length code.
+func seqCodeExtra(kind seqCode, code uint8) (baseline uint32, nbits uint8) {
+	switch kind {
+	case seqLiteral:
+		if code < literalLengthOffset {
+			return uint32(code), 0
+		}
+		v := literalLengthBase[code-literalLengthOffset]
+		return v & 0xffffff, uint8(v >> 24)
+	case seqMatch:
+		if code < matchLengthOffset {
+			return uint32(code) + 3, 0
+		}
+		v := matchLengthBase[code-matchLengthOffset]
+		return v & 0xffffff, uint8(v >> 24)
+	}
+	panic("unreachable")
+}
+
+// appendSequences appends the Sequences_Section for seqs to dst.
+// RFC 3.1.1.3.2.
+func (e *blockEncoder) appendSequences(dst []byte, seqs []seq) []byte {
+	n := len(seqs)
+	switch {
+	case n < 128:
+		dst = append(dst, byte(n))
+	case n < 0x7f00:
+		dst = append(dst, byte(n>>8)+128, byte(n))
+	default:
+		dst = append(dst, 255, byte(n-0x7f00), byte((n-0x7f00)>>8))
+	}
+	if n == 0 {
+		return dst
+	}
+
+	for kind := range e.codes {
+		e.codes[kind] = e.codes[kind][:0]
+	}
+	for _, s := range seqs {
+		e.codes[seqLiteral] = append(e.codes[seqLiteral], literalLengthCode(s.litLen))
+		e.codes[seqOffset] = append(e.codes[seqOffset], uint8(bits.Len32(s.offset+3)-1))
+		e.codes[seqMatch] = append(e.codes[seqMatch], matchLengthCode(s.matchLen))
+	}
+
+	modesOff := len(dst)
+	dst = append(dst, 0)
+	var modes byte
+	for _, kind := range [...]seqCode{seqLiteral, seqOffset, seqMatch} {
+		var mode byte
+		dst, mode = e.chooseTable(dst, kind)
+		modes |= mode << (6 - 2*kind)
+	}
+	dst[modesOff] = modes
+
+	// Write the sequences in reverse order,
+	// as the decoder reads the bit stream backward.
+	// See execSeqs for the order in which values are read.
+	bw := bitWriter{out: dst}
+	llEnc, ofEnc, mlEnc := &e.fse[seqLiteral], &e.fse[seqOffset], &e.fse[seqMatch]
+	llCodes, ofCodes, mlCodes := e.codes[seqLiteral], e.codes[seqOffset], e.codes[seqMatch]
+	last := n - 1
+	mlState := mlEnc.initState(mlCodes[last])
+	ofState := ofEnc.initState(ofCodes[last])
+	llState := llEnc.initState(llCodes[last])
+	for i := last; i >= 0; i-- {
+		if i < last {
+			ofState = ofEnc.encode(&bw, ofState, ofCodes[i])
+			mlState = mlEnc.encode(&bw, mlState, mlCodes[i])
+			llState = llEnc.encode(&bw, llState, llCodes[i])
+		}
+		s := &seqs[i]
+		base, nbits := seqCodeExtra(seqLiteral, llCodes[i])
+		bw.addBits(s.litLen-base, nbits)
+		base, nbits = seqCodeExtra(seqMatch, mlCodes[i])
+		bw.addBits(s.matchLen-base, nbits)
+		bw.addBits(s.offset+3, ofCodes[i])
+	}
+	mlEnc.flushState(&bw, mlState)
+	ofEnc.flushState(&bw, ofState)
+	llEnc.flushState(&bw, llState)
+	return bw.closeReverse()
+}
+
+// chooseTable sets e.fse[kind] to the table to use for the codes
+// of that kind, appending any table description to dst.
+// It returns the Compression_Mode. RFC 3.1.1.3.2.1.2.
+func (e *blockEncoder) chooseTable(dst []byte, kind seqCode) ([]byte, byte) {
+	info := &seqCodeInfo[kind]
+	var dist []int16
+	switch kind {
+	case seqLiteral:
+		dist = literalPredefinedDistribution
+	case seqOffset:
+		dist = offsetPredefinedDistribution
+	case seqMatch:
+		dist = matchPredefinedDistribution
+	}
+
+	codes := e.codes[kind]
+	var counts [53]uint32
+	maxSym := 0
+	for _, c := range codes {
+		counts[c]++
+		maxSym = max(maxSym, int(c))
+	}
+	enc := &e.fse[kind]
+
+	if counts[maxSym] == uint32(len(codes)) && len(codes) > 2 {
+		enc.setRLE(uint8(maxSym))
+		return append(dst, uint8(maxSym)), 1
+	}
+
+	enc.setPredefined(dist, info.predefTableBits)
+	predefCost := enc.cost(counts[:maxSym+1])
+	if len(codes) < 64 && predefCost >= 0 {
+		return dst, 0
+	}
+
+	// Try a table fitted to the codes,
+	// keeping it if it is cheaper including its description.
+	var custom fseEncoder
+	tableBits := optimalTableBits(len(codes), maxSym, info.maxBits)
+	custom.normalize(counts[:maxSym+1], len(codes), tableBits)
+	start := len(dst)
+	dst = custom.appendHeader(dst)
+	customCost := custom.cost(counts[:maxSym+1]) + 8*(len(dst)-start)
+	if predefCost >= 0 && predefCost <= customCost {
+		return dst[:start], 0
+	}
+	custom.build()
+	*enc = custom
+	return dst, 2
+}
@@ -0,0 +1,317 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"math"
+	"math/bits"
+)
+
+// bitWriter writes a bit stream going forward.
+// Bits are packed starting at the low bit of each byte.
+// A stream that is read in reverse, as by reverseBitReader,
+// is terminated by a 1 bit; see closeReverse.
+type bitWriter struct {
+	out   []byte // completed bytes
+	bits  uint64 // bits not yet written to out
+	nbits uint8  // number of valid bits in bits
+}
+
+// addBits adds the low n bits of v to the stream. n must be at most 32.
+func (bw *bitWriter) addBits(v uint32, n uint8) {
+	if n == 0 {
+		return
+	}
+	if bw.nbits+n > 64 {
+		bw.flush()
+	}
+	bw.bits |= (uint64(v) & (1<<n - 1)) << bw.nbits
+	bw.nbits += n
+}
+
+// flush moves all complete bytes to out.
+func (bw *bitWriter) flush() {
+	for bw.nbits >= 8 {
+		bw.out = append(bw.out, byte(bw.bits))
+		bw.bits >>= 8
+		bw.nbits -= 8
+	}
+}
+
+// close writes any remaining bits, padding the last byte with zeroes.
+func (bw *bitWriter) close() []byte {
+	bw.flush()
+	if bw.nbits > 0 {
+		bw.out = append(bw.out, byte(bw.bits))
+		bw.bits = 0
+		bw.nbits = 0
+	}
+	return bw.out
+}
+
+// closeReverse terminates a stream that will be read in reverse.
+// The final 1 bit tells the reader where the stream starts.
+func (bw *bitWriter) closeReverse() []byte {
+	bw.addBits(1, 1)
+	return bw.close()
+}
+
+// maxFSETableBits is the largest table size we use for any FSE encoding.
+const maxFSETableBits = 9
+
+// fseSymbolTransform describes how to encode one symbol with an FSE table.
+type fseSymbolTransform struct {
+	deltaNbBits    uint32
+	deltaFindState int32
+}
+
+// fseEncoder holds an FSE encoding table.
+// The table is the inverse of the decoding table built by buildFSE.
+type fseEncoder struct {
+	tableBits  uint8
+	norm       [256]int16 // normalized counts; -1 is a low probability symbol
+	maxSym     int        // largest symbol in norm
+	symbolTT   [256]fseSymbolTransform
+	stateTable [1 << maxFSETableBits]uint16
+}
+
+// build builds the encoding table from e.norm, e.maxSym and e.tableBits.
+// A table with zero bits encodes a single symbol using no bits,
+// as for RLE_Mode.
+func (e *fseEncoder) build() {
+	tableSize := 1 << e.tableBits
+	mask := tableSize - 1
+	highThreshold := tableSize - 1
+	norm := e.norm[:e.maxSym+1]
+
+	// Place symbols in the table exactly as buildFSE does.
+	var tableSymbol [1 << maxFSETableBits]uint8
+	var cumul [257]int
+	for i, n := range norm {
+		if n == -1 {
+			cumul[i+1] = cumul[i] + 1
+			tableSymbol[highThreshold] = uint8(i)
+			highThreshold--
+		} else {
+			cumul[i+1] = cumul[i] + int(n)
+		}
+	}
+	pos := 0
+	step := (tableSize >> 1) + (tableSize >> 3) + 3
+	for i, n := range norm {
+		for j := 0; j < int(n); j++ {
+			tableSymbol[pos] = uint8(i)
+			pos = (pos + step) & mask
+			for pos > highThreshold {
+				pos = (pos + step) & mask
+			}
+		}
+	}
+
+	// The state table maps the position of a symbol
+	// to the decoder state that produces it.
+	for i := 0; i < tableSize; i++ {
+		sym := tableSymbol[i]
+		e.stateTable[cumul[sym]] = uint16(tableSize + i)
+		cumul[sym]++
+	}
+
+	total := int32(0)
+	for i, n := range norm {
+		tt := &e.symbolTT[i]
+		switch n {
+		case 0:
+			tt.deltaNbBits = (uint32(e.tableBits+1) << 16) - uint32(tableSize)
+			tt.deltaFindState = 0
+		case -1, 1:
+			tt.deltaNbBits = (uint32(e.tableBits) << 16) - uint32(tableSize)
+			tt.deltaFindState = total - 1
+			total++
+		default:
+			maxBitsOut := uint32(e.tableBits) - uint32(bits.Len16(uint16(n-1))-1)
+			minStatePlus := uint32(n) << maxBitsOut
+			tt.deltaNbBits = (maxBitsOut << 16) - minStatePlus
+			tt.deltaFindState = total - int32(n)
+			total += int32(n)
+		}
+	}
+}
+
+// initState returns the initial encoder state for sym.
+// Since the decoder reads states in reverse order,
+// this is the state for the last symbol encoded.
+func (e *fseEncoder) initState(sym uint8) uint32 {
+	tt := &e.symbolTT[sym]
+	nbBitsOut := (tt.deltaNbBits + (1 << 15)) >> 16
+	v := (nbBitsOut << 16) - tt.deltaNbBits
+	return uint32(e.stateTable[int32(v>>nbBitsOut)+tt.deltaFindState])
+}
+
+// encode writes the bits that lead from state to sym,
+// and returns the new state.
+func (e *fseEncoder) encode(bw *bitWriter, state uint32, sym uint8) uint32 {
+	tt := &e.symbolTT[sym]
+	nbBitsOut := (state + tt.deltaNbBits) >> 16
+	bw.addBits(state, uint8(nbBitsOut))
+	return uint32(e.stateTable[int32(state>>nbBitsOut)+tt.deltaFindState])
+}
+
+// flushState writes the final state, which the decoder reads first.
+func (e *fseEncoder) flushState(bw *bitWriter, state uint32) {
+	bw.addBits(state, e.tableBits)
+}
+
+// setPredefined sets e to encode using a predefined distribution.
+func (e *fseEncoder) setPredefined(dist []int16, tableBits int) {
+	copy(e.norm[:], dist)
+	e.maxSym = len(dist) - 1
+	e.tableBits = uint8(tableBits)
+	e.build()
+}
+
+// setRLE sets e to encode a single symbol using no bits.
+func (e *fseEncoder) setRLE(sym uint8) {
+	clear(e.norm[:int(sym)+1])
+	e.norm[sym] = 1
+	e.maxSym = int(sym)
+	e.tableBits = 0
+	e.build()
+}
+
+// optimalTableBits returns the number of bits to use for an FSE table
+// encoding total symbols whose largest value is maxSym.
+// The result is between 5 and maxBits.
+func optimalTableBits(total, maxSym, maxBits int) int {
+	srcBits := bits.Len(uint(total-1)) - 3
+	minBits := min(bits.Len(uint(total)), bits.Len(uint(maxSym))+1)
+	tableBits := min(maxBits, srcBits)
+	tableBits = max(tableBits, minBits)
+	return min(max(tableBits, 5), maxBits)
+}
+
+// normalize sets e.norm to a distribution that approximates counts,
+// with a sum of 1<<tableBits. The caller must ensure that
+// at least two symbols have a non-zero count, and that the table is
+// large enough to give every symbol at least one state.
+func (e *fseEncoder) normalize(counts []uint32, total int, tableBits int) {
+	tableSize := 1 << tableBits
+	lowThreshold := uint64(total) >> tableBits
+	remaining := tableSize
+	largest, largestCount := 0, uint32(0)
+	for i, c := range counts {
+		if c > largestCount {
+			largest, largestCount = i, c
+		}
+		switch {
+		case c == 0:
+			e.norm[i] = 0
+		case uint64(c) <= lowThreshold:
+			e.norm[i] = -1
+			remaining--
+		default:
+			n := int(uint64(c) << tableBits / uint64(total))
+			e.norm[i] = int16(n)
+			remaining -= n
+		}
+	}
+	e.maxSym = len(counts) - 1
+	e.tableBits = uint8(tableBits)
+
+	if remaining >= 0 {
+		e.norm[largest] += int16(remaining)
+		return
+	}
+
+	// The low probability symbols took too many states.
+	// Take states from the most probable symbols.
+	for remaining < 0 {
+		best := -1
+		for i := range counts {
+			if e.norm[i] > 1 && (best < 0 || e.norm[i] > e.norm[best]) {
+				best = i
+			}
+		}
+		e.norm[best]--
+		remaining++
+	}
+}
+
+// appendHeader appends the FSE table description to dst. RFC 4.1.1.
+func (e *fseEncoder) appendHeader(dst []byte) []byte {
+	bw := bitWriter{out: dst}
+	bw.addBits(uint32(e.tableBits)-5, 4)
+
+	tableSize := 1 << e.tableBits
+	remaining := tableSize + 1
+	threshold := tableSize
+	nbBits := uint8(e.tableBits) + 1
+	prev0 := false
+	for sym := 0; sym <= e.maxSym && remaining > 1; {
+		if prev0 {
+			// Write repeat flags for runs of zero probability.
+			start := sym
+			for sym <= e.maxSym && e.norm[sym] == 0 {
+				sym++
+			}
+			if sym > e.maxSym {
+				break
+			}
+			for sym >= start+3 {
+				bw.addBits(3, 2)
+				start += 3
+			}
+			bw.addBits(uint32(sym-start), 2)
+		}
+
+		count := int(e.norm[sym])
+		sym++
+		max := (2*threshold - 1) - remaining
+		if count < 0 {
+			remaining += count
+		} else {
+			remaining -= count
+		}
+		count++
+		if count >= threshold {
+			count += max
+		}
+		if count < max {
+			bw.addBits(uint32(count), nbBits-1)
+		} else {
+			bw.addBits(uint32(count), nbBits)
+		}
+		prev0 = count == 1
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+	return bw.close()
+}
+
+// cost returns the approximate number of bits needed to encode
+// counts using the distribution in e, or -1 if some symbol
+// in counts can not be encoded.
+func (e *fseEncoder) cost(counts []uint32) int {
+	if len(counts) > e.maxSym+1 {
+		return -1
+	}
+	tableSize := float64(int(1) << e.tableBits)
+	var c float64
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		norm := e.norm[i]
+		switch norm {
+		case 0:
+			return -1
+		case -1:
+			norm = 1
+		}
+		c += float64(n) * math.Log2(tableSize/float64(norm))
+	}
+	return int(c) + 1
+}
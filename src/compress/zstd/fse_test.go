@@ -9,30 +9,6 @@ import (
 	"testing"
 )
 
-// literalPredefinedDistribution is the predefined distribution table
-// for literal lengths. RFC 3.1.1.3.2.2.1.
-var literalPredefinedDistribution = []int16{
-	4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1,
-	2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
-	-1, -1, -1, -1,
-}
-
-// offsetPredefinedDistribution is the predefined distribution table
-// for offsets. RFC 3.1.1.3.2.2.3.
-var offsetPredefinedDistribution = []int16{
-	1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
-}
-
-// matchPredefinedDistribution is the predefined distribution table
-// for match lengths. RFC 3.1.1.3.2.2.2.
-var matchPredefinedDistribution = []int16{
-	1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1,
-	-1, -1, -1, -1, -1,
-}
-
 // TestPredefinedTables verifies that we can generate the predefined
 // literal/offset/match tables from the input data in RFC 8878.
 // This serves as a test of the predefined tables, and also of buildFSE
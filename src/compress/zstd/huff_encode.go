@@ -0,0 +1,273 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"sort"
+)
+
+// huffEncoder holds a Huffman code for literals.
+type huffEncoder struct {
+	lens      [256]uint8  // code length of each symbol, 0 if unused
+	codes     [256]uint16 // code of each symbol
+	maxSym    int         // largest symbol with a code
+	tableBits int         // length of the longest code
+
+	// Scratch space for building the code.
+	syms    [256]uint8
+	counts  [511]uint32
+	parents [511]int16
+	fse     fseEncoder
+}
+
+// build builds a Huffman code for the symbols counted in hist,
+// whose largest symbol is maxSym. At least two symbols must have
+// a non-zero count.
+func (h *huffEncoder) build(hist *[256]uint32, maxSym int) {
+	h.maxSym = maxSym
+	clear(h.lens[:])
+
+	// Sort the symbols by increasing count.
+	syms := h.syms[:0]
+	for i, c := range hist[:maxSym+1] {
+		if c > 0 {
+			syms = append(syms, uint8(i))
+		}
+	}
+	sort.SliceStable(syms, func(i, j int) bool {
+		return hist[syms[i]] < hist[syms[j]]
+	})
+
+	// Build the tree using two queues: the leaves in order,
+	// and the internal nodes, which are created in order.
+	n := len(syms)
+	for i, s := range syms {
+		h.counts[i] = hist[s]
+	}
+	leaf, inner := 0, n
+	for next := n; next < 2*n-1; next++ {
+		var pick [2]int
+		for j := range pick {
+			if leaf < n && (inner >= next || h.counts[leaf] <= h.counts[inner]) {
+				pick[j] = leaf
+				leaf++
+			} else {
+				pick[j] = inner
+				inner++
+			}
+		}
+		h.counts[next] = h.counts[pick[0]] + h.counts[pick[1]]
+		h.parents[pick[0]] = int16(next)
+		h.parents[pick[1]] = int16(next)
+	}
+
+	// Compute the depth of each node; the root is the last node.
+	// Reuse counts to hold the depths.
+	h.counts[2*n-2] = 0
+	for i := 2*n - 3; i >= 0; i-- {
+		h.counts[i] = h.counts[h.parents[i]] + 1
+	}
+	for i, s := range syms {
+		h.lens[s] = uint8(min(h.counts[i], maxHuffmanBits))
+	}
+
+	h.limitLengths(syms)
+
+	h.tableBits = 0
+	for _, s := range syms {
+		h.tableBits = max(h.tableBits, int(h.lens[s]))
+	}
+
+	// Assign codes in the order used by readHuff:
+	// by increasing weight (decreasing length), then by symbol.
+	var next uint32
+	for l := h.tableBits; l > 0; l-- {
+		for s := 0; s <= maxSym; s++ {
+			if int(h.lens[s]) == l {
+				h.codes[s] = uint16(next >> (h.tableBits - l))
+				next += 1 << (h.tableBits - l)
+			}
+		}
+	}
+}
+
+// limitLengths adjusts the code lengths, which have been clamped
+// to maxHuffmanBits, so that they form a complete prefix code.
+// The weights that describe a Huffman table can only express a
+// complete code. syms holds the symbols in increasing order of count.
+func (h *huffEncoder) limitLengths(syms []uint8) {
+	const total = 1 << maxHuffmanBits
+	kraft := 0
+	for _, s := range syms {
+		kraft += total >> h.lens[s]
+	}
+
+	// While the code is over-subscribed,
+	// lengthen the code of the least frequent symbol that permits it.
+	for kraft > total {
+		for _, s := range syms {
+			if h.lens[s] < maxHuffmanBits {
+				kraft -= total >> (h.lens[s] + 1)
+				h.lens[s]++
+				break
+			}
+		}
+	}
+
+	// While the code is incomplete,
+	// shorten the code of the most frequent symbol that permits it.
+	for kraft < total {
+		for i := len(syms) - 1; i >= 0; i-- {
+			s := syms[i]
+			if h.lens[s] > 1 && kraft+total>>h.lens[s] <= total {
+				kraft += total >> h.lens[s]
+				h.lens[s]--
+				break
+			}
+		}
+	}
+}
+
+// weight returns the weight of sym in the Huffman table description.
+func (h *huffEncoder) weight(sym int) uint8 {
+	if h.lens[sym] == 0 {
+		return 0
+	}
+	return uint8(h.tableBits + 1 - int(h.lens[sym]))
+}
+
+// appendTable appends the Huffman tree description to dst.
+// It reports false if the description can't be written. RFC 4.2.1.
+func (h *huffEncoder) appendTable(dst []byte) ([]byte, bool) {
+	// The weight of the last symbol is implied.
+	count := h.maxSym
+
+	if out, ok := h.appendFSETable(dst); ok && (count > 128 || len(out)-len(dst) < 1+(count+1)/2) {
+		return out, true
+	}
+	if count > 128 {
+		return dst, false
+	}
+
+	// Write the weights directly, 4 bits each. RFC 4.2.1.1.
+	dst = append(dst, byte(127+count))
+	for i := 0; i < count; i += 2 {
+		b := h.weight(i) << 4
+		if i+1 < count {
+			b |= h.weight(i + 1)
+		}
+		dst = append(dst, b)
+	}
+	return dst, true
+}
+
+// appendFSETable appends a Huffman tree description whose
+// weights are compressed with FSE. RFC 4.2.1.2.
+func (h *huffEncoder) appendFSETable(dst []byte) ([]byte, bool) {
+	count := h.maxSym
+	if count < 2 {
+		return dst, false
+	}
+	var hist [maxHuffmanBits + 1]uint32
+	maxWeight := 0
+	distinct := 0
+	for i := 0; i < count; i++ {
+		w := h.weight(i)
+		if hist[w] == 0 {
+			distinct++
+		}
+		hist[w]++
+		maxWeight = max(maxWeight, int(w))
+	}
+	if distinct < 2 {
+		return dst, false
+	}
+
+	const maxWeightTableBits = 6
+	e := &h.fse
+	tableBits := optimalTableBits(count, maxWeight, maxWeightTableBits)
+	e.normalize(hist[:maxWeight+1], count, tableBits)
+
+	// The decoder stops when it runs out of bits, so the state
+	// it reads last must consume at least one bit. No state
+	// consumes zero bits unless a symbol has more than half of
+	// the table, so take the excess away from such a symbol.
+	half := int16(1) << (tableBits - 1)
+	for i, n := range e.norm[:maxWeight+1] {
+		if n > half {
+			e.norm[i] = half
+			excess := n - half
+			// Give the excess to the next most common symbol.
+			best := -1
+			for j, m := range e.norm[:maxWeight+1] {
+				if j != i && m != 0 && (best < 0 || m > e.norm[best]) {
+					best = j
+				}
+			}
+			if e.norm[best] == -1 {
+				e.norm[best] = 1
+			}
+			e.norm[best] += excess
+			break
+		}
+	}
+	e.build()
+
+	start := len(dst)
+	dst = append(dst, 0) // header byte, filled in below
+	dst = e.appendHeader(dst)
+
+	// Encode the weights with two interleaved states.
+	// The decoder reads the first weight from state1,
+	// the second from state2, and so on.
+	bw := bitWriter{out: dst}
+	i := count - 1
+	var state1, state2 uint32
+	if count&1 != 0 {
+		state1 = e.initState(h.weight(i))
+		state2 = e.initState(h.weight(i - 1))
+		state1 = e.encode(&bw, state1, h.weight(i-2))
+		i -= 3
+	} else {
+		state2 = e.initState(h.weight(i))
+		state1 = e.initState(h.weight(i - 1))
+		i -= 2
+	}
+	for ; i >= 0; i -= 2 {
+		state2 = e.encode(&bw, state2, h.weight(i))
+		state1 = e.encode(&bw, state1, h.weight(i-1))
+	}
+	e.flushState(&bw, state2)
+	e.flushState(&bw, state1)
+	dst = bw.closeReverse()
+
+	size := len(dst) - start - 1
+	if size >= 128 {
+		return dst[:start], false
+	}
+	dst[start] = byte(size)
+	return dst, true
+}
+
+// appendStream appends the Huffman coded literals to dst as a single
+// stream. The decoder reads the stream backward, so the literals
+// are written in reverse order.
+func (h *huffEncoder) appendStream(dst, lits []byte) []byte {
+	bw := bitWriter{out: dst}
+	for i := len(lits) - 1; i >= 0; i-- {
+		c := lits[i]
+		bw.addBits(uint32(h.codes[c]), h.lens[c])
+	}
+	return bw.closeReverse()
+}
+
+// cost returns the number of bytes needed to encode hist.
+func (h *huffEncoder) cost(hist *[256]uint32) int {
+	bits := 0
+	for i, c := range hist[:h.maxSym+1] {
+		bits += int(c) * int(h.lens[i])
+	}
+	return (bits + 7) / 8
+}
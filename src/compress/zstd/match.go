@@ -0,0 +1,225 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// minMatch is the shortest match the matcher looks for.
+const minMatch = 4
+
+// levelParams are the parameters of a compression level.
+type levelParams struct {
+	windowBits int  // log2 of the window size
+	hashBits   int  // log2 of the hash table size
+	chainBits  int  // log2 of the hash chain size; 0 for no chain
+	depth      int  // maximum number of chain entries to check
+	lazy       bool // whether to check the next position for a better match
+}
+
+// levels holds the parameters for each compression level,
+// indexed by level. Level 0 does not compress.
+var levels = [...]levelParams{
+	0: {windowBits: 17},
+	1: {windowBits: 19, hashBits: 15, depth: 1},
+	2: {windowBits: 19, hashBits: 16, chainBits: 16, depth: 4},
+	3: {windowBits: 20, hashBits: 17, chainBits: 17, depth: 8, lazy: true},
+	4: {windowBits: 20, hashBits: 17, chainBits: 17, depth: 16, lazy: true},
+	5: {windowBits: 21, hashBits: 17, chainBits: 18, depth: 32, lazy: true},
+	6: {windowBits: 21, hashBits: 18, chainBits: 18, depth: 64, lazy: true},
+	7: {windowBits: 22, hashBits: 18, chainBits: 19, depth: 128, lazy: true},
+	8: {windowBits: 22, hashBits: 18, chainBits: 19, depth: 256, lazy: true},
+	9: {windowBits: 23, hashBits: 18, chainBits: 20, depth: 512, lazy: true},
+}
+
+// matcher finds matches in the history of a frame.
+//
+// Positions in the tables are absolute: hist[i] is at position
+// base+i. A position of 0 is never valid, so a zero entry is empty.
+type matcher struct {
+	p     *levelParams
+	table []uint32 // most recent position for each hash
+	chain []uint32 // previous position with the same hash, by position
+	base  uint32   // absolute position of hist[0]
+}
+
+// reset prepares m for a new frame.
+func (m *matcher) reset(p *levelParams) {
+	m.p = p
+	if p.hashBits == 0 {
+		return
+	}
+	if len(m.table) != 1<<p.hashBits {
+		m.table = make([]uint32, 1<<p.hashBits)
+	} else {
+		clear(m.table)
+	}
+	if p.chainBits == 0 {
+		m.chain = nil
+	} else if len(m.chain) != 1<<p.chainBits {
+		m.chain = make([]uint32, 1<<p.chainBits)
+	} else {
+		clear(m.chain)
+	}
+	m.base = 1
+}
+
+// slide records that n bytes have been removed from
+// the start of the history.
+func (m *matcher) slide(n int) {
+	m.base += uint32(n)
+}
+
+// checkOverflow clears the tables if positions in a history
+// of size n could overflow. This loses the history, which
+// only happens once every few gigabytes.
+func (m *matcher) checkOverflow(n int) {
+	if m.table != nil && uint64(m.base)+uint64(n) > 1<<31 {
+		clear(m.table)
+		clear(m.chain)
+		m.base = 1
+	}
+}
+
+func load32(b []byte, i int) uint32 {
+	return binary.LittleEndian.Uint32(b[i:])
+}
+
+func hash4(u uint32, hashBits int) uint32 {
+	const prime4bytes = 2654435761
+	return (u * prime4bytes) >> (32 - hashBits)
+}
+
+// insert adds position i of hist to the tables.
+// There must be at least minMatch bytes at i.
+func (m *matcher) insert(hist []byte, i int) uint32 {
+	h := hash4(load32(hist, i), m.p.hashBits)
+	pos := m.base + uint32(i)
+	prev := m.table[h]
+	m.table[h] = pos
+	if m.chain != nil {
+		m.chain[pos&uint32(len(m.chain)-1)] = prev
+	}
+	return prev
+}
+
+// matchLen returns the length of the common prefix of a and b.
+func matchLen(a, b []byte) int {
+	n := 0
+	for len(a) >= 8 && len(b) >= 8 {
+		x := binary.LittleEndian.Uint64(a) ^ binary.LittleEndian.Uint64(b)
+		if x != 0 {
+			return n + bits.TrailingZeros64(x)>>3
+		}
+		a, b, n = a[8:], b[8:], n+8
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// find inserts position i of hist and returns the longest match
+// for it that ends by end and starts at or after min.
+// It returns a length of 0 if there is no match.
+func (m *matcher) find(hist []byte, i, end, min int) (length, offset int) {
+	cand := m.insert(hist, i)
+	cur := load32(hist, i)
+	minPos := m.base + uint32(min)
+	for depth := m.p.depth; depth > 0 && cand >= minPos; depth-- {
+		j := int(cand - m.base)
+		if load32(hist, j) == cur {
+			if n := minMatch + matchLen(hist[j+minMatch:end], hist[i+minMatch:end]); n > length {
+				length, offset = n, i-j
+				if i+n == end {
+					break
+				}
+			}
+		}
+		if m.chain == nil {
+			break
+		}
+		next := m.chain[cand&uint32(len(m.chain)-1)]
+		if next >= cand {
+			// The chain entry has been overwritten by a later position.
+			break
+		}
+		cand = next
+	}
+	return length, offset
+}
+
+// findSequences finds matches for the bytes of hist from start to end,
+// appending literals to lits and sequences to seqs.
+// Matches may refer back up to windowSize bytes, but not before
+// the start of hist.
+func (m *matcher) findSequences(hist []byte, start, end, windowSize int, lits []byte, seqs []seq) ([]byte, []seq) {
+	// Leave room to load minMatch bytes at the last position,
+	// and don't bother looking for matches in the last few bytes.
+	limit := end - 8
+	litStart := start
+	i := start
+	for i < limit {
+		length, offset := m.find(hist, i, end, max(0, i-windowSize))
+		inserted := i + 1
+		if length == 0 {
+			// Skip ahead faster when there are no matches,
+			// at the fastest level.
+			if m.p.chainBits == 0 {
+				i += 1 + (i-litStart)>>6
+			} else {
+				i++
+			}
+			continue
+		}
+
+		if m.p.lazy {
+			// Prefer a longer match at the next position.
+			for i+1 < limit {
+				n, off := m.find(hist, i+1, end, max(0, i+1-windowSize))
+				inserted = i + 2
+				if n <= length {
+					break
+				}
+				i++
+				length, offset = n, off
+			}
+		}
+
+		// Extend the match backward.
+		for i > litStart && i > offset && hist[i-1] == hist[i-offset-1] {
+			i--
+			length++
+		}
+
+		lits = append(lits, hist[litStart:i]...)
+		seqs = append(seqs, seq{
+			litLen:   uint32(i - litStart),
+			matchLen: uint32(length),
+			offset:   uint32(offset),
+		})
+
+		// Add the positions covered by the match.
+		matchEnd := i + length
+		if m.p.chainBits == 0 {
+			if matchEnd-2 < limit {
+				m.insert(hist, matchEnd-2)
+			}
+		} else {
+			for j := inserted; j < matchEnd && j < limit; j++ {
+				m.insert(hist, j)
+			}
+		}
+		i = matchEnd
+		litStart = i
+	}
+	lits = append(lits, hist[litStart:end]...)
+	return lits, seqs
+}
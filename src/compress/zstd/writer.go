@@ -0,0 +1,368 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Compression levels for [NewWriterLevel] and [NewWriterDict].
+// Higher levels look harder for matches, and use a larger window,
+// which requires more memory from both the compressor and the
+// decompressor.
+const (
+	NoCompression      = 0
+	BestSpeed          = 1
+	BestCompression    = 9
+	DefaultCompression = -1
+)
+
+// defaultLevel is the level used for [DefaultCompression].
+const defaultLevel = 3
+
+var errWriterClosed = errors.New("zstd: write to closed Writer")
+
+// A Writer is an [io.WriteCloser] that compresses data written to it
+// as a single zstd frame. Each frame includes a checksum of its
+// uncompressed content, which [Reader] verifies.
+type Writer struct {
+	w     io.Writer
+	level int
+	p     *levelParams
+	dict  *dictionary
+	err   error
+
+	wroteHeader bool
+	closed      bool
+
+	// The history of the frame, including any dictionary content,
+	// followed by data that has not yet been compressed,
+	// which starts at pending.
+	hist    []byte
+	pending int
+
+	matcher  matcher
+	enc      blockEncoder
+	checksum xxhash64
+
+	// Scratch space.
+	lits []byte
+	seqs []seq
+	out  []byte
+}
+
+// NewWriter returns a new [Writer] compressing data at the
+// default level. Writes to the returned writer are compressed
+// and written to w.
+//
+// It is the caller's responsibility to call Close on the [Writer]
+// when done. Writes may be buffered and not flushed until Close.
+//
+// Note that the exact bytes written to w are not covered by the Go 1
+// compatibility promise. Callers, including tests, should not depend on the
+// exact written bytes.
+func NewWriter(w io.Writer) *Writer {
+	zw, _ := NewWriterLevel(w, DefaultCompression)
+	return zw
+}
+
+// NewWriterLevel is like [NewWriter] but specifies the compression level
+// instead of assuming [DefaultCompression].
+//
+// The compression level can be [DefaultCompression], [NoCompression],
+// or any integer value between [BestSpeed] and [BestCompression] inclusive.
+// The error returned will be nil if the level is valid.
+func NewWriterLevel(w io.Writer, level int) (*Writer, error) {
+	return NewWriterDict(w, level, nil)
+}
+
+// NewWriterDict is like [NewWriterLevel] but compresses using a
+// preset dictionary. The dictionary may be either a zstd dictionary,
+// as produced by the zstd --train command, or raw content.
+// The compressed data written to w can only be decompressed
+// by a [Reader] using the same dictionary (see [NewReaderDict]).
+// The Writer refers to, but does not modify, dict.
+func NewWriterDict(w io.Writer, level int, dict []byte) (*Writer, error) {
+	if level == DefaultCompression {
+		level = defaultLevel
+	}
+	if level < NoCompression || level > BestCompression {
+		return nil, fmt.Errorf("zstd: invalid compression level: %d", level)
+	}
+	zw := &Writer{
+		level: level,
+		p:     &levels[level],
+	}
+	if len(dict) > 0 {
+		d, err := new(Reader).parseDict(dict)
+		if err != nil {
+			return nil, err
+		}
+		zw.dict = d
+	}
+	zw.Reset(w)
+	return zw, nil
+}
+
+// Reset discards the [Writer]'s state and makes it equivalent to the
+// result of its original state from [NewWriter], [NewWriterLevel]
+// or [NewWriterDict], but writing to w instead. This permits reusing
+// a [Writer] rather than allocating a new one.
+func (zw *Writer) Reset(w io.Writer) {
+	zw.w = w
+	zw.err = nil
+	zw.wroteHeader = false
+	zw.closed = false
+	zw.hist = zw.hist[:0]
+	zw.pending = 0
+	zw.matcher.reset(zw.p)
+	zw.checksum.reset()
+
+	if zw.dict != nil && zw.p.hashBits > 0 {
+		// The dictionary content is history
+		// available to the first window of the frame.
+		content := zw.dict.content
+		content = content[max(0, len(content)-zw.windowSize()):]
+		zw.hist = append(zw.hist, content...)
+		zw.pending = len(zw.hist)
+		for i := 0; i+8 <= len(zw.hist); i++ {
+			zw.matcher.insert(zw.hist, i)
+		}
+	}
+}
+
+// windowSize returns the window size of frames written by zw.
+func (zw *Writer) windowSize() int {
+	return 1 << zw.p.windowBits
+}
+
+// Write writes a compressed form of p to the underlying [io.Writer].
+// The compressed bytes are not necessarily flushed until the
+// [Writer] is flushed or closed.
+func (zw *Writer) Write(p []byte) (int, error) {
+	if zw.err != nil {
+		return 0, zw.err
+	}
+	if zw.closed {
+		return 0, errWriterClosed
+	}
+	n := 0
+	for len(p) > 0 {
+		// Only compress a full block once there is more data,
+		// so that Close can write a small frame as a single segment.
+		if len(zw.hist)-zw.pending == maxBlockSize {
+			if err := zw.writeBlock(false); err != nil {
+				return n, err
+			}
+		}
+		if len(zw.hist) == cap(zw.hist) {
+			zw.grow()
+		}
+		m := min(len(p), maxBlockSize-(len(zw.hist)-zw.pending), cap(zw.hist)-len(zw.hist))
+		zw.hist = append(zw.hist, p[:m]...)
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// grow makes room in the history for more data. The history
+// grows up to two windows plus a block, after which we discard
+// data that has moved out of the window.
+func (zw *Writer) grow() {
+	keep := 0
+	if zw.p.hashBits > 0 {
+		keep = zw.windowSize()
+	}
+	maxSize := 2*keep + maxBlockSize
+	if c := cap(zw.hist); c < maxSize {
+		hist := make([]byte, len(zw.hist), min(max(2*c, 64<<10), maxSize))
+		copy(hist, zw.hist)
+		zw.hist = hist
+		return
+	}
+
+	// The data not yet compressed is at most a block,
+	// so this discards at least a window.
+	drop := zw.pending - keep
+	n := copy(zw.hist, zw.hist[drop:])
+	zw.hist = zw.hist[:n]
+	zw.pending -= drop
+	zw.matcher.slide(drop)
+}
+
+// Flush compresses any pending data and writes it to the
+// underlying writer. It is useful mainly in network protocols,
+// to ensure that a remote reader has enough data to reconstruct
+// a packet. Flush does not return until the data has been written.
+// If the underlying writer returns an error, Flush returns that error.
+func (zw *Writer) Flush() error {
+	if zw.err != nil {
+		return zw.err
+	}
+	if zw.closed || len(zw.hist) == zw.pending {
+		return nil
+	}
+	return zw.writeBlock(false)
+}
+
+// Close compresses any pending data, and finishes the frame
+// by writing the last block and the checksum.
+// It does not close the underlying [io.Writer].
+func (zw *Writer) Close() error {
+	if zw.err != nil {
+		return zw.err
+	}
+	if zw.closed {
+		return nil
+	}
+	zw.closed = true
+	if err := zw.writeBlock(true); err != nil {
+		return err
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(zw.checksum.digest()))
+	_, zw.err = zw.w.Write(b[:])
+	return zw.err
+}
+
+// writeBlock compresses the pending data as a block, writing the
+// frame header first if needed.
+func (zw *Writer) writeBlock(last bool) error {
+	out := zw.out[:0]
+	if !zw.wroteHeader {
+		out = zw.appendFrameHeader(out, last)
+		zw.wroteHeader = true
+	}
+
+	src := zw.hist[zw.pending:]
+	zw.checksum.update(src)
+	out = zw.appendBlock(out, src, last)
+	zw.pending = len(zw.hist)
+	zw.out = out
+
+	_, zw.err = zw.w.Write(out)
+	return zw.err
+}
+
+// appendFrameHeader appends the frame header to dst. RFC 3.1.1.
+// If last is true the content is all in one block, so the
+// header records the content size, which is also the window size.
+func (zw *Writer) appendFrameHeader(dst []byte, last bool) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, 0xfd2fb528)
+
+	var dictID uint32
+	if zw.dict != nil {
+		dictID = zw.dict.id
+	}
+
+	// Frame_Header_Descriptor. RFC 3.1.1.1.1.
+	descriptor := byte(1 << 2) // Content_Checksum_Flag
+	var dictIDSize int
+	switch {
+	case dictID == 0:
+	case dictID < 1<<8:
+		descriptor |= 1
+		dictIDSize = 1
+	case dictID < 1<<16:
+		descriptor |= 2
+		dictIDSize = 2
+	default:
+		descriptor |= 3
+		dictIDSize = 4
+	}
+
+	// A single segment frame holds the whole content in the window.
+	// Don't use one with a dictionary, as the dictionary content
+	// must remain in the window.
+	singleSegment := last && zw.dict == nil
+	size := uint64(len(zw.hist) - zw.pending)
+	var fcsSize int
+	if singleSegment {
+		descriptor |= 1 << 5
+		switch {
+		case size < 256:
+			fcsSize = 1
+		case size < 65536+256:
+			descriptor |= 1 << 6
+			fcsSize = 2
+		default:
+			descriptor |= 2 << 6
+			fcsSize = 4
+		}
+	}
+	dst = append(dst, descriptor)
+
+	if !singleSegment {
+		// Window_Descriptor. RFC 3.1.1.1.2.
+		dst = append(dst, byte(zw.p.windowBits-10)<<3)
+	}
+
+	for i := 0; i < dictIDSize; i++ {
+		dst = append(dst, byte(dictID>>(8*i)))
+	}
+
+	switch fcsSize {
+	case 1:
+		dst = append(dst, byte(size))
+	case 2:
+		dst = binary.LittleEndian.AppendUint16(dst, uint16(size-256))
+	case 4:
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(size))
+	}
+	return dst
+}
+
+// appendBlock appends src as a single block to dst. RFC 3.1.1.2.
+func (zw *Writer) appendBlock(dst, src []byte, last bool) []byte {
+	var lastBit uint32
+	if last {
+		lastBit = 1
+	}
+
+	if len(src) > 0 && zw.p.hashBits > 0 {
+		if rle(src) {
+			dst = appendBlockHeader(dst, lastBit|1<<1, len(src))
+			return append(dst, src[0])
+		}
+
+		// Compress the block, falling back to a raw block
+		// if that doesn't help.
+		start := zw.pending
+		zw.matcher.checkOverflow(len(zw.hist))
+		zw.lits, zw.seqs = zw.matcher.findSequences(zw.hist, start, len(zw.hist), zw.windowSize(), zw.lits[:0], zw.seqs[:0])
+		hdr := len(dst)
+		dst = appendBlockHeader(dst, 0, 0)
+		dst = zw.enc.appendCompressedBlock(dst, zw.lits, zw.seqs)
+		if size := len(dst) - hdr - 3; size < len(src) {
+			h := lastBit | 2<<1 | uint32(size)<<3
+			dst[hdr], dst[hdr+1], dst[hdr+2] = byte(h), byte(h>>8), byte(h>>16)
+			return dst
+		}
+		dst = dst[:hdr]
+	}
+
+	dst = appendBlockHeader(dst, lastBit, len(src))
+	return append(dst, src...)
+}
+
+// appendBlockHeader appends a block header to dst. RFC 3.1.1.2.
+func appendBlockHeader(dst []byte, flags uint32, size int) []byte {
+	h := flags | uint32(size)<<3
+	return append(dst, byte(h), byte(h>>8), byte(h>>16))
+}
+
+// rle reports whether b consists of a single repeated byte.
+func rle(b []byte) bool {
+	for _, c := range b[1:] {
+		if c != b[0] {
+			return false
+		}
+	}
+	return true
+}
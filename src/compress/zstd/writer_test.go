@@ -0,0 +1,321 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package zstd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testInputs returns inputs of various shapes for round trip tests.
+func testInputs(t testing.TB) map[string][]byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	random := make([]byte, 300<<10)
+	for i := range random {
+		random[i] = byte(r.Uint32())
+	}
+	// Text-like data with a skewed distribution of bytes.
+	var words bytes.Buffer
+	for words.Len() < 200<<10 {
+		fmt.Fprintf(&words, "word%d ", r.IntN(1000))
+	}
+	var binary bytes.Buffer
+	for binary.Len() < 200<<10 {
+		binary.WriteByte(byte(r.NormFloat64()*20 + 128))
+	}
+	inputs := map[string][]byte{
+		"empty":  nil,
+		"byte":   {'x'},
+		"short":  []byte("hello, world\n"),
+		"zeros":  make([]byte, 300<<10),
+		"random": random,
+		"words":  words.Bytes(),
+		"binary": binary.Bytes(),
+		"repeat": bytes.Repeat([]byte("abcdefghijklmnop"), 20000),
+	}
+	for _, test := range tests {
+		inputs["sample-"+test.name] = []byte(test.uncompressed)
+	}
+	if !testing.Short() {
+		inputs["big"] = bigData(t)
+	}
+	return inputs
+}
+
+func compress(t testing.TB, data []byte, level int, dict []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriterDict(&buf, level, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for name, data := range testInputs(t) {
+		for level := NoCompression; level <= BestCompression; level++ {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, nil)
+				got, err := io.ReadAll(NewReader(bytes.NewReader(compressed)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, data) {
+					showDiffs(t, got, data)
+				}
+				if level > NoCompression && len(data) > 1000 && name != "random" && len(compressed) >= len(data) {
+					t.Errorf("compressed %d bytes to %d", len(data), len(compressed))
+				}
+			})
+		}
+	}
+}
+
+func TestWriterLevels(t *testing.T) {
+	for _, level := range []int{DefaultCompression, NoCompression, BestSpeed, BestCompression} {
+		if _, err := NewWriterLevel(io.Discard, level); err != nil {
+			t.Errorf("NewWriterLevel(%d): %v", level, err)
+		}
+	}
+	for _, level := range []int{-2, BestCompression + 1} {
+		if _, err := NewWriterLevel(io.Discard, level); err == nil {
+			t.Errorf("NewWriterLevel(%d): succeeded, want error", level)
+		}
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	pr, pw := io.Pipe()
+	w := NewWriter(pw)
+	r := NewReader(pr)
+	for i := 0; i < 5; i++ {
+		msg := []byte(strings.Repeat(fmt.Sprintf("message %d;", i), i+1))
+		done := make(chan error)
+		go func() {
+			if _, err := w.Write(msg); err != nil {
+				done <- err
+				return
+			}
+			done <- w.Flush()
+		}()
+		got := make([]byte, len(msg))
+		if _, err := io.ReadFull(r, got); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("got %q, want %q", got, msg)
+		}
+	}
+	go func() {
+		w.Close()
+		pw.Close()
+	}()
+	if rest, err := io.ReadAll(r); err != nil || len(rest) != 0 {
+		t.Fatalf("after Close: got %q, %v; want EOF", rest, err)
+	}
+}
+
+func TestWriterReset(t *testing.T) {
+	data := bigData(t)[:500<<10]
+	var buf1, buf2 bytes.Buffer
+	w := NewWriter(&buf1)
+	w.Write([]byte("discarded data"))
+	w.Reset(&buf1)
+	w.Write(data)
+	w.Close()
+	w.Reset(&buf2)
+	w.Write(data)
+	w.Close()
+	if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+		t.Error("output differs after Reset")
+	}
+	got, err := io.ReadAll(NewReader(&buf1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		showDiffs(t, got, data)
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w := NewWriter(io.Discard)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Error("Write after Close succeeded")
+	}
+}
+
+func TestWriterChecksum(t *testing.T) {
+	compressed := compress(t, []byte(strings.Repeat("checksum ", 100)), DefaultCompression, nil)
+	compressed[len(compressed)-1] ^= 1
+	if _, err := io.ReadAll(NewReader(bytes.NewReader(compressed))); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("got error %v, want checksum error", err)
+	}
+}
+
+func TestRawDict(t *testing.T) {
+	dict := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog. ", 20))
+	data := []byte("the lazy dog jumps over the quick brown fox. the quick brown fox jumps over the lazy dog.")
+	with := compress(t, data, DefaultCompression, dict)
+	without := compress(t, data, DefaultCompression, nil)
+	if len(with) >= len(without) {
+		t.Errorf("compressed size with dictionary %d, without %d", len(with), len(without))
+	}
+	r, err := NewReaderDict(bytes.NewReader(with), dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+}
+
+// trainDict uses the zstd program to train a dictionary on samples.
+func trainDict(t *testing.T, zstd string, samples [][]byte) []byte {
+	dir := t.TempDir()
+	var files []string
+	for i, s := range samples {
+		name := filepath.Join(dir, fmt.Sprintf("sample%d", i))
+		if err := os.WriteFile(name, s, 0o666); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, name)
+	}
+	dictFile := filepath.Join(dir, "dict")
+	args := append([]string{"-q", "--train", "--maxdict=4096", "-o", dictFile}, files...)
+	if out, err := exec.Command(zstd, args...).CombinedOutput(); err != nil {
+		t.Skipf("zstd --train failed: %v\n%s", err, out)
+	}
+	dict, err := os.ReadFile(dictFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dict
+}
+
+func dictSamples() [][]byte {
+	r := rand.New(rand.NewPCG(3, 4))
+	var samples [][]byte
+	for i := 0; i < 200; i++ {
+		samples = append(samples, fmt.Appendf(nil,
+			`{"id":%d,"name":"user%d","email":"user%d@example.com","active":%t,"score":%d,"tags":["alpha","beta","gamma"]}`,
+			i, r.IntN(1000), r.IntN(1000), r.IntN(2) == 0, r.IntN(100)))
+	}
+	return samples
+}
+
+// Test interoperation with the zstd program using a trained dictionary.
+func TestTrainedDict(t *testing.T) {
+	zstd := findZstd(t)
+	samples := dictSamples()
+	dict := trainDict(t, zstd, samples)
+	dictFile := filepath.Join(t.TempDir(), "dict")
+	if err := os.WriteFile(dictFile, dict, 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, data := range samples[:10] {
+		// Compress with zstd, decompress with Reader.
+		cmd := exec.Command(zstd, "-q", "-c", "-D", dictFile)
+		cmd.Stdin = bytes.NewReader(data)
+		compressed, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("zstd -D failed: %v", err)
+		}
+		r, err := NewReaderDict(bytes.NewReader(compressed), dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("sample %d: %v", i, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("sample %d: got %q, want %q", i, got, data)
+		}
+
+		// Compress with Writer, decompress with zstd.
+		compressed = compress(t, data, DefaultCompression, dict)
+		cmd = exec.Command(zstd, "-q", "-d", "-c", "-D", dictFile)
+		cmd.Stdin = bytes.NewReader(compressed)
+		got, err = cmd.Output()
+		if err != nil {
+			t.Fatalf("sample %d: zstd -d -D failed: %v", i, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("sample %d: zstd decompressed %q, want %q", i, got, data)
+		}
+
+		// A reader without the dictionary must reject the frame.
+		if _, err := io.ReadAll(NewReader(bytes.NewReader(compressed))); err == nil {
+			t.Errorf("sample %d: decompressed without dictionary", i)
+		}
+	}
+}
+
+// Test that the zstd program can decompress what we compress.
+func TestWriterZstd(t *testing.T) {
+	zstd := findZstd(t)
+	for name, data := range testInputs(t) {
+		for _, level := range []int{NoCompression, BestSpeed, DefaultCompression, BestCompression} {
+			t.Run(fmt.Sprintf("%s/%d", name, level), func(t *testing.T) {
+				compressed := compress(t, data, level, nil)
+				cmd := exec.Command(zstd, "-q", "-d", "-c")
+				cmd.Stdin = bytes.NewReader(compressed)
+				var stderr bytes.Buffer
+				cmd.Stderr = &stderr
+				got, err := cmd.Output()
+				if err != nil {
+					t.Fatalf("zstd -d failed: %v\n%s", err, stderr.Bytes())
+				}
+				if !bytes.Equal(got, data) {
+					showDiffs(t, got, data)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	data := bigData(b)
+	for _, level := range []int{BestSpeed, DefaultCompression, BestCompression} {
+		b.Run(fmt.Sprint(level), func(b *testing.B) {
+			w, _ := NewWriterLevel(io.Discard, level)
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for b.Loop() {
+				w.Reset(io.Discard)
+				w.Write(data)
+				w.Close()
+			}
+		})
+	}
+}
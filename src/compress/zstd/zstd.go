@@ -2,8 +2,8 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package zstd provides a decompressor for zstd streams,
-// described in RFC 8878. It does not support dictionaries.
+// Package zstd implements reading and writing of zstd compressed data,
+// as described in RFC 8878.
 package zstd
 
 import (
@@ -82,6 +82,9 @@ type Reader struct {
 
 	// For checksum computation.
 	checksum xxhash64
+
+	// The dictionary, if any.
+	dict *dictionary
 }
 
 // NewReader creates a new Reader that decompresses data from the given reader.
@@ -91,8 +94,26 @@ func NewReader(input io.Reader) *Reader {
 	return r
 }
 
+// NewReaderDict is like [NewReader] but uses a preset dictionary.
+// The dictionary may be either a zstd dictionary, as produced by
+// the zstd --train command, or raw content. A frame that names
+// a dictionary ID other than that of dict is rejected with an error.
+// NewReaderDict returns an error if dict is a malformed zstd dictionary.
+// The Reader refers to, but does not modify, dict.
+func NewReaderDict(input io.Reader, dict []byte) (*Reader, error) {
+	r := new(Reader)
+	d, err := r.parseDict(dict)
+	if err != nil {
+		return nil, err
+	}
+	r.dict = d
+	r.Reset(input)
+	return r, nil
+}
+
 // Reset discards the current state and starts reading a new stream from r.
 // This permits reusing a Reader rather than allocating a new one.
+// Any dictionary passed to [NewReaderDict] is retained.
 func (r *Reader) Reset(input io.Reader) {
 	r.r = input
 
@@ -255,14 +276,12 @@ retry:
 	}
 
 	// Dictionary_ID. RFC 3.1.1.1.3.
-	if dictionaryIdSize != 0 {
-		dictionaryId := r.scratch[windowDescriptorSize : windowDescriptorSize+dictionaryIdSize]
-		// Allow only zero Dictionary ID.
-		for _, b := range dictionaryId {
-			if b != 0 {
-				return r.makeError(relativeOffset, "dictionaries are not supported")
-			}
-		}
+	var dictionaryId uint32
+	for i, b := range r.scratch[windowDescriptorSize : windowDescriptorSize+dictionaryIdSize] {
+		dictionaryId |= uint32(b) << (8 * i)
+	}
+	if dictionaryId != 0 && (r.dict == nil || r.dict.id != dictionaryId) {
+		return r.makeError(relativeOffset, fmt.Sprintf("unknown dictionary ID %d", dictionaryId))
 	}
 
 	// Frame_Content_Size. RFC 3.1.1.1.4.
@@ -308,11 +327,32 @@ retry:
 	r.repeatedOffset2 = 4
 	r.repeatedOffset3 = 8
 	r.huffmanTableBits = 0
-	r.window.reset(int(windowSize))
 	r.seqTables[0] = nil
 	r.seqTables[1] = nil
 	r.seqTables[2] = nil
 
+	if r.dict == nil {
+		r.window.reset(int(windowSize))
+		return nil
+	}
+
+	// The dictionary content precedes the frame content,
+	// and may be referenced from anywhere in the first window.
+	// RFC 5.
+	r.window.reset(int(windowSize) + len(r.dict.content))
+	r.window.save(r.dict.content)
+	if r.dict.hasTables {
+		r.repeatedOffset1 = r.dict.repeatedOffsets[0]
+		r.repeatedOffset2 = r.dict.repeatedOffsets[1]
+		r.repeatedOffset3 = r.dict.repeatedOffsets[2]
+		// Copy the Huffman table, as a compressed literals
+		// block overwrites it in place.
+		r.huffmanTable = append(r.huffmanTable[:0], r.dict.huffmanTable...)
+		r.huffmanTableBits = r.dict.huffmanTableBits
+		r.seqTables = r.dict.seqTables
+		r.seqTableBits = r.dict.seqTableBits
+	}
+
 	return nil
 }
 
@@ -0,0 +1,240 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// defaultBulkBatchSize is the default of BulkInsertOptions.BatchSize.
+const defaultBulkBatchSize = 1000
+
+// BulkInsertOptions holds options for [DB.BulkInsert], [Conn.BulkInsert]
+// and [Tx.BulkInsert]. They only apply when the driver does not implement
+// [driver.BulkInserter].
+type BulkInsertOptions struct {
+	// Query is the statement executed to insert each row, with one
+	// placeholder for each column, such as
+	// "INSERT INTO t (a, b) VALUES ($1, $2)" for PostgreSQL.
+	// It is required, as placeholder syntax and the quoting of
+	// names vary between databases.
+	Query string
+
+	// BatchSize is the number of rows DB.BulkInsert and Conn.BulkInsert
+	// insert in each transaction. If zero, 1000 rows are inserted in
+	// each transaction.
+	BatchSize int
+}
+
+// BulkInsert inserts rows into the named columns of table, and returns
+// the number of rows inserted. Each row must hold one value for each
+// column, in order, of the types accepted as query arguments.
+//
+// If the driver implements [driver.BulkInserter], the rows are passed to
+// it to load efficiently, for example with PostgreSQL's COPY FROM, and
+// opts may be nil. Otherwise BulkInsert prepares the INSERT statement
+// given by opts.Query, and executes it for each row, in transactions
+// of opts.BatchSize rows; it returns an error if opts.Query is empty.
+//
+// If rows yields a non-nil error, BulkInsert stops and returns it. The
+// rows of batches already committed remain inserted, and are included
+// in the count returned with the error. To insert all of the rows or
+// none, use [Tx.BulkInsert].
+//
+// rows must not use the DB's connections.
+func (db *DB) BulkInsert(ctx context.Context, table string, columns []string, rows iter.Seq2[[]any, error], opts *BulkInsertOptions) (int64, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.BulkInsert(ctx, table, columns, rows, opts)
+}
+
+// BulkInsert inserts rows into the named columns of table on the
+// connection, and returns the number of rows inserted.
+// See [DB.BulkInsert] for details.
+//
+// rows must not use c, or a transaction begun on it: if the driver
+// implements [driver.BulkInserter], c is locked while rows is iterated,
+// and using it from rows deadlocks.
+func (c *Conn) BulkInsert(ctx context.Context, table string, columns []string, rows iter.Seq2[[]any, error], opts *BulkInsertOptions) (int64, error) {
+	dc, release, err := c.grabConn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if bi, ok := dc.ci.(driver.BulkInserter); ok {
+		n, err := bulkInsertDC(ctx, dc, bi, table, columns, rows)
+		release(err)
+		return n, err
+	}
+	release(nil)
+
+	query, batchSize, err := bulkInsertQuery(opts)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		n     int64
+		tx    *Tx
+		stmt  *Stmt
+		batch int
+	)
+	defer func() {
+		// rows failed or panicked in the middle of a batch.
+		if tx != nil {
+			tx.Rollback()
+		}
+	}()
+	for row, err := range rows {
+		if err != nil {
+			return n, err
+		}
+		if tx == nil {
+			tx, err = c.BeginTx(ctx, nil)
+			if err != nil {
+				return n, err
+			}
+			stmt, err = tx.PrepareContext(ctx, query)
+			if err != nil {
+				return n, err
+			}
+		}
+		if err := execBulkRow(ctx, stmt, columns, row); err != nil {
+			return n, err
+		}
+		batch++
+		if batch == batchSize {
+			err := tx.Commit()
+			tx = nil
+			if err != nil {
+				return n, err
+			}
+			n += int64(batch)
+			batch = 0
+		}
+	}
+	if tx != nil {
+		err := tx.Commit()
+		tx = nil
+		if err != nil {
+			return n, err
+		}
+		n += int64(batch)
+	}
+	return n, nil
+}
+
+// BulkInsert inserts rows into the named columns of table within the
+// transaction, and returns the number of rows inserted.
+// See [DB.BulkInsert] for details. All of the rows are inserted in
+// the transaction, regardless of opts.BatchSize.
+//
+// rows must not use tx: if the driver implements [driver.BulkInserter],
+// the transaction's connection is locked while rows is iterated, and
+// using tx from rows deadlocks.
+func (tx *Tx) BulkInsert(ctx context.Context, table string, columns []string, rows iter.Seq2[[]any, error], opts *BulkInsertOptions) (int64, error) {
+	dc, release, err := tx.grabConn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if bi, ok := dc.ci.(driver.BulkInserter); ok {
+		n, err := bulkInsertDC(ctx, dc, bi, table, columns, rows)
+		release(err)
+		return n, err
+	}
+	release(nil)
+
+	query, _, err := bulkInsertQuery(opts)
+	if err != nil {
+		return 0, err
+	}
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	var n int64
+	for row, err := range rows {
+		if err != nil {
+			return n, err
+		}
+		if err := execBulkRow(ctx, stmt, columns, row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// bulkInsertDC passes rows to the driver's BulkInserter on dc,
+// converting them to driver values.
+func bulkInsertDC(ctx context.Context, dc *driverConn, bi driver.BulkInserter, table string, columns []string, rows iter.Seq2[[]any, error]) (n int64, err error) {
+	// convErr is the error passed to the driver, which is returned
+	// even if the driver does not return it.
+	var convErr error
+	nvrows := func(yield func([]driver.NamedValue, error) bool) {
+		for row, err := range rows {
+			var nv []driver.NamedValue
+			if err == nil {
+				err = checkBulkRow(columns, row)
+			}
+			if err == nil {
+				nv, err = driverArgsConnLocked(dc.ci, nil, row)
+			}
+			if err != nil {
+				convErr = err
+				yield(nil, err)
+				return
+			}
+			if !yield(nv, nil) {
+				return
+			}
+		}
+	}
+	withLock(dc, func() {
+		n, err = bi.BulkInsert(ctx, table, columns, nvrows)
+	})
+	if err == nil && convErr != nil {
+		err = convErr
+	}
+	return n, err
+}
+
+var errBulkInsertQuery = errors.New("sql: BulkInsert requires BulkInsertOptions.Query for drivers that do not implement driver.BulkInserter")
+
+// bulkInsertQuery returns the statement and batch size to insert rows
+// with when the driver does not implement driver.BulkInserter.
+func bulkInsertQuery(opts *BulkInsertOptions) (query string, batchSize int, err error) {
+	if opts == nil || opts.Query == "" {
+		return "", 0, errBulkInsertQuery
+	}
+	batchSize = defaultBulkBatchSize
+	if opts.BatchSize > 0 {
+		batchSize = opts.BatchSize
+	}
+	return opts.Query, batchSize, nil
+}
+
+// checkBulkRow reports an error if row does not hold a value for each
+// of the columns.
+func checkBulkRow(columns []string, row []any) error {
+	if len(row) != len(columns) {
+		return fmt.Errorf("sql: BulkInsert row has %d values, want %d", len(row), len(columns))
+	}
+	return nil
+}
+
+func execBulkRow(ctx context.Context, stmt *Stmt, columns []string, row []any) error {
+	if err := checkBulkRow(columns, row); err != nil {
+		return err
+	}
+	_, err := stmt.ExecContext(ctx, row...)
+	return err
+}
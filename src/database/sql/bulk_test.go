@@ -0,0 +1,240 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"testing/synctest"
+)
+
+type bulkConnector struct {
+	fakeConnector
+	inserted chan []driver.Value
+}
+
+func (c *bulkConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.fakeConnector.Connect(ctx)
+	fc := getFakeConn(conn)
+	return &bulkConn{fc, c.inserted}, err
+}
+
+// bulkConn is a Conn that implements BulkInserter by sending the
+// inserted rows on a channel.
+type bulkConn struct {
+	*fakeConn
+	inserted chan []driver.Value
+}
+
+func (c *bulkConn) BulkInsert(ctx context.Context, table string, columns []string, rows iter.Seq2[[]driver.NamedValue, error]) (int64, error) {
+	if table != "people" || !slices.Equal(columns, []string{"name", "age"}) {
+		return 0, errors.New("bad table or columns")
+	}
+	var n int64
+	for row, err := range rows {
+		if err != nil {
+			return 0, err
+		}
+		var vals []driver.Value
+		for _, nv := range row {
+			vals = append(vals, nv.Value)
+		}
+		c.inserted <- vals
+		n++
+	}
+	return n, nil
+}
+
+// bulkRows returns an iterator over n rows of people, which yields
+// err after the rows if it is non-nil.
+func bulkRows(n int, err error) iter.Seq2[[]any, error] {
+	return func(yield func([]any, error) bool) {
+		for i := range n {
+			if !yield([]any{"person", 10 + i}, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func countPeople(t *testing.T, db *DB) int {
+	t.Helper()
+	n := 0
+	for _, err := range ScanAll[string](db.Query("SELECT|people|name|")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	return n
+}
+
+func TestBulkInsertQuery(t *testing.T) {
+	for _, opts := range []*BulkInsertOptions{nil, {BatchSize: 10}} {
+		if _, _, err := bulkInsertQuery(opts); err != errBulkInsertQuery {
+			t.Errorf("bulkInsertQuery(%+v) error = %v, want %v", opts, err, errBulkInsertQuery)
+		}
+	}
+	opts := &BulkInsertOptions{Query: "INSERT INTO people VALUES ($1, $2)"}
+	query, batchSize, err := bulkInsertQuery(opts)
+	if query != opts.Query || batchSize != defaultBulkBatchSize || err != nil {
+		t.Errorf("bulkInsertQuery = %q, %d, %v, want %q, %d", query, batchSize, err, opts.Query, defaultBulkBatchSize)
+	}
+	opts.BatchSize = 10
+	if _, batchSize, _ := bulkInsertQuery(opts); batchSize != 10 {
+		t.Errorf("batch size = %d, want 10", batchSize)
+	}
+}
+
+var bulkOpts = &BulkInsertOptions{Query: "INSERT|people|name=?,age=?", BatchSize: 2}
+
+func TestBulkInsertBatches(t *testing.T) {
+	testDatabase(t, testBulkInsertBatches)
+}
+func testBulkInsertBatches(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	h := &recordingHook{t: t}
+	db.SetHook(h)
+	n, err := db.BulkInsert(t.Context(), "people", []string{"name", "age"}, bulkRows(5, nil), bulkOpts)
+	if err != nil || n != 5 {
+		t.Fatalf("BulkInsert = %d, %v, want 5 rows", n, err)
+	}
+	want := []string{"begin", "commit", "begin", "commit", "begin", "commit"}
+	if got := txEvents(h); !slices.Equal(got, want) {
+		t.Errorf("transactions: %q, want %q", got, want)
+	}
+	if n := countPeople(t, db); n != 3+5 {
+		t.Errorf("%d people after BulkInsert, want 8", n)
+	}
+	if n := db.Stats().InUse; n != 0 {
+		t.Errorf("%d connections in use after BulkInsert, want 0", n)
+	}
+}
+
+func TestBulkInsertBatchesError(t *testing.T) {
+	testDatabase(t, testBulkInsertBatchesError)
+}
+func testBulkInsertBatchesError(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	h := &recordingHook{t: t}
+	db.SetHook(h)
+	errRead := errors.New("read failed")
+	n, err := db.BulkInsert(t.Context(), "people", []string{"name", "age"}, bulkRows(3, errRead), bulkOpts)
+	if err != errRead || n != 2 {
+		t.Errorf("BulkInsert = %d, %v, want 2 rows, %v", n, err, errRead)
+	}
+	want := []string{"begin", "commit", "begin", "rollback"}
+	if got := txEvents(h); !slices.Equal(got, want) {
+		t.Errorf("transactions: %q, want %q", got, want)
+	}
+
+	short := func(yield func([]any, error) bool) {
+		yield([]any{"person"}, nil)
+	}
+	_, err = db.BulkInsert(t.Context(), "people", []string{"name", "age"}, short, bulkOpts)
+	if err == nil || !strings.Contains(err.Error(), "row has 1 values, want 2") {
+		t.Errorf("BulkInsert of short row: %v, want error", err)
+	}
+	if _, err := db.BulkInsert(t.Context(), "people", []string{"name", "age"}, bulkRows(1, nil), nil); err != errBulkInsertQuery {
+		t.Errorf("BulkInsert without Query: %v, want %v", err, errBulkInsertQuery)
+	}
+	if n := db.Stats().InUse; n != 0 {
+		t.Errorf("%d connections in use after BulkInsert, want 0", n)
+	}
+}
+
+func TestTxBulkInsert(t *testing.T) {
+	testDatabase(t, testTxBulkInsert)
+}
+func testTxBulkInsert(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	h := &recordingHook{t: t}
+	db.SetHook(h)
+	tx, err := db.BeginTx(t.Context(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := tx.BulkInsert(t.Context(), "people", []string{"name", "age"}, bulkRows(5, nil), bulkOpts)
+	if err != nil || n != 5 {
+		t.Fatalf("BulkInsert = %d, %v, want 5 rows", n, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := txEvents(h), []string{"begin", "commit"}; !slices.Equal(got, want) {
+		t.Errorf("transactions: %q, want %q", got, want)
+	}
+	if n := countPeople(t, db); n != 3+5 {
+		t.Errorf("%d people after BulkInsert, want 8", n)
+	}
+}
+
+func TestBulkInserter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		connector := &bulkConnector{
+			fakeConnector: fakeConnector{name: fakeDBName},
+			inserted:      make(chan []driver.Value, 10),
+		}
+		db := OpenDB(connector)
+		defer closeDB(t, db)
+		ctx := t.Context()
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		for _, tt := range []struct {
+			name   string
+			insert func(rows iter.Seq2[[]any, error]) (int64, error)
+		}{
+			{"DB", func(rows iter.Seq2[[]any, error]) (int64, error) {
+				return db.BulkInsert(ctx, "people", []string{"name", "age"}, rows, nil)
+			}},
+			{"Conn", func(rows iter.Seq2[[]any, error]) (int64, error) {
+				return conn.BulkInsert(ctx, "people", []string{"name", "age"}, rows, nil)
+			}},
+			{"Tx", func(rows iter.Seq2[[]any, error]) (int64, error) {
+				return tx.BulkInsert(ctx, "people", []string{"name", "age"}, rows, nil)
+			}},
+		} {
+			n, err := tt.insert(bulkRows(2, nil))
+			if err != nil || n != 2 {
+				t.Fatalf("%s: BulkInsert = %d, %v, want 2 rows", tt.name, n, err)
+			}
+			for i := range 2 {
+				// The int values are converted to int64 for the driver.
+				got, want := <-connector.inserted, []driver.Value{"person", int64(10 + i)}
+				if !slices.Equal(got, want) {
+					t.Errorf("%s: inserted %v, want %v", tt.name, got, want)
+				}
+			}
+
+			errRead := errors.New("read failed")
+			if _, err := tt.insert(bulkRows(0, errRead)); err != errRead {
+				t.Errorf("%s: BulkInsert = %v, want %v", tt.name, err, errRead)
+			}
+			unsupported := func(yield func([]any, error) bool) {
+				yield([]any{"person", struct{}{}}, nil)
+			}
+			if _, err := tt.insert(unsupported); err == nil {
+				t.Errorf("%s: BulkInsert of unsupported value: no error", tt.name)
+			}
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ConnInfo describes one of the driver connections in a [DB]'s
+// connection pool, as returned by [DB.OpenConns] and passed to
+// [ConnCallbacks].
+type ConnInfo struct {
+	// ID identifies the connection among those opened by the DB,
+	// which are numbered from 1.
+	ID uint64
+
+	// CreatedAt is when the connection was opened.
+	CreatedAt time.Time
+
+	// InUse reports whether the connection is in use, rather than
+	// idle in the pool.
+	InUse bool
+
+	// ReturnedAt is when the connection was last returned to the
+	// pool, or opened if it has never been returned.
+	ReturnedAt time.Time
+
+	// Uses is the number of times the connection has been taken
+	// from the pool, including when it was opened for use.
+	Uses int64
+
+	// Queries is the number of statements executed on the connection.
+	Queries int64
+}
+
+// A ConnCloseReason is why a [DB] closed one of its connections.
+type ConnCloseReason int
+
+const (
+	CloseBadConn      ConnCloseReason = iota + 1 // an operation returned driver.ErrBadConn
+	CloseInvalid                                 // the driver reported the connection invalid
+	CloseResetFailed                             // the session could not be reset
+	CloseMaxLifetime                             // the connection reached the maximum lifetime
+	CloseMaxIdleTime                             // the connection was idle for the maximum time
+	CloseMaxIdleConns                            // the pool held the maximum number of idle connections
+	CloseMaxOpenConns                            // more than the maximum number of connections were open
+	CloseDBClosed                                // the DB was closed
+)
+
+func (r ConnCloseReason) String() string {
+	switch r {
+	case CloseBadConn:
+		return "bad connection"
+	case CloseInvalid:
+		return "invalid"
+	case CloseResetFailed:
+		return "reset failed"
+	case CloseMaxLifetime:
+		return "max lifetime"
+	case CloseMaxIdleTime:
+		return "max idle time"
+	case CloseMaxIdleConns:
+		return "max idle connections"
+	case CloseMaxOpenConns:
+		return "max open connections"
+	case CloseDBClosed:
+		return "database closed"
+	}
+	return "ConnCloseReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// ConnCallbacks holds functions a [DB] calls as the connections in its
+// pool change state, as set with [DB.SetConnCallbacks]. Any of the
+// functions may be nil.
+//
+// The functions are called concurrently from multiple goroutines,
+// without the DB's locks held, and should return quickly.
+// They may call [DB.Stats] and [DB.OpenConns], but should not
+// otherwise use the DB.
+type ConnCallbacks struct {
+	// Open is called when a connection is opened, before it is used.
+	Open func(ConnInfo)
+
+	// Reuse is called when a connection that has been used before is
+	// taken from the pool.
+	Reuse func(ConnInfo)
+
+	// Reset is called when the session of a connection taken from the
+	// pool has been reset with driver.SessionResetter, with the error
+	// the reset failed with, if any.
+	Reset func(ConnInfo, error)
+
+	// Close is called when a connection has been closed, with the
+	// reason the DB closed it.
+	Close func(ConnInfo, ConnCloseReason)
+}
+
+// SetConnCallbacks sets the functions the database calls as its
+// connections are opened, reused, reset and closed. If cb is nil, no
+// functions are called.
+//
+// SetConnCallbacks may be called while the database is in use.
+func (db *DB) SetConnCallbacks(cb *ConnCallbacks) {
+	if cb == nil {
+		db.connCallbacks.Store(nil)
+		return
+	}
+	c := *cb
+	db.connCallbacks.Store(&c)
+}
+
+// OpenConns returns a snapshot of the database's open connections,
+// both in use and idle, ordered by ID.
+func (db *DB) OpenConns() []ConnInfo {
+	db.mu.Lock()
+	var conns []ConnInfo
+	for fc := range db.dep {
+		if dc, ok := fc.(*driverConn); ok && !dc.dbmuClosed && dc.closeReason == 0 {
+			conns = append(conns, dc.infoLocked())
+		}
+	}
+	db.mu.Unlock()
+	slices.SortFunc(conns, func(a, b ConnInfo) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return conns
+}
+
+// infoLocked returns a description of dc.
+// The dc.db's Mutex is held.
+func (dc *driverConn) infoLocked() ConnInfo {
+	return ConnInfo{
+		ID:         dc.id,
+		CreatedAt:  dc.createdAt,
+		InUse:      dc.inUse,
+		ReturnedAt: dc.returnedAt,
+		Uses:       dc.uses,
+		Queries:    dc.queries.Load(),
+	}
+}
+
+// connOpened calls the Open callback for dc, which has not yet been
+// added to the pool.
+func (db *DB) connOpened(dc *driverConn) {
+	if cb := db.connCallbacks.Load(); cb != nil && cb.Open != nil {
+		db.mu.Lock()
+		info := dc.infoLocked()
+		db.mu.Unlock()
+		cb.Open(info)
+	}
+}
+
+// connTaken calls the Reset and Reuse callbacks for dc, which has been
+// taken from the pool. reset reports whether the session was reset,
+// and err is the error it failed with. dc is not used if err is
+// driver.ErrBadConn.
+func (db *DB) connTaken(dc *driverConn, reset bool, err error) {
+	cb := db.connCallbacks.Load()
+	if cb == nil || (cb.Reuse == nil && (cb.Reset == nil || !reset)) {
+		return
+	}
+	db.mu.Lock()
+	info := dc.infoLocked()
+	db.mu.Unlock()
+	if reset && cb.Reset != nil {
+		cb.Reset(info, err)
+	}
+	if !errors.Is(err, driver.ErrBadConn) && info.Uses > 1 && cb.Reuse != nil {
+		cb.Reuse(info)
+	}
+}
+
+// connClosed calls the Close callback for a connection described by
+// info, which was closed for reason.
+func (db *DB) connClosed(info ConnInfo, reason ConnCloseReason) {
+	if cb := db.connCallbacks.Load(); cb != nil && cb.Close != nil {
+		cb.Close(info, reason)
+	}
+}
+
+// closePoolReasonLocked returns why a connection that could not be
+// returned to the pool is closed.
+// The db's Mutex is held.
+func (db *DB) closePoolReasonLocked() ConnCloseReason {
+	switch {
+	case db.closed:
+		return CloseDBClosed
+	case db.maxOpen > 0 && db.numOpen > db.maxOpen:
+		return CloseMaxOpenConns
+	}
+	return CloseMaxIdleConns
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// connEvents records the events reported to ConnCallbacks.
+type connEvents struct {
+	t *testing.T
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (ce *connEvents) add(format string, args ...any) {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	ce.events = append(ce.events, fmt.Sprintf(format, args...))
+}
+
+func (ce *connEvents) callbacks() *ConnCallbacks {
+	return &ConnCallbacks{
+		Open:  func(c ConnInfo) { ce.add("open %d", c.ID) },
+		Reuse: func(c ConnInfo) { ce.add("reuse %d", c.ID) },
+		Reset: func(c ConnInfo, err error) {
+			if err != nil {
+				ce.add("reset %d error", c.ID)
+				return
+			}
+			ce.add("reset %d", c.ID)
+		},
+		Close: func(c ConnInfo, reason ConnCloseReason) { ce.add("close %d %v", c.ID, reason) },
+	}
+}
+
+func (ce *connEvents) want(want ...string) {
+	ce.t.Helper()
+	ce.mu.Lock()
+	got := ce.events
+	ce.events = nil
+	ce.mu.Unlock()
+	if !slices.Equal(got, want) {
+		ce.t.Errorf("events:\n\t%q\nwant:\n\t%q", got, want)
+	}
+}
+
+// testConnCallbacks runs f in a synctest bubble with a database whose
+// connection events are recorded.
+func testConnCallbacks(t *testing.T, f func(t *testing.T, db *DB, ce *connEvents)) {
+	synctest.Test(t, func(t *testing.T) {
+		db := OpenDB(&fakeConnector{name: fakeDBName})
+		t.Cleanup(func() {
+			closeDB(t, db)
+		})
+		ce := &connEvents{t: t}
+		db.SetConnCallbacks(ce.callbacks())
+		f(t, db, ce)
+	})
+}
+
+func TestConnCallbacks(t *testing.T) {
+	testConnCallbacks(t, func(t *testing.T, db *DB, ce *connEvents) {
+		ctx := t.Context()
+		if _, err := db.Exec("WIPE"); err != nil {
+			t.Fatal(err)
+		}
+		ce.want("open 1")
+
+		c1, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ce.want("reset 1", "reuse 1")
+		c2, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ce.want("open 2")
+		c1.Close()
+		c2.Close()
+
+		// The connection returned last is closed.
+		db.SetMaxIdleConns(1)
+		ce.want("close 2 max idle connections")
+
+		// A connection failing with ErrBadConn is closed.
+		c1, err = db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c1.Raw(func(any) error { return driver.ErrBadConn })
+		c1.Close()
+		ce.want("reset 1", "reuse 1", "close 1 bad connection")
+
+		// A connection the driver reports invalid is closed when returned.
+		c3, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c3.Raw(func(dc any) error {
+			getFakeConn(dc.(driver.Conn)).stickyBad = true
+			return nil
+		})
+		c3.Close()
+		ce.want("open 3", "close 3 invalid")
+
+		// Idle connections are closed after their maximum lifetime.
+		if _, err := db.Exec("WIPE"); err != nil {
+			t.Fatal(err)
+		}
+		db.SetConnMaxLifetime(time.Minute)
+		time.Sleep(2 * time.Minute)
+		synctest.Wait()
+		ce.want("open 4", "close 4 max lifetime")
+
+		db.SetConnCallbacks(nil)
+		if _, err := db.Exec("WIPE"); err != nil {
+			t.Fatal(err)
+		}
+		ce.want()
+	})
+}
+
+func TestConnCallbacksDBClosed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		db := OpenDB(&fakeConnector{name: fakeDBName})
+		ce := &connEvents{t: t}
+		db.SetConnCallbacks(ce.callbacks())
+		conn, err := db.Conn(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("WIPE"); err != nil {
+			t.Fatal(err)
+		}
+		ce.want("open 1", "open 2")
+		db.Close()
+		ce.want("close 2 database closed")
+		conn.Close()
+		ce.want("close 1 database closed")
+	})
+}
+
+func TestOpenConns(t *testing.T) {
+	testDatabase(t, testOpenConns)
+}
+func testOpenConns(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	start := time.Now()
+	conn, err := db.Conn(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	time.Sleep(time.Second)
+	rows, err := db.Query("SELECT|people|name|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	conns := db.OpenConns()
+	if len(conns) != 2 {
+		t.Fatalf("OpenConns returned %d connections, want 2: %+v", len(conns), conns)
+	}
+	c1, c2 := conns[0], conns[1]
+	if c1.ID != 1 || !c1.InUse || c1.CreatedAt.After(start) || c1.Uses < 2 || c1.Queries < 4 {
+		t.Errorf("first connection %+v, want reused connection in use by Conn", c1)
+	}
+	if c2.ID != 2 || !c2.InUse || !c2.CreatedAt.Equal(start.Add(time.Second)) || c2.Uses != 1 || c2.Queries != 1 {
+		t.Errorf("second connection %+v, want new connection in use by Rows", c2)
+	}
+	if st := db.Stats(); st.OpenConnections != len(conns) {
+		t.Errorf("Stats reports %d open connections, want %d", st.OpenConnections, len(conns))
+	}
+}
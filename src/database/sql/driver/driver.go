@@ -30,6 +30,12 @@
 // and [RowsColumnTypePrecisionScale]. A given row value may also return a [Rows]
 // type, which may represent a database cursor value.
 //
+// To allow transactions that fail with transient errors, such as
+// serialization failures, to be retried, implement [ErrorClassifier].
+//
+// To load many rows into a table more efficiently than by executing an
+// INSERT statement for each, implement [BulkInserter].
+//
 // If a [Conn] implements [Validator], then the IsValid method is called
 // before returning the connection to the connection pool. If an entry in the
 // connection pool implements [SessionResetter], then ResetSession
@@ -42,6 +48,7 @@ import (
 	"context"
 	"database/sql/internal"
 	"errors"
+	"iter"
 	"reflect"
 )
 
@@ -314,6 +321,43 @@ type Validator interface {
 	IsValid() bool
 }
 
+// ErrorClassifier may be implemented by [Conn] to allow drivers to
+// classify the errors of a transaction, so that the sql package can
+// retry transactions that failed with a transient error.
+type ErrorClassifier interface {
+	// IsRetryable reports whether a transaction on the connection that
+	// failed with err may succeed if run again from the start, as for
+	// a serialization failure or a deadlock. err may wrap an error
+	// returned by the driver, and may be returned by a Commit.
+	//
+	// IsRetryable must return false for an error returned by Commit
+	// unless the transaction was certainly not committed.
+	IsRetryable(err error) bool
+}
+
+// BulkInserter may be implemented by [Conn] to insert many rows into a
+// table more efficiently than by executing an INSERT statement for
+// each row, for example with PostgreSQL's COPY FROM.
+//
+// If a [Conn] does not implement BulkInserter, the sql package's
+// BulkInsert methods fall back to executing a prepared statement, which
+// the caller supplies, for each row.
+type BulkInserter interface {
+	// BulkInsert inserts the rows into the named columns of table and
+	// returns the number of rows inserted. The table and column names
+	// are passed as given to the sql package, without quoting.
+	//
+	// Each row holds one value for each column, in order, converted
+	// as for the arguments of a query. The row slice is only valid
+	// until the next iteration. If rows yields a non-nil error, or
+	// the context is canceled, BulkInsert must stop and return the
+	// error; it should not insert any of the rows if it can avoid it.
+	//
+	// BulkInsert is called within a transaction if the connection is
+	// in one.
+	BulkInsert(ctx context.Context, table string, columns []string, rows iter.Seq2[[]NamedValue, error]) (int64, error)
+}
+
 // Result is the result of a query execution.
 type Result interface {
 	// LastInsertId returns the database's auto-generated ID
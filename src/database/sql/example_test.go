@@ -66,6 +66,22 @@ func ExampleDB_QueryRowContext() {
 	}
 }
 
+func ExampleScanAll() {
+	type user struct {
+		ID      int64
+		Name    string         `db:"username"`
+		Email   sql.NullString // NULL if the user has no email address
+		Created time.Time      `db:"created_at"`
+	}
+	age := 27
+	for u, err := range sql.ScanAll[user](db.QueryContext(ctx, "SELECT id, username, email, created_at FROM users WHERE age=?", age)) {
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("user %d is %q, account created on %s\n", u.ID, u.Name, u.Created)
+	}
+}
+
 func ExampleDB_ExecContext() {
 	id := 47
 	result, err := db.ExecContext(ctx, "UPDATE balances SET balance = balance + 10 WHERE user_id = ?", id)
@@ -0,0 +1,170 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// A Hook observes the operations a [DB] performs with its driver,
+// such as executing queries. It can be used to log queries, record
+// metrics, or trace them, without wrapping the driver.
+// A DB's hook is set with [DB.SetHook].
+//
+// Start is called when an operation begins, and End when it ends,
+// with the same [HookEvent]. The context passed to End is the one
+// returned by Start, which may derive it from ctx, for example to
+// hold a trace span. The context returned by Start is also passed to
+// the driver for the operation.
+//
+// Hooks are called concurrently from multiple goroutines, sometimes
+// while a connection is locked, and should return quickly.
+// They must not use the DB.
+type Hook interface {
+	Start(ctx context.Context, e *HookEvent) context.Context
+	End(ctx context.Context, e *HookEvent)
+}
+
+// A HookOp is the kind of operation described by a [HookEvent].
+type HookOp int
+
+const (
+	HookConnect  HookOp = iota + 1 // opening a new connection
+	HookWait                       // waiting for a connection from the pool
+	HookPrepare                    // preparing a statement
+	HookExec                       // executing a statement that returns no rows
+	HookQuery                      // executing a query that returns rows
+	HookBegin                      // beginning a transaction
+	HookCommit                     // committing a transaction
+	HookRollback                   // rolling back a transaction
+)
+
+func (op HookOp) String() string {
+	switch op {
+	case HookConnect:
+		return "connect"
+	case HookWait:
+		return "wait"
+	case HookPrepare:
+		return "prepare"
+	case HookExec:
+		return "exec"
+	case HookQuery:
+		return "query"
+	case HookBegin:
+		return "begin"
+	case HookCommit:
+		return "commit"
+	case HookRollback:
+		return "rollback"
+	}
+	return "HookOp(" + strconv.Itoa(int(op)) + ")"
+}
+
+// A HookEvent describes an operation observed by a [Hook].
+//
+// A HookQuery operation ends when the query has been executed and its
+// rows are ready to be read, not when the [Rows] are closed.
+// Operations retried on another connection after failing with
+// [driver.ErrBadConn] are reported once for each attempt.
+type HookEvent struct {
+	// Op is the kind of operation.
+	Op HookOp
+
+	// Query is the query text of a HookPrepare, HookExec or
+	// HookQuery operation.
+	Query string
+
+	// Args are the arguments of a HookExec or HookQuery operation,
+	// as passed to the DB.
+	Args []any
+
+	// Start is when the operation began.
+	Start time.Time
+
+	// The following fields are set when the operation ends,
+	// before Hook.End is called.
+
+	// Duration is how long the operation took.
+	Duration time.Duration
+
+	// RowsAffected is the number of rows affected by a HookExec
+	// operation, or -1 if it is not known or the operation is not
+	// a HookExec.
+	RowsAffected int64
+
+	// Err is the error the operation failed with, if any.
+	Err error
+
+	hook Hook
+	ctx  context.Context // returned by hook.Start
+}
+
+// hookHolder holds a Hook in an atomic.Pointer.
+type hookHolder struct {
+	h Hook
+}
+
+// SetHook sets the hook that observes the operations of the database.
+// If h is nil, operations are not observed.
+//
+// SetHook may be called while the database is in use. Operations in
+// progress are reported to the hook they started with.
+func (db *DB) SetHook(h Hook) {
+	if h == nil {
+		db.hook.Store(nil)
+		return
+	}
+	db.hook.Store(&hookHolder{h})
+}
+
+// startHook reports the start of an operation to the database's hook,
+// if any. It returns the context for the operation, and the event to
+// end with [HookEvent.end], which is nil if the database has no hook.
+func (db *DB) startHook(ctx context.Context, op HookOp, query string, args []any) (context.Context, *HookEvent) {
+	hh := db.hook.Load()
+	if hh == nil {
+		return ctx, nil
+	}
+	e := &HookEvent{
+		Op:           op,
+		Query:        query,
+		Args:         args,
+		Start:        time.Now(),
+		RowsAffected: -1,
+		hook:         hh.h,
+	}
+	if c := hh.h.Start(ctx, e); c != nil {
+		ctx = c
+	}
+	e.ctx = ctx
+	return ctx, e
+}
+
+// end reports the end of the operation described by e, which failed
+// with err if it is non-nil. It does nothing if e is nil.
+func (e *HookEvent) end(err error) {
+	if e == nil {
+		return
+	}
+	e.Duration = time.Since(e.Start)
+	e.Err = err
+	e.hook.End(e.ctx, e)
+}
+
+// endExec is like end for a HookExec operation that returned res.
+func (e *HookEvent) endExec(res Result, err error) {
+	if e == nil {
+		return
+	}
+	if err == nil && res != nil {
+		if n, err := res.RowsAffected(); err == nil {
+			e.RowsAffected = n
+		}
+	}
+	e.end(err)
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+)
+
+type hookCtxKey struct{}
+
+// recordingHook is a Hook that records the events it observes.
+type recordingHook struct {
+	t *testing.T
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *recordingHook) Start(ctx context.Context, e *HookEvent) context.Context {
+	return context.WithValue(ctx, hookCtxKey{}, e)
+}
+
+func (h *recordingHook) End(ctx context.Context, e *HookEvent) {
+	if ctx.Value(hookCtxKey{}) != e {
+		h.t.Errorf("%v: End not passed the context returned by Start", e.Op)
+	}
+	s := e.Op.String()
+	if e.Query != "" {
+		s += fmt.Sprintf(" %q", e.Query)
+	}
+	if len(e.Args) > 0 {
+		s += fmt.Sprintf(" %v", e.Args)
+	}
+	if e.RowsAffected >= 0 {
+		s += fmt.Sprintf(" rows=%d", e.RowsAffected)
+	}
+	if e.Err != nil {
+		s += " error"
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, s)
+}
+
+// take returns the events recorded since the last call to take.
+func (h *recordingHook) take() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	events := h.events
+	h.events = nil
+	return events
+}
+
+func (h *recordingHook) want(want ...string) {
+	h.t.Helper()
+	if got := h.take(); !slices.Equal(got, want) {
+		h.t.Errorf("events:\n\t%q\nwant:\n\t%q", got, want)
+	}
+}
+
+func TestHook(t *testing.T) {
+	testDatabase(t, testHook)
+}
+func testHook(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	h := &recordingHook{t: t}
+	db.SetHook(h)
+	ctx := t.Context()
+
+	if _, err := db.ExecContext(ctx, "INSERT|people|name=Dan,age=?", 4); err != nil {
+		t.Fatal(err)
+	}
+	h.want(`exec "INSERT|people|name=Dan,age=?" [4] rows=1`)
+
+	var name string
+	if err := db.QueryRowContext(ctx, "SELECT|people|name|age=?", 4).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	h.want(`query "SELECT|people|name|age=?" [4]`)
+
+	if _, err := db.QueryContext(ctx, "SELECT|nosuchtable|name|"); err == nil {
+		t.Fatal("query of missing table succeeded")
+	}
+	h.want(`query "SELECT|nosuchtable|name|" error`)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT|people|name=Eve,age=?", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	h.want("begin", `exec "INSERT|people|name=Eve,age=?" [5] rows=1`, "commit")
+
+	tx, err = db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	h.want("begin", "rollback")
+
+	stmt, err := db.PrepareContext(ctx, "SELECT|people|name|age=?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	if err := stmt.QueryRowContext(ctx, 5).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	h.want(`prepare "SELECT|people|name|age=?"`, `query "SELECT|people|name|age=?" [5]`)
+
+	db.SetHook(nil)
+	if _, err := db.ExecContext(ctx, "INSERT|people|name=Fay,age=?", 6); err != nil {
+		t.Fatal(err)
+	}
+	h.want()
+}
+
+func TestHookConnectWait(t *testing.T) {
+	testDatabase(t, testHookConnectWait)
+}
+func testHookConnectWait(t *testing.T, db *DB) {
+	h := &recordingHook{t: t}
+	db.SetHook(h)
+	db.SetMaxOpenConns(2)
+	ctx := t.Context()
+
+	// The first connection is the idle one opened by the test setup.
+	c1, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+	h.want("connect")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c3, err := db.Conn(ctx)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		c3.Close()
+	}()
+	synctest.Wait()
+	h.want()
+	c1.Close()
+	<-done
+	h.want("wait")
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"math/rand/v2"
+	"time"
+)
+
+// Retry limits of DB.RunTx.
+const (
+	maxTxAttempts = 10
+	minTxBackoff  = 5 * time.Millisecond
+	maxTxBackoff  = time.Second
+)
+
+// RunTx runs fn in a transaction, and retries the transaction if it
+// fails with a transient error.
+//
+// RunTx begins a transaction with [DB.BeginTx] and calls fn with it.
+// If fn returns nil, RunTx commits the transaction. If fn returns an
+// error or panics, RunTx rolls the transaction back. fn must not commit
+// or roll back the transaction itself.
+//
+// If fn or the commit fails with an error that the driver classifies as
+// retryable, by implementing [driver.ErrorClassifier], RunTx waits for
+// a short random delay, which increases with each attempt, and runs the
+// transaction again, up to 10 times in all. Such errors include the
+// serialization failures of transactions at [LevelSerializable] and
+// deadlocks. As fn may be called more than once, it should not have
+// effects outside the transaction. A transaction that has been committed
+// is never run again.
+//
+// RunTx stops retrying when ctx is done. It returns nil if the
+// transaction was committed, and otherwise the error of the last attempt.
+func (db *DB) RunTx(ctx context.Context, opts *TxOptions, fn func(tx *Tx) error) error {
+	backoff := minTxBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := db.runTx(ctx, opts, fn)
+		if err == nil || !retryable || attempt == maxTxAttempts || ctx.Err() != nil {
+			return err
+		}
+		timer := time.NewTimer(backoff/2 + rand.N(backoff/2))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, maxTxBackoff)
+	}
+}
+
+// runTx runs fn in a transaction once. It returns the error the
+// transaction failed with, if any, and whether the driver classifies
+// the error as retryable.
+func (db *DB) runTx(ctx context.Context, opts *TxOptions, fn func(tx *Tx) error) (retryable bool, err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+	classifier, _ := tx.dc.ci.(driver.ErrorClassifier)
+	returned := false
+	defer func() {
+		if !returned {
+			// fn panicked.
+			tx.Rollback()
+		}
+	}()
+	err = fn(tx)
+	returned = true
+	if err != nil {
+		tx.Rollback()
+	} else {
+		err = tx.Commit()
+	}
+	if err != nil && classifier != nil {
+		retryable = classifier.IsRetryable(err)
+	}
+	return retryable, err
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+var errSerialization = errors.New("serialization failure")
+
+type retryConnector struct {
+	fakeConnector
+}
+
+func (c *retryConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.fakeConnector.Connect(ctx)
+	fc := getFakeConn(conn)
+	return &retryConn{fc}, err
+}
+
+// retryConn is a Conn that classifies errSerialization as retryable.
+type retryConn struct {
+	*fakeConn
+}
+
+func (c *retryConn) IsRetryable(err error) bool {
+	return errors.Is(err, errSerialization)
+}
+
+// testRunTx runs f in a synctest bubble with a database whose
+// driver implements ErrorClassifier.
+func testRunTx(t *testing.T, f func(t *testing.T, db *DB)) {
+	synctest.Test(t, func(t *testing.T) {
+		db := OpenDB(&retryConnector{fakeConnector{name: fakeDBName}})
+		if _, err := db.Exec("WIPE"); err != nil {
+			t.Fatalf("exec wipe: %v", err)
+		}
+		t.Cleanup(func() {
+			closeDB(t, db)
+		})
+		populate(t, db, "people")
+		f(t, db)
+	})
+}
+
+// txEvents returns the begin, commit and rollback events recorded by h.
+// (The fake driver does not undo the changes of rolled back transactions.)
+func txEvents(h *recordingHook) []string {
+	return slices.DeleteFunc(h.take(), func(e string) bool {
+		return e != "begin" && e != "commit" && e != "rollback"
+	})
+}
+
+func TestRunTxRetry(t *testing.T) {
+	testRunTx(t, func(t *testing.T, db *DB) {
+		h := &recordingHook{t: t}
+		db.SetHook(h)
+		attempts := 0
+		start := time.Now()
+		err := db.RunTx(t.Context(), nil, func(tx *Tx) error {
+			attempts++
+			if _, err := tx.Exec("INSERT|people|name=Dan,age=?", 4); err != nil {
+				return err
+			}
+			if attempts < 3 {
+				return fmt.Errorf("insert: %w", errSerialization)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("RunTx: %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("transaction run %d times, want 3", attempts)
+		}
+		if d := time.Since(start); d < minTxBackoff/2+minTxBackoff {
+			t.Errorf("retried after %v, want backoff", d)
+		}
+		want := []string{"begin", "rollback", "begin", "rollback", "begin", "commit"}
+		if got := txEvents(h); !slices.Equal(got, want) {
+			t.Errorf("transactions: %q, want %q", got, want)
+		}
+		if n := db.Stats().InUse; n != 0 {
+			t.Errorf("%d connections in use after RunTx, want 0", n)
+		}
+	})
+}
+
+func TestRunTxMaxAttempts(t *testing.T) {
+	testRunTx(t, func(t *testing.T, db *DB) {
+		attempts := 0
+		err := db.RunTx(t.Context(), nil, func(tx *Tx) error {
+			attempts++
+			return errSerialization
+		})
+		if err != errSerialization {
+			t.Errorf("RunTx = %v, want %v", err, errSerialization)
+		}
+		if attempts != maxTxAttempts {
+			t.Errorf("transaction run %d times, want %d", attempts, maxTxAttempts)
+		}
+	})
+}
+
+func TestRunTxNotRetryable(t *testing.T) {
+	testRunTx(t, func(t *testing.T, db *DB) {
+		h := &recordingHook{t: t}
+		db.SetHook(h)
+		errFailed := errors.New("failed")
+		attempts := 0
+		err := db.RunTx(t.Context(), nil, func(tx *Tx) error {
+			attempts++
+			if _, err := tx.Exec("INSERT|people|name=Dan,age=?", 4); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if err != errFailed || attempts != 1 {
+			t.Errorf("RunTx = %v after %d attempts, want %v after 1", err, attempts, errFailed)
+		}
+		if got, want := txEvents(h), []string{"begin", "rollback"}; !slices.Equal(got, want) {
+			t.Errorf("transactions: %q, want %q", got, want)
+		}
+	})
+}
+
+// Errors are not retried if the driver does not classify them.
+func TestRunTxNoClassifier(t *testing.T) {
+	testDatabase(t, testRunTxNoClassifier)
+}
+func testRunTxNoClassifier(t *testing.T, db *DB) {
+	attempts := 0
+	err := db.RunTx(t.Context(), nil, func(tx *Tx) error {
+		attempts++
+		return errSerialization
+	})
+	if err != errSerialization || attempts != 1 {
+		t.Errorf("RunTx = %v after %d attempts, want %v after 1", err, attempts, errSerialization)
+	}
+}
+
+func TestRunTxContextCanceled(t *testing.T) {
+	testRunTx(t, func(t *testing.T, db *DB) {
+		ctx, cancel := context.WithCancel(t.Context())
+		attempts := 0
+		err := db.RunTx(ctx, nil, func(tx *Tx) error {
+			attempts++
+			time.AfterFunc(time.Millisecond, cancel)
+			return errSerialization
+		})
+		if err != errSerialization || attempts != 1 {
+			t.Errorf("RunTx = %v after %d attempts, want %v after 1", err, attempts, errSerialization)
+		}
+	})
+}
+
+func TestRunTxPanic(t *testing.T) {
+	testRunTx(t, func(t *testing.T, db *DB) {
+		h := &recordingHook{t: t}
+		db.SetHook(h)
+		func() {
+			defer func() {
+				if p := recover(); p != "boom" {
+					t.Errorf("recovered %v, want boom", p)
+				}
+			}()
+			db.RunTx(t.Context(), nil, func(tx *Tx) error {
+				if _, err := tx.Exec("INSERT|people|name=Dan,age=?", 4); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+		if got, want := txEvents(h), []string{"begin", "rollback"}; !slices.Equal(got, want) {
+			t.Errorf("transactions: %q, want %q", got, want)
+		}
+		if n := db.Stats().InUse; n != 0 {
+			t.Errorf("%d connections in use after panic, want 0", n)
+		}
+	})
+}
@@ -542,6 +542,10 @@ type DB struct {
 	maxIdleTimeClosed int64 // Total number of connections closed due to idle time.
 	maxLifetimeClosed int64 // Total number of connections closed due to max connection lifetime limit.
 
+	hook          atomic.Pointer[hookHolder]    // set by SetHook
+	connCallbacks atomic.Pointer[ConnCallbacks] // set by SetConnCallbacks
+	nextConnID    atomic.Uint64                 // ID of the last connection opened
+
 	stop func() // stop cancels the connection opener.
 }
 
@@ -564,6 +568,8 @@ const (
 type driverConn struct {
 	db        *DB
 	createdAt time.Time
+	id        uint64       // see ConnInfo.ID
+	queries   atomic.Int64 // number of statements executed
 
 	sync.Mutex  // guards following
 	ci          driver.Conn
@@ -573,10 +579,12 @@ type driverConn struct {
 	openStmt    map[*driverStmt]bool
 
 	// guarded by db.mu
-	inUse      bool
-	dbmuClosed bool      // same as closed, but guarded by db.mu, for removeClosedStmtLocked
-	returnedAt time.Time // Time the connection was created or returned.
-	onPut      []func()  // code (with db.mu held) run when conn is next returned
+	inUse       bool
+	dbmuClosed  bool            // same as closed, but guarded by db.mu, for removeClosedStmtLocked
+	returnedAt  time.Time       // Time the connection was created or returned.
+	onPut       []func()        // code (with db.mu held) run when conn is next returned
+	uses        int64           // number of times the conn was taken from the pool
+	closeReason ConnCloseReason // why the conn is closed, or 0 if it is open
 }
 
 func (dc *driverConn) releaseConn(err error) {
@@ -598,17 +606,18 @@ func (dc *driverConn) expired(timeout time.Duration) bool {
 
 // resetSession checks if the driver connection needs the
 // session to be reset and if required, resets it.
-func (dc *driverConn) resetSession(ctx context.Context) error {
+// It reports whether the session was reset.
+func (dc *driverConn) resetSession(ctx context.Context) (reset bool, err error) {
 	dc.Lock()
 	defer dc.Unlock()
 
 	if !dc.needReset {
-		return nil
+		return false, nil
 	}
 	if cr, ok := dc.ci.(driver.SessionResetter); ok {
-		return cr.ResetSession(ctx)
+		return true, cr.ResetSession(ctx)
 	}
-	return nil
+	return false, nil
 }
 
 // validateConnection checks if the connection is valid and can
@@ -629,7 +638,9 @@ func (dc *driverConn) validateConnection(needsReset bool) bool {
 // prepareLocked prepares the query on dc. When cg == nil the dc must keep track of
 // the prepared statements in a pool.
 func (dc *driverConn) prepareLocked(ctx context.Context, cg stmtConnGrabber, query string) (*driverStmt, error) {
+	ctx, e := dc.db.startHook(ctx, HookPrepare, query, nil)
 	si, err := ctxDriverPrepare(ctx, dc.ci, query)
+	e.end(err)
 	if err != nil {
 		return nil, err
 	}
@@ -659,6 +670,9 @@ func (dc *driverConn) closeDBLocked() func() error {
 		return func() error { return errors.New("sql: duplicate driverConn close") }
 	}
 	dc.closed = true
+	if dc.closeReason == 0 {
+		dc.closeReason = CloseDBClosed
+	}
 	return dc.db.removeDepLocked(dc, dc)
 }
 
@@ -679,6 +693,14 @@ func (dc *driverConn) Close() error {
 	return fn()
 }
 
+// closeFor closes dc, which the pool no longer holds, for reason.
+func (dc *driverConn) closeFor(reason ConnCloseReason) error {
+	dc.db.mu.Lock()
+	dc.closeReason = reason
+	dc.db.mu.Unlock()
+	return dc.Close()
+}
+
 func (dc *driverConn) finalClose() error {
 	var err error
 
@@ -704,9 +726,11 @@ func (dc *driverConn) finalClose() error {
 	dc.db.mu.Lock()
 	dc.db.numOpen--
 	dc.db.maybeOpenNewConnections()
+	info, reason := dc.infoLocked(), dc.closeReason
 	dc.db.mu.Unlock()
 
 	dc.db.numClosed.Add(1)
+	dc.db.connClosed(info, reason)
 	return err
 }
 
@@ -1017,6 +1041,9 @@ func (db *DB) SetMaxIdleConns(n int) {
 		db.freeConn = db.freeConn[:maxIdle]
 	}
 	db.maxIdleClosed += int64(len(closing))
+	for _, c := range closing {
+		c.closeReason = CloseMaxIdleConns
+	}
 	db.mu.Unlock()
 	for _, c := range closing {
 		c.Close()
@@ -1158,6 +1185,9 @@ func (db *DB) connectionCleanerRunLocked(d time.Duration) (time.Duration, []*dri
 				closing = db.freeConn[:i:i]
 				db.freeConn = db.freeConn[i:]
 				idleClosing = int64(len(closing))
+				for _, c := range closing {
+					c.closeReason = CloseMaxIdleTime
+				}
 				db.maxIdleTimeClosed += idleClosing
 				break
 			}
@@ -1178,6 +1208,7 @@ func (db *DB) connectionCleanerRunLocked(d time.Duration) (time.Duration, []*dri
 		for i := 0; i < len(db.freeConn); i++ {
 			c := db.freeConn[i]
 			if c.createdAt.Before(expiredSince) {
+				c.closeReason = CloseMaxLifetime
 				closing = append(closing, c)
 
 				last := len(db.freeConn) - 1
@@ -1277,34 +1308,51 @@ func (db *DB) openNewConnection(ctx context.Context) {
 	// maybeOpenNewConnections has already executed db.numOpen++ before it sent
 	// on db.openerCh. This function must execute db.numOpen-- if the
 	// connection fails or is closed before returning.
-	ci, err := db.connector.Connect(ctx)
+	hctx, e := db.startHook(ctx, HookConnect, "", nil)
+	ci, err := db.connector.Connect(hctx)
+	e.end(err)
+	var dc *driverConn
+	if err == nil {
+		dc = &driverConn{
+			db:         db,
+			createdAt:  time.Now(),
+			returnedAt: time.Now(),
+			ci:         ci,
+			id:         db.nextConnID.Add(1),
+		}
+		db.connOpened(dc)
+	}
 	db.mu.Lock()
-	defer db.mu.Unlock()
 	if db.closed {
-		if err == nil {
-			ci.Close()
-		}
 		db.numOpen--
+		if err != nil {
+			db.mu.Unlock()
+			return
+		}
+		info := dc.infoLocked()
+		db.mu.Unlock()
+		ci.Close()
+		db.connClosed(info, CloseDBClosed)
 		return
 	}
 	if err != nil {
 		db.numOpen--
 		db.putConnDBLocked(nil, err)
 		db.maybeOpenNewConnections()
+		db.mu.Unlock()
 		return
 	}
-	dc := &driverConn{
-		db:         db,
-		createdAt:  time.Now(),
-		returnedAt: time.Now(),
-		ci:         ci,
-	}
 	if db.putConnDBLocked(dc, err) {
 		db.addDepLocked(dc, dc)
-	} else {
-		db.numOpen--
-		ci.Close()
+		db.mu.Unlock()
+		return
 	}
+	reason := db.closePoolReasonLocked()
+	db.numOpen--
+	info := dc.infoLocked()
+	db.mu.Unlock()
+	ci.Close()
+	db.connClosed(info, reason)
 }
 
 // connRequest represents one request for a new connection
@@ -1343,15 +1391,19 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 		conn.inUse = true
 		if conn.expired(lifetime) {
 			db.maxLifetimeClosed++
+			conn.closeReason = CloseMaxLifetime
 			db.mu.Unlock()
 			conn.Close()
 			return nil, driver.ErrBadConn
 		}
+		conn.uses++
 		db.mu.Unlock()
 
 		// Reset the session if required.
-		if err := conn.resetSession(ctx); errors.Is(err, driver.ErrBadConn) {
-			conn.Close()
+		reset, err := conn.resetSession(ctx)
+		db.connTaken(conn, reset, err)
+		if errors.Is(err, driver.ErrBadConn) {
+			conn.closeFor(CloseResetFailed)
 			return nil, err
 		}
 
@@ -1369,6 +1421,7 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 		db.mu.Unlock()
 
 		waitStart := time.Now()
+		_, e := db.startHook(ctx, HookWait, "", nil)
 
 		// Timeout the connection request with the context.
 		select {
@@ -1380,6 +1433,7 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			db.mu.Unlock()
 
 			db.waitDuration.Add(int64(time.Since(waitStart)))
+			e.end(ctx.Err())
 
 			// If we failed to delete it, that means either the DB was closed or
 			// something else grabbed it and is about to send on it.
@@ -1403,8 +1457,10 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			db.waitDuration.Add(int64(time.Since(waitStart)))
 
 			if !ok {
+				e.end(errDBClosed)
 				return nil, errDBClosed
 			}
+			e.end(ret.err)
 			// Only check if the connection is expired if the strategy is cachedOrNewConns.
 			// If we require a new connection, just re-use the connection without looking
 			// at the expiry time. If it is expired, it will be checked when it is placed
@@ -1414,6 +1470,7 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			if strategy == cachedOrNewConn && ret.err == nil && ret.conn.expired(lifetime) {
 				db.mu.Lock()
 				db.maxLifetimeClosed++
+				ret.conn.closeReason = CloseMaxLifetime
 				db.mu.Unlock()
 				ret.conn.Close()
 				return nil, driver.ErrBadConn
@@ -1423,8 +1480,10 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 			}
 
 			// Reset the session if required.
-			if err := ret.conn.resetSession(ctx); errors.Is(err, driver.ErrBadConn) {
-				ret.conn.Close()
+			reset, err := ret.conn.resetSession(ctx)
+			db.connTaken(ret.conn, reset, err)
+			if errors.Is(err, driver.ErrBadConn) {
+				ret.conn.closeFor(CloseResetFailed)
 				return nil, err
 			}
 			return ret.conn, ret.err
@@ -1433,7 +1492,9 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 
 	db.numOpen++ // optimistically
 	db.mu.Unlock()
-	ci, err := db.connector.Connect(ctx)
+	hctx, e := db.startHook(ctx, HookConnect, "", nil)
+	ci, err := db.connector.Connect(hctx)
+	e.end(err)
 	if err != nil {
 		db.mu.Lock()
 		db.numOpen-- // correct for earlier optimism
@@ -1441,14 +1502,17 @@ func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn
 		db.mu.Unlock()
 		return nil, err
 	}
-	db.mu.Lock()
 	dc := &driverConn{
 		db:         db,
 		createdAt:  time.Now(),
 		returnedAt: time.Now(),
 		ci:         ci,
 		inUse:      true,
+		id:         db.nextConnID.Add(1),
+		uses:       1,
 	}
+	db.connOpened(dc)
+	db.mu.Lock()
 	db.addDepLocked(dc, dc)
 	db.mu.Unlock()
 	return dc, nil
@@ -1484,9 +1548,11 @@ const debugGetPut = false
 // putConn adds a connection to the db's free pool.
 // err is optionally the last error that occurred on this connection.
 func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
+	reason := CloseBadConn
 	if !errors.Is(err, driver.ErrBadConn) {
 		if !dc.validateConnection(resetSession) {
 			err = driver.ErrBadConn
+			reason = CloseInvalid
 		}
 	}
 	db.mu.Lock()
@@ -1501,6 +1567,7 @@ func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
 	if !errors.Is(err, driver.ErrBadConn) && dc.expired(db.maxLifetime) {
 		db.maxLifetimeClosed++
 		err = driver.ErrBadConn
+		reason = CloseMaxLifetime
 	}
 	if debugGetPut {
 		db.lastPut[dc] = stack()
@@ -1518,6 +1585,7 @@ func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
 		// Since the conn is considered bad and is being discarded, treat it
 		// as closed. Don't decrement the open count here, finalClose will
 		// take care of that.
+		dc.closeReason = reason
 		db.maybeOpenNewConnections()
 		db.mu.Unlock()
 		dc.Close()
@@ -1527,6 +1595,9 @@ func (db *DB) putConn(dc *driverConn, err error, resetSession bool) {
 		putConnHook(db, dc)
 	}
 	added := db.putConnDBLocked(dc, nil)
+	if !added {
+		dc.closeReason = db.closePoolReasonLocked()
+	}
 	db.mu.Unlock()
 
 	if !added {
@@ -1554,6 +1625,7 @@ func (db *DB) putConnDBLocked(dc *driverConn, err error) bool {
 	if req, ok := db.connRequests.TakeRandom(); ok {
 		if err == nil {
 			dc.inUse = true
+			dc.uses++
 		}
 		req <- connRequest{
 			conn: dc,
@@ -1699,9 +1771,12 @@ func (db *DB) exec(ctx context.Context, query string, args []any, strategy connR
 }
 
 func (db *DB) execDC(ctx context.Context, dc *driverConn, release func(error), query string, args []any) (res Result, err error) {
+	ctx, e := db.startHook(ctx, HookExec, query, args)
 	defer func() {
+		e.endExec(res, err)
 		release(err)
 	}()
+	dc.queries.Add(1)
 	execerCtx, ok := dc.ci.(driver.ExecerContext)
 	var execer driver.Execer
 	if !ok {
@@ -1773,7 +1848,12 @@ func (db *DB) query(ctx context.Context, query string, args []any, strategy conn
 // The connection gets released by the releaseConn function.
 // The ctx context is from a query method and the txctx context is from an
 // optional transaction context.
-func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []any) (*Rows, error) {
+func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []any) (_ *Rows, err error) {
+	hctx, e := db.startHook(ctx, HookQuery, query, args)
+	defer func() {
+		e.end(err)
+	}()
+	dc.queries.Add(1)
 	queryerCtx, ok := dc.ci.(driver.QueryerContext)
 	var queryer driver.Queryer
 	if !ok {
@@ -1782,13 +1862,12 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 	if ok {
 		var nvdargs []driver.NamedValue
 		var rowsi driver.Rows
-		var err error
 		withLock(dc, func() {
 			nvdargs, err = driverArgsConnLocked(dc.ci, nil, args)
 			if err != nil {
 				return
 			}
-			rowsi, err = ctxDriverQuery(ctx, queryerCtx, queryer, query, nvdargs)
+			rowsi, err = ctxDriverQuery(hctx, queryerCtx, queryer, query, nvdargs)
 		})
 		if err != driver.ErrSkip {
 			if err != nil {
@@ -1808,9 +1887,8 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 	}
 
 	var si driver.Stmt
-	var err error
 	withLock(dc, func() {
-		si, err = ctxDriverPrepare(ctx, dc.ci, query)
+		si, err = ctxDriverPrepare(hctx, dc.ci, query)
 	})
 	if err != nil {
 		releaseConn(err)
@@ -1818,7 +1896,7 @@ func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn fu
 	}
 
 	ds := &driverStmt{Locker: dc, si: si}
-	rowsi, err := rowsiFromStatement(ctx, dc.ci, ds, args...)
+	rowsi, err := rowsiFromStatement(hctx, dc.ci, ds, args...)
 	if err != nil {
 		ds.Close()
 		releaseConn(err)
@@ -1904,12 +1982,14 @@ func (db *DB) begin(ctx context.Context, opts *TxOptions, strategy connReuseStra
 func (db *DB) beginDC(ctx context.Context, dc *driverConn, release func(error), opts *TxOptions) (tx *Tx, err error) {
 	var txi driver.Tx
 	keepConnOnRollback := false
+	hctx, e := db.startHook(ctx, HookBegin, "", nil)
 	withLock(dc, func() {
 		_, hasSessionResetter := dc.ci.(driver.SessionResetter)
 		_, hasConnectionValidator := dc.ci.(driver.Validator)
 		keepConnOnRollback = hasSessionResetter && hasConnectionValidator
-		txi, err = ctxDriverBegin(ctx, opts, dc.ci)
+		txi, err = ctxDriverBegin(hctx, opts, dc.ci)
 	})
+	e.end(err)
 	if err != nil {
 		release(err)
 		return nil, err
@@ -2314,9 +2394,11 @@ func (tx *Tx) Commit() error {
 	tx.closemu.Unlock()
 
 	var err error
+	_, e := tx.db.startHook(context.WithoutCancel(tx.ctx), HookCommit, "", nil)
 	withLock(tx.dc, func() {
 		err = tx.txi.Commit()
 	})
+	e.end(err)
 	if !errors.Is(err, driver.ErrBadConn) {
 		tx.closePrepared()
 	}
@@ -2346,9 +2428,11 @@ func (tx *Tx) rollback(discardConn bool) error {
 	tx.closemu.Unlock()
 
 	var err error
+	_, e := tx.db.startHook(context.WithoutCancel(tx.ctx), HookRollback, "", nil)
 	withLock(tx.dc, func() {
 		err = tx.txi.Rollback()
 	})
+	e.end(err)
 	if !errors.Is(err, driver.ErrBadConn) {
 		tx.closePrepared()
 	}
@@ -2656,7 +2740,10 @@ func (s *Stmt) ExecContext(ctx context.Context, args ...any) (Result, error) {
 			return err
 		}
 
-		res, err = resultFromStatement(ctx, dc.ci, ds, args...)
+		hctx, e := s.db.startHook(ctx, HookExec, s.query, args)
+		dc.queries.Add(1)
+		res, err = resultFromStatement(hctx, dc.ci, ds, args...)
+		e.endExec(res, err)
 		releaseConn(err)
 		return err
 	})
@@ -2800,7 +2887,10 @@ func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*Rows, error) {
 			return err
 		}
 
-		rowsi, err = rowsiFromStatement(ctx, dc.ci, ds, args...)
+		hctx, e := s.db.startHook(ctx, HookQuery, s.query, args)
+		dc.queries.Add(1)
+		rowsi, err = rowsiFromStatement(hctx, dc.ci, ds, args...)
+		e.end(err)
 		if err == nil {
 			// Note: ownership of ci passes to the *Rows, to be freed
 			// with releaseConn.
@@ -0,0 +1,265 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScanStruct copies the columns in the current row into the fields of
+// the struct pointed at by dest.
+//
+// Each column is stored in the field whose name matches the column
+// name, ignoring case. The name of a field is given by its "db" tag,
+// if any, and is otherwise the field's Go name. Fields tagged `db:"-"`
+// and unexported fields are ignored. The fields of an embedded struct
+// without a "db" tag are treated as fields of the outer struct, unless
+// the embedded struct implements [Scanner]; a nil embedded struct
+// pointer is allocated as needed. As with encoding/json, a field at a
+// shallower depth hides fields of the same name in embedded structs,
+// and fields of the same name at the same depth are ignored.
+//
+// Columns are converted to field types as described in [Rows.Scan],
+// so fields may be pointers, implement [Scanner], or be of a type such
+// as [NullString] or [Null] to hold nullable columns.
+//
+// ScanStruct returns an error if a column has no matching field.
+// Fields with no matching column are left unchanged.
+func (rs *Rows) ScanStruct(dest any) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sql: ScanStruct destination must be a non-nil pointer to a struct, not %T", dest)
+	}
+	s, err := newStructScanner(rs, v.Type().Elem())
+	if err != nil {
+		return err
+	}
+	return rs.Scan(s.dest(v.Elem())...)
+}
+
+// ScanStruct copies the columns from the matched row into the fields of
+// the struct pointed at by dest. See the documentation on [Rows.ScanStruct]
+// for details. If more than one row matches the query, ScanStruct uses
+// the first row and discards the rest. If no row matches the query,
+// ScanStruct returns [ErrNoRows].
+func (r *Row) ScanStruct(dest any) error {
+	if r.err != nil {
+		return r.err
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		r.rows.Close()
+		return fmt.Errorf("sql: ScanStruct destination must be a non-nil pointer to a struct, not %T", dest)
+	}
+	s, err := newStructScanner(r.rows, v.Type().Elem())
+	if err != nil {
+		r.rows.Close()
+		return err
+	}
+	return r.Scan(s.dest(v.Elem())...)
+}
+
+// ScanAll returns an iterator over the rows of a query result,
+// scanned into values of type T. It accepts the results of a query
+// method, such as [DB.QueryContext], [Tx.QueryContext],
+// [Conn.QueryContext] or [Stmt.QueryContext], directly:
+//
+//	for p, err := range sql.ScanAll[Person](db.QueryContext(ctx, "SELECT name, age FROM people")) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(p.Name, p.Age)
+//	}
+//
+// If T is a struct type, other than [time.Time], whose pointer does not
+// implement [Scanner], each row is scanned into a T as by [Rows.ScanStruct].
+// Otherwise, the query must return a single column, which is scanned
+// into a T as by [Rows.Scan].
+//
+// If err is non-nil, the iterator yields only err.
+// If a row cannot be scanned, or the rows end with an error reported by
+// [Rows.Err], the iterator yields the error and stops.
+// The iterator closes rows when it stops, including when the loop is
+// exited early. It must not be used more than once.
+func ScanAll[T any](rows *Rows, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+		var s *structScanner
+		if t := reflect.TypeFor[T](); isScanStruct(t) {
+			var err error
+			if s, err = newStructScanner(rows, t); err != nil {
+				yield(zero, err)
+				return
+			}
+		}
+		for rows.Next() {
+			var v T
+			var err error
+			if s != nil {
+				err = rows.Scan(s.dest(reflect.ValueOf(&v).Elem())...)
+			} else {
+				err = rows.Scan(&v)
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+var (
+	scannerType = reflect.TypeFor[Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// isScanStruct reports whether values of type t are scanned field by field.
+func isScanStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(scannerType)
+}
+
+// A structScanner scans the columns of a result into fields of a struct type.
+type structScanner struct {
+	index [][]int // index of the field for each column
+	ptrs  []any
+}
+
+func newStructScanner(rs *Rows, t reflect.Type) (*structScanner, error) {
+	cols, err := rs.Columns()
+	if err != nil {
+		return nil, err
+	}
+	fields := cachedStructFields(t)
+	s := &structScanner{
+		index: make([][]int, len(cols)),
+		ptrs:  make([]any, len(cols)),
+	}
+	for i, col := range cols {
+		index, ok := fields[strings.ToLower(col)]
+		if !ok {
+			return nil, fmt.Errorf("sql: no field for column %q in %v", col, t)
+		}
+		s.index[i] = index
+	}
+	return s, nil
+}
+
+// dest returns pointers to the fields of v for each column,
+// allocating embedded struct pointers as needed.
+func (s *structScanner) dest(v reflect.Value) []any {
+	for i, index := range s.index {
+		f := v
+		for j, x := range index {
+			if j > 0 && f.Kind() == reflect.Pointer {
+				if f.IsNil() {
+					f.Set(reflect.New(f.Type().Elem()))
+				}
+				f = f.Elem()
+			}
+			f = f.Field(x)
+		}
+		s.ptrs[i] = f.Addr().Interface()
+	}
+	return s.ptrs
+}
+
+var structFieldsCache sync.Map // map[reflect.Type]map[string][]int
+
+// cachedStructFields is like structFields but uses a cache.
+func cachedStructFields(t reflect.Type) map[string][]int {
+	if f, ok := structFieldsCache.Load(t); ok {
+		return f.(map[string][]int)
+	}
+	f, _ := structFieldsCache.LoadOrStore(t, structFields(t))
+	return f.(map[string][]int)
+}
+
+// structFields returns the index of each field of the struct type t
+// that can be scanned into, keyed by its lower-cased name.
+func structFields(t reflect.Type) map[string][]int {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	fields := make(map[string][]int)
+	visited := make(map[reflect.Type]bool)
+	// Walk the embedded structs breadth first, so that fields at
+	// shallower depths hide those at deeper ones.
+	for level := []embedded{{typ: t}}; len(level) > 0; {
+		var next []embedded
+		found := make(map[string][]int)
+		count := make(map[string]int)
+		for _, e := range level {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := range e.typ.NumField() {
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get("db")
+				if tag == "-" {
+					continue
+				}
+				index := append(slices.Clip(e.index), i)
+				if sf.Anonymous && tag == "" {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if isScanStruct(ft) {
+						// A pointer to an unexported type cannot be allocated.
+						if sf.IsExported() || sf.Type.Kind() != reflect.Pointer {
+							next = append(next, embedded{ft, index})
+						}
+						continue
+					}
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				name := tag
+				if name == "" {
+					name = sf.Name
+				}
+				name = strings.ToLower(name)
+				found[name] = index
+				count[name]++
+			}
+		}
+		for name, index := range found {
+			if _, ok := fields[name]; ok {
+				continue
+			}
+			if count[name] > 1 {
+				index = nil // ambiguous; hides deeper fields too
+			}
+			fields[name] = index
+		}
+		level = next
+	}
+	for name, index := range fields {
+		if index == nil {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
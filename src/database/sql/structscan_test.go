@@ -0,0 +1,223 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PersonName struct {
+	Name string
+}
+
+type person struct {
+	*PersonName
+	Years    int32    `db:"age"`
+	Birthday NullTime `db:"bdate"`
+	Photo    []byte
+	Ignored  string `db:"-"`
+}
+
+func TestRowsScanStruct(t *testing.T) {
+	testDatabase(t, testRowsScanStruct)
+}
+func testRowsScanStruct(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	rows, err := db.Query("SELECT|people|age,name,bdate,photo|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []person
+	for rows.Next() {
+		var p person
+		if err := rows.ScanStruct(&p); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []person{
+		{PersonName: &PersonName{"Alice"}, Years: 1, Photo: []byte("APHOTO")},
+		{PersonName: &PersonName{"Bob"}, Years: 2, Photo: []byte("BPHOTO")},
+		{PersonName: &PersonName{"Chris"}, Years: 3, Birthday: NullTime{chrisBirthday, true}, Photo: []byte("CPHOTO")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanned %+v, want %+v", got, want)
+	}
+}
+
+func TestRowScanStruct(t *testing.T) {
+	testDatabase(t, testRowScanStruct)
+}
+func testRowScanStruct(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	var p person
+	if err := db.QueryRow("SELECT|people|age,name|age=?", 2).ScanStruct(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Bob" || p.Years != 2 {
+		t.Errorf("scanned %+v, want Bob, 2", p)
+	}
+	if err := db.QueryRow("SELECT|people|age,name|age=?", 4).ScanStruct(&p); err != ErrNoRows {
+		t.Errorf("scanning no rows: %v, want ErrNoRows", err)
+	}
+	err := db.QueryRow("SELECT|people|age,dead|age=?", 2).ScanStruct(&p)
+	if err == nil || !strings.Contains(err.Error(), `no field for column "dead"`) {
+		t.Errorf("scanning column with no field: %v, want error", err)
+	}
+	if err := db.QueryRow("SELECT|people|age|age=?", 2).ScanStruct(p); err == nil {
+		t.Errorf("scanning into non-pointer: no error")
+	}
+	if n := db.Stats().InUse; n != 0 {
+		t.Errorf("%d connections in use after Row.ScanStruct, want 0", n)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	testDatabase(t, testScanAll)
+}
+func testScanAll(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	ctx := context.Background()
+	const query = "SELECT|people|age,name|"
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	for _, tt := range []struct {
+		name  string
+		query func() (*Rows, error)
+	}{
+		{"DB", func() (*Rows, error) { return db.QueryContext(ctx, query) }},
+		{"Conn", func() (*Rows, error) { return conn.QueryContext(ctx, query) }},
+		{"Tx", func() (*Rows, error) { return tx.QueryContext(ctx, query) }},
+		{"Stmt", func() (*Rows, error) { return stmt.QueryContext(ctx) }},
+	} {
+		var names []string
+		for p, err := range ScanAll[person](tt.query()) {
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			names = append(names, p.Name)
+		}
+		if want := []string{"Alice", "Bob", "Chris"}; !slices.Equal(names, want) {
+			t.Errorf("%s: scanned names %q, want %q", tt.name, names, want)
+		}
+	}
+}
+
+func TestScanAllColumn(t *testing.T) {
+	testDatabase(t, testScanAllColumn)
+}
+func testScanAllColumn(t *testing.T, db *DB) {
+	populate(t, db, "people")
+	var ages []int
+	for age, err := range ScanAll[int](db.Query("SELECT|people|age|")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ages = append(ages, age)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(ages, want) {
+		t.Errorf("scanned ages %v, want %v", ages, want)
+	}
+	var birthdays []time.Time
+	for bdate, err := range ScanAll[time.Time](db.Query("SELECT|people|bdate|age=?", 3)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		birthdays = append(birthdays, bdate)
+	}
+	if want := []time.Time{chrisBirthday}; !slices.Equal(birthdays, want) {
+		t.Errorf("scanned birthdays %v, want %v", birthdays, want)
+	}
+}
+
+func TestScanAllErrors(t *testing.T) {
+	testDatabase(t, testScanAllErrors)
+}
+func testScanAllErrors(t *testing.T, db *DB) {
+	populate(t, db, "people")
+
+	queryErr := errors.New("query failed")
+	for _, err := range ScanAll[person](nil, queryErr) {
+		if err != queryErr {
+			t.Errorf("yielded %v, want the query error", err)
+		}
+	}
+
+	// A scan error stops the iteration and closes the rows.
+	n := 0
+	for _, err := range ScanAll[int](db.Query("SELECT|people|name|")) {
+		n++
+		if err == nil {
+			t.Errorf("scanning a name into an int: no error")
+		}
+	}
+	if n != 1 {
+		t.Errorf("iterator yielded %d times after a scan error, want 1", n)
+	}
+
+	// Breaking out of the loop closes the rows.
+	for _, err := range ScanAll[person](db.Query("SELECT|people|age,name|")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+	if n := db.Stats().InUse; n != 0 {
+		t.Errorf("%d connections in use after breaking out of ScanAll loop, want 0", n)
+	}
+}
+
+func TestStructFields(t *testing.T) {
+	type inner struct {
+		A, B int
+	}
+	type Other struct {
+		B int
+	}
+	type outer struct {
+		inner
+		*Other
+		A      int    // hides inner.A
+		Tagged string `db:"custom_name"`
+		hidden int
+		Null   NullString
+		NullT  Null[int]
+	}
+	got := structFields(reflect.TypeFor[outer]())
+	want := map[string][]int{
+		"a":           {2},
+		"custom_name": {3},
+		"null":        {5},
+		"nullt":       {6},
+		// "b" is ambiguous.
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("structFields = %v, want %v", got, want)
+	}
+}
@@ -18,12 +18,12 @@ package elf
 import (
 	"bytes"
 	"compress/zlib"
+	"compress/zstd"
 	"debug/dwarf"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"internal/saferio"
-	"internal/zstd"
 	"io"
 	"math"
 	"os"
@@ -0,0 +1,221 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A Decoder reads records from a [Reader] and stores them in structs.
+//
+// The first record read is the header, which names the column of each
+// field. Each struct field is decoded from the column named by the
+// field's csv tag, or by the field name if the field has no tag.
+// Columns are matched to names exactly, or else without regard to case.
+// Fields with the tag "-" are ignored, as are fields that have no
+// column and columns that have no field.
+// The fields of embedded structs are promoted as in encoding/json.
+//
+// A Decoder decodes fields of string, boolean, integer, floating-point,
+// and complex types, and of pointers to those types. It decodes fields of
+// types that implement [encoding.TextUnmarshaler], such as [time.Time],
+// by calling UnmarshalText. An empty field always decodes as the zero
+// value, or as nil for a pointer.
+type Decoder struct {
+	r      *Reader
+	header []string
+	err    error // sticky error from reading the header
+
+	// The columns of the last struct type decoded.
+	typ     reflect.Type
+	columns []int // index of field i's column, or -1
+}
+
+// NewDecoder returns a new decoder that reads records from r.
+func NewDecoder(r *Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Header returns the header record, reading it if necessary.
+func (d *Decoder) Header() ([]string, error) {
+	if d.header == nil && d.err == nil {
+		header, err := d.r.Read()
+		if err == nil && len(header) == 0 {
+			err = errors.New("csv: empty header")
+		}
+		if err != nil {
+			d.err = err
+			return nil, err
+		}
+		d.header = append([]string(nil), header...)
+	}
+	return d.header, d.err
+}
+
+// Decode reads the next record and stores it in the struct pointed to by v.
+// At the end of the input, Decode returns [io.EOF].
+//
+// If a field cannot be decoded, Decode returns a [*DecodeError]
+// and leaves the remaining fields of v unchanged.
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("csv: Decode of %T, not a non-nil pointer to struct", v)
+	}
+	rv = rv.Elem()
+	if _, err := d.Header(); err != nil {
+		return err
+	}
+	fields := cachedFields(rv.Type())
+	if fields.err != nil {
+		return fields.err
+	}
+	if d.typ != rv.Type() {
+		d.typ = rv.Type()
+		d.columns = d.matchColumns(fields.list)
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	for i, f := range fields.list {
+		col := d.columns[i]
+		if col < 0 || col >= len(record) {
+			continue
+		}
+		if err := decodeField(rv, f, record[col]); err != nil {
+			line, column := d.r.FieldPos(col)
+			return &DecodeError{
+				Line:   line,
+				Column: column,
+				Header: d.header[col],
+				Field:  f.goName,
+				Type:   f.typ,
+				Err:    err,
+			}
+		}
+	}
+	return nil
+}
+
+// matchColumns returns the index of the column
+// for each field in fields, or -1 if there is none.
+func (d *Decoder) matchColumns(fields []field) []int {
+	columns := make([]int, len(fields))
+	for i, f := range fields {
+		columns[i] = -1
+		for j, h := range d.header {
+			if h == f.name {
+				columns[i] = j
+				break
+			}
+			if columns[i] < 0 && strings.EqualFold(h, f.name) {
+				columns[i] = j
+			}
+		}
+	}
+	return columns
+}
+
+// decodeField decodes s into the field f of the struct v.
+func decodeField(v reflect.Value, f field, s string) error {
+	fv, err := fieldByIndex(v, f.index)
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		fv.SetZero()
+		return nil
+	}
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		fv = fv.Elem()
+	}
+	if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(n)
+	case reflect.Complex64, reflect.Complex128:
+		n, err := strconv.ParseComplex(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetComplex(n)
+	default:
+		return fmt.Errorf("%s does not implement encoding.TextUnmarshaler", fv.Type())
+	}
+	return nil
+}
+
+// fieldByIndex returns the nested field of v with the given index,
+// allocating any nil embedded struct pointers along the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// A DecodeError describes a field that could not be decoded
+// into a struct field by [Decoder.Decode].
+type DecodeError struct {
+	Line   int          // line of the field, starting at 1
+	Column int          // column of the field in bytes, starting at 1
+	Header string       // header of the field's column
+	Field  string       // name of the struct field, such as "Addr.City"
+	Type   reflect.Type // type of the struct field
+	Err    error        // the error from decoding the field
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("csv: cannot decode column %q on line %d, column %d into Go field %s of type %s: %v",
+		e.Header, e.Line, e.Column, e.Field, e.Type, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error { return e.Err }
@@ -0,0 +1,152 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"errors"
+	"io"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type decodeAddr struct {
+	City string `csv:"city"`
+	Zip  string
+}
+
+type decodePerson struct {
+	Name    string  `csv:"name"`
+	Age     int     `csv:"age"`
+	Score   float64 `csv:"score"`
+	Active  bool    `csv:"active"`
+	Nick    *string `csv:"nick"`
+	Joined  time.Time
+	IP      netip.Addr `csv:"ip"`
+	Ignored string     `csv:"-"`
+	private int
+	decodeAddr
+}
+
+func TestDecode(t *testing.T) {
+	const input = `name,AGE,score,active,nick,joined,ip,city,zip,extra,Ignored
+Ann,30,1.5,true,annie,2020-01-02T03:04:05Z,192.0.2.1,Paris,75001,x,y
+Bob,,,,,,,,,,
+`
+	d := NewDecoder(NewReader(strings.NewReader(input)))
+	var got []decodePerson
+	for {
+		var p decodePerson
+		err := d.Decode(&p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p)
+	}
+
+	nick := "annie"
+	want := []decodePerson{{
+		Name:       "Ann",
+		Age:        30,
+		Score:      1.5,
+		Active:     true,
+		Nick:       &nick,
+		Joined:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		IP:         netip.MustParseAddr("192.0.2.1"),
+		decodeAddr: decodeAddr{City: "Paris", Zip: "75001"},
+	}, {
+		Name: "Bob",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+
+	header, err := d.Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(header) != 11 || header[1] != "AGE" {
+		t.Errorf("Header() = %q", header)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	const input = "name,age\nAnn,30\nBob,\"x1\"\n"
+	d := NewDecoder(NewReader(strings.NewReader(input)))
+	var p decodePerson
+	if err := d.Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	err := d.Decode(&p)
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("Decode = %v, want DecodeError", err)
+	}
+	if de.Line != 3 || de.Column != 5 || de.Header != "age" || de.Field != "Age" || de.Type != reflect.TypeFor[int]() {
+		t.Errorf("DecodeError = %+v", de)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("DecodeError does not wrap strconv.ErrSyntax: %v", err)
+	}
+	const wantMsg = `csv: cannot decode column "age" on line 3, column 5 into Go field Age of type int: strconv.ParseInt: parsing "x1": invalid syntax`
+	if err.Error() != wantMsg {
+		t.Errorf("Error() = %q\nwant       %q", err.Error(), wantMsg)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	d := NewDecoder(NewReader(strings.NewReader("a\n1\n")))
+	for _, v := range []any{nil, decodePerson{}, new(int), (*decodePerson)(nil)} {
+		if err := d.Decode(v); err == nil {
+			t.Errorf("Decode(%T) succeeded", v)
+		}
+	}
+	var bad struct{ A []string }
+	if err := d.Decode(&bad); err == nil || !strings.Contains(err.Error(), "unsupported type") {
+		t.Errorf("Decode of struct with slice field = %v, want unsupported type error", err)
+	}
+
+	d = NewDecoder(NewReader(strings.NewReader("")))
+	if err := d.Decode(new(decodePerson)); err != io.EOF {
+		t.Errorf("Decode of empty input = %v, want EOF", err)
+	}
+
+	d = NewDecoder(NewReader(strings.NewReader("a,b\n1\n")))
+	if err := d.Decode(new(struct{ A, B string })); !errors.Is(err, ErrFieldCount) {
+		t.Errorf("Decode of short record = %v, want ErrFieldCount", err)
+	}
+}
+
+func TestTypeFieldsEmbedded(t *testing.T) {
+	type Inner struct {
+		A, B string
+		C    string `csv:"c"`
+	}
+	type Other struct {
+		B string
+		c string
+	}
+	type Outer struct {
+		Inner
+		*Other
+		A string
+	}
+	fields := typeFields(reflect.TypeFor[Outer]())
+	var names []string
+	for _, f := range fields.list {
+		names = append(names, f.goName+"="+f.name)
+	}
+	// A is hidden by Outer.A, and B is ambiguous.
+	want := []string{"Inner.C=c", "A=A"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("fields = %v, want %v", names, want)
+	}
+}
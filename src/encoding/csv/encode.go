@@ -0,0 +1,202 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// An Encoder writes structs as records to a [Writer].
+//
+// Before the first record, an Encoder writes a header naming the
+// column of each field. The columns are the exported fields of the
+// struct type in order, named as described for [Decoder].
+// A field with the tag option "omitempty", as in `csv:"name,omitempty"`,
+// is written as an empty string if it has the zero value.
+//
+// An Encoder encodes the types that a [Decoder] decodes. It encodes
+// fields of types that implement [encoding.TextMarshaler], such as
+// [time.Time], by calling MarshalText. A nil pointer is encoded as
+// an empty string.
+type Encoder struct {
+	w      *Writer
+	typ    reflect.Type // struct type of the records written so far
+	fields []field
+	record []string
+}
+
+// NewEncoder returns a new encoder that writes records to w.
+func NewEncoder(w *Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes v as one or more records, writing the header first
+// if this is the first call to Encode. The value v may be a struct,
+// a pointer to a struct, or a slice, array, or iterator (such as an
+// [iter.Seq]) of structs or pointers to structs. Every struct written
+// by an Encoder must have the same type. If v is an empty slice,
+// array, or iterator, Encode writes just the header for its element
+// type, so that the output can be decoded by a [Decoder].
+//
+// Encode flushes the underlying [Writer] before returning,
+// and returns any error from writing the records.
+func (e *Encoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	var err error
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			if err = e.encode(rv.Index(i)); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = e.header(rv.Type().Elem())
+		}
+	case reflect.Func:
+		if !rv.Type().CanSeq() {
+			return fmt.Errorf("csv: cannot encode %T", v)
+		}
+		for elem := range rv.Seq() {
+			if err = e.encode(elem); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			// The iterator is a func(yield func(T) bool).
+			err = e.header(rv.Type().In(0).In(0))
+		}
+	default:
+		err = e.encode(rv)
+	}
+	if err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// encode writes the struct or pointer to struct v as a record.
+func (e *Encoder) encode(v reflect.Value) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return fmt.Errorf("csv: cannot encode nil %s", v.Type())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		if !v.IsValid() {
+			return fmt.Errorf("csv: cannot encode nil")
+		}
+		return fmt.Errorf("csv: cannot encode %s, not a struct", v.Type())
+	}
+
+	if e.typ == nil {
+		if err := e.header(v.Type()); err != nil {
+			return err
+		}
+	} else if v.Type() != e.typ {
+		return fmt.Errorf("csv: cannot encode %s after %s", v.Type(), e.typ)
+	}
+	if !v.CanAddr() {
+		// Make the fields addressable, to find MarshalText
+		// methods with pointer receivers.
+		p := reflect.New(v.Type()).Elem()
+		p.Set(v)
+		v = p
+	}
+
+	for i, f := range e.fields {
+		s, err := encodeField(v, f)
+		if err != nil {
+			return fmt.Errorf("csv: encoding Go field %s of type %s: %w", f.goName, f.typ, err)
+		}
+		e.record[i] = s
+	}
+	return e.w.Write(e.record)
+}
+
+// header writes the header for records of type t, a struct or pointer
+// to struct, unless it has already been written. It writes nothing if
+// t is an interface type.
+func (e *Encoder) header(t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if e.typ != nil || t.Kind() == reflect.Interface {
+		return nil
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("csv: cannot encode %s, not a struct", t)
+	}
+	fields := cachedFields(t)
+	if fields.err != nil {
+		return fields.err
+	}
+	e.typ = t
+	e.fields = fields.list
+	e.record = make([]string, len(e.fields))
+	for i, f := range e.fields {
+		e.record[i] = f.name
+	}
+	return e.w.Write(e.record)
+}
+
+// encodeField returns the encoding of the field f of the struct v.
+func encodeField(v reflect.Value, f field) (string, error) {
+	for i, x := range f.index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				// A field of a nil embedded struct.
+				return "", nil
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	if f.omitEmpty && v.IsZero() {
+		return "", nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if m, ok := textMarshaler(v); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("%s does not implement encoding.TextMarshaler", v.Type())
+}
+
+// textMarshaler returns v as an encoding.TextMarshaler, if it implements it.
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	if v.Type().Implements(textMarshalerType) {
+		return v.Interface().(encoding.TextMarshaler), true
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		return v.Addr().Interface().(encoding.TextMarshaler), true
+	}
+	return nil, false
+}
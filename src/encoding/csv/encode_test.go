@@ -0,0 +1,136 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"io"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	nick := "annie"
+	people := []decodePerson{{
+		Name:       "Ann",
+		Age:        30,
+		Score:      1.5,
+		Active:     true,
+		Nick:       &nick,
+		Joined:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		IP:         netip.MustParseAddr("192.0.2.1"),
+		decodeAddr: decodeAddr{City: "Paris, France", Zip: "75001"},
+	}, {
+		Name: "Bob",
+	}}
+	const want = `name,age,score,active,nick,Joined,ip,city,Zip
+Ann,30,1.5,true,annie,2020-01-02T03:04:05Z,192.0.2.1,"Paris, France",75001
+Bob,0,0,false,,0001-01-01T00:00:00Z,,,
+`
+
+	for _, tt := range []struct {
+		name string
+		v    any
+	}{
+		{"slice", people},
+		{"pointers", []*decodePerson{&people[0], &people[1]}},
+		{"iterator", slices.Values(people)},
+	} {
+		var b strings.Builder
+		if err := NewEncoder(NewWriter(&b)).Encode(tt.v); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := b.String(); got != want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, got, want)
+		}
+	}
+
+	// Encode one record at a time.
+	var b strings.Builder
+	e := NewEncoder(NewWriter(&b))
+	for _, p := range people {
+		if err := e.Encode(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := b.String(); got != want {
+		t.Errorf("one at a time: got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The output decodes to the input.
+	d := NewDecoder(NewReader(strings.NewReader(want)))
+	for i := range people {
+		var p decodePerson
+		if err := d.Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 {
+			// The zero time is not encoded as an empty field.
+			p.Joined = time.Time{}
+		}
+		if !reflect.DeepEqual(p, people[i]) {
+			t.Errorf("decoded %+v, want %+v", p, people[i])
+		}
+	}
+}
+
+// Encoding no records writes the header.
+func TestEncodeEmpty(t *testing.T) {
+	const want = "name,age,score,active,nick,Joined,ip,city,Zip\n"
+	for _, tt := range []struct {
+		name string
+		v    any
+	}{
+		{"slice", []decodePerson{}},
+		{"array", [0]*decodePerson{}},
+		{"iterator", slices.Values([]decodePerson(nil))},
+	} {
+		var b strings.Builder
+		if err := NewEncoder(NewWriter(&b)).Encode(tt.v); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := b.String(); got != want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, want)
+		}
+		var p decodePerson
+		if err := NewDecoder(NewReader(strings.NewReader(b.String()))).Decode(&p); err != io.EOF {
+			t.Errorf("%s: Decode = %v, want io.EOF", tt.name, err)
+		}
+	}
+}
+
+func TestEncodeOmitEmpty(t *testing.T) {
+	type T struct {
+		A int       `csv:"a,omitempty"`
+		B time.Time `csv:"b,omitempty"`
+	}
+	var b strings.Builder
+	if err := NewEncoder(NewWriter(&b)).Encode([]T{{}, {A: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.String(), "a,b\n,\n1,\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	type A struct{ X int }
+	type B struct{ Y int }
+	e := NewEncoder(NewWriter(new(strings.Builder)))
+	if err := e.Encode(A{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(B{1}); err == nil {
+		t.Errorf("Encode of a different type succeeded")
+	}
+	for _, v := range []any{nil, 1, []int{1}, []int{}, (*A)(nil), func() {}} {
+		if err := NewEncoder(NewWriter(new(strings.Builder))).Encode(v); err == nil {
+			t.Errorf("Encode(%#v) succeeded", v)
+		}
+	}
+}
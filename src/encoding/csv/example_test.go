@@ -129,3 +129,52 @@ func ExampleWriter_WriteAll() {
 	// Ken,Thompson,ken
 	// Robert,Griesemer,gri
 }
+
+func ExampleDecoder() {
+	in := `username,first_name,last_name,commits
+rob,Rob,Pike,1200
+ken,Ken,Thompson,300
+`
+	type User struct {
+		First   string `csv:"first_name"`
+		Last    string `csv:"last_name"`
+		Commits int    `csv:"commits"`
+	}
+
+	d := csv.NewDecoder(csv.NewReader(strings.NewReader(in)))
+	for {
+		var u User
+		err := d.Decode(&u)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%+v\n", u)
+	}
+	// Output:
+	// {First:Rob Last:Pike Commits:1200}
+	// {First:Ken Last:Thompson Commits:300}
+}
+
+func ExampleEncoder() {
+	type User struct {
+		Username string `csv:"username"`
+		First    string `csv:"first_name"`
+		Last     string `csv:"last_name"`
+	}
+	users := []User{
+		{"rob", "Rob", "Pike"},
+		{"ken", "Ken", "Thompson"},
+	}
+
+	e := csv.NewEncoder(csv.NewWriter(os.Stdout))
+	if err := e.Encode(users); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// username,first_name,last_name
+	// rob,Rob,Pike
+	// ken,Ken,Thompson
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A field is a struct field mapped to a CSV column.
+type field struct {
+	name      string // column name
+	goName    string // name of the Go field, qualified by any embedded structs
+	index     []int  // index sequence for reflect.Value.FieldByIndex
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// A structFields describes the columns of a struct type.
+type structFields struct {
+	list []field
+	err  error // error for an unsupported field type
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedFields returns the columns of the struct type t.
+func cachedFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(*structFields)
+}
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// typeFields returns the columns of the struct type t, in field order.
+//
+// Exported fields are mapped to columns named by their csv tag, or by
+// the field name if there is no tag. Fields with the tag "-" are ignored.
+// The fields of embedded structs are promoted, following the same rules
+// as encoding/json: a field at a shallower depth hides fields of the same
+// name at greater depths, and fields of the same name at the same depth
+// hide each other.
+func typeFields(t reflect.Type) *structFields {
+	type candidate struct {
+		field
+		depth  int
+		tagged bool
+	}
+	var all []candidate
+	var walk func(t reflect.Type, prefix string, index []int, depth int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, prefix string, index []int, depth int, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("csv")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(index[:len(index):len(index)], i)
+			ft := sf.Type
+			if sf.Anonymous && name == "" {
+				et := ft
+				if et.Kind() == reflect.Pointer {
+					if !sf.IsExported() {
+						// Decoding cannot allocate an embedded pointer
+						// to an unexported struct type.
+						continue
+					}
+					et = et.Elem()
+				}
+				if et.Kind() == reflect.Struct && !isTextType(et) {
+					walk(et, prefix+sf.Name+".", idx, depth+1, visited)
+					continue
+				}
+			}
+			if !sf.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			all = append(all, candidate{
+				field: field{
+					name:      name,
+					goName:    prefix + sf.Name,
+					index:     idx,
+					typ:       ft,
+					omitEmpty: opts == "omitempty",
+				},
+				depth:  depth,
+				tagged: tag != "",
+			})
+		}
+	}
+	walk(t, "", nil, 0, map[reflect.Type]bool{})
+
+	// Resolve names that appear more than once.
+	byName := make(map[string][]int)
+	for i, c := range all {
+		byName[c.name] = append(byName[c.name], i)
+	}
+	winner := func(dups []int) int {
+		minDepth := all[dups[0]].depth
+		for _, j := range dups {
+			minDepth = min(minDepth, all[j].depth)
+		}
+		found, foundTagged := -1, -1
+		n, nTagged := 0, 0
+		for _, j := range dups {
+			if all[j].depth != minDepth {
+				continue
+			}
+			n++
+			found = j
+			if all[j].tagged {
+				nTagged++
+				foundTagged = j
+			}
+		}
+		switch {
+		case nTagged == 1:
+			return foundTagged
+		case n == 1:
+			return found
+		}
+		return -1
+	}
+
+	sf := new(structFields)
+	for i, c := range all {
+		if dups := byName[c.name]; len(dups) > 1 && winner(dups) != i {
+			continue
+		}
+		if sf.err == nil && !supportedType(c.typ) {
+			sf.err = fmt.Errorf("csv: unsupported type %s for field %s of %s", c.typ, c.name, t)
+		}
+		sf.list = append(sf.list, c.field)
+	}
+	return sf
+}
+
+// isTextType reports whether t or *t implements
+// encoding.TextMarshaler or encoding.TextUnmarshaler.
+func isTextType(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(textMarshalerType) || pt.Implements(textMarshalerType) ||
+		pt.Implements(textUnmarshalerType)
+}
+
+// supportedType reports whether values of type t can be
+// encoded and decoded as fields.
+func supportedType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if isTextType(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
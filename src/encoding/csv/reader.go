@@ -58,6 +58,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"unicode"
 	"unicode/utf8"
 )
@@ -248,6 +249,31 @@ func (r *Reader) ReadAll() (records [][]string, err error) {
 	}
 }
 
+// Records returns an iterator over the remaining records in r.
+// Each iteration yields the result of a call to [Reader.Read],
+// so if [Reader.ReuseRecord] is true, the yielded record may share
+// its backing array with the records of other iterations.
+//
+// The iteration ends at the end of the input, or after yielding an
+// error. As with Read, a record with an unexpected number of fields is
+// yielded along with [ErrFieldCount]; that error does not end the iteration.
+func (r *Reader) Records() iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+			if !yield(record, err) {
+				return
+			}
+			if err != nil && !errors.Is(err, ErrFieldCount) {
+				return
+			}
+		}
+	}
+}
+
 // readLine reads the next line (with the trailing endline).
 // If EOF is hit without a trailing endline, it will be omitted.
 // If some bytes were read, then the error is never [io.EOF].
@@ -656,3 +656,46 @@ xxxxxxxxxxxxxxxxxxxxxxxx,yyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyy,zzzzzzzzzzzzzzzzzzzzzz
 xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx,yyyyyyyyyyyyyyyyyyyyyyyyyyyyyyyy,zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz,wwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwwww,vvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvvv
 `, 3))
 }
+
+func TestRecords(t *testing.T) {
+	const input = "a,b\nc,d\ne\nf,g\n"
+	for _, reuse := range []bool{false, true} {
+		r := NewReader(strings.NewReader(input))
+		r.ReuseRecord = reuse
+		var got [][]string
+		var errs []error
+		for record, err := range r.Records() {
+			got = append(got, slices.Clone(record))
+			errs = append(errs, err)
+		}
+		want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}, {"f", "g"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReuseRecord=%v: got %q, want %q", reuse, got, want)
+		}
+		if len(errs) != 4 || errs[0] != nil || errs[1] != nil || !errors.Is(errs[2], ErrFieldCount) || errs[3] != nil {
+			t.Errorf("ReuseRecord=%v: errors %v, want ErrFieldCount for the third record only", reuse, errs)
+		}
+	}
+
+	// A parse error ends the iteration.
+	r := NewReader(strings.NewReader("a,b\nc,\"d\ne,f\n"))
+	n := 0
+	var lastErr error
+	for _, err := range r.Records() {
+		n++
+		lastErr = err
+	}
+	var pe *ParseError
+	if n != 2 || !errors.As(lastErr, &pe) {
+		t.Errorf("got %d records ending with %v, want 2 ending with a ParseError", n, lastErr)
+	}
+
+	// Breaking out of the loop stops reading.
+	r = NewReader(strings.NewReader(input))
+	for range r.Records() {
+		break
+	}
+	if record, err := r.Read(); err != nil || !slices.Equal(record, []string{"c", "d"}) {
+		t.Errorf("Read after break = %q, %v; want [c d]", record, err)
+	}
+}
@@ -14,10 +14,10 @@ package json
 
 import (
 	"bytes"
+	"compress/zstd"
 	"crypto/sha256"
 	"fmt"
 	"internal/testenv"
-	"internal/zstd"
 	"io"
 	"os"
 	"reflect"
@@ -305,8 +305,14 @@ var depsRules = `
 
 	# compression
 	FMT, encoding/binary, hash/adler32, hash/crc32, sort
-	< compress/bzip2, compress/flate, compress/lzw, internal/zstd
-	< archive/zip, compress/gzip, compress/zlib;
+	< compress/bzip2, compress/flate, compress/lzw, compress/zstd
+	< compress/gzip, compress/zlib;
+
+	FMT
+	< archive/internal/extract;
+
+	archive/internal/extract, compress/flate, compress/zstd
+	< archive/zip;
 
 	# templates
 	FMT
@@ -336,7 +342,7 @@ var depsRules = `
 	< index/suffixarray;
 
 	# executable parsing
-	FMT, encoding/binary, compress/zlib, internal/saferio, internal/zstd, sort
+	FMT, encoding/binary, compress/zlib, compress/zstd, internal/saferio, sort
 	< runtime/debug
 	< debug/dwarf
 	< debug/elf, debug/gosym, debug/macho, debug/pe, debug/plan9obj, internal/xcoff
@@ -412,7 +418,9 @@ var depsRules = `
 	< plugin;
 
 	CGO, FMT
-	< os/user
+	< os/user;
+
+	archive/internal/extract, os/user
 	< archive/tar;
 
 	sync
@@ -666,7 +674,12 @@ var depsRules = `
 	NET, crypto/tls
 	< net/http/httptrace;
 
+	FMT, math/rand/v2
+	< net/http/tracecontext;
+
 	compress/gzip,
+	compress/zlib,
+	compress/zstd,
 	golang.org/x/net/http/httpguts,
 	golang.org/x/net/http/httpproxy,
 	golang.org/x/net/http2/hpack,
@@ -674,6 +687,7 @@ var depsRules = `
 	net/http/internal/ascii,
 	net/http/internal/testcert,
 	net/http/httptrace,
+	net/http/tracecontext,
 	mime/multipart,
 	log
 	< net/http/internal/httpcommon, net/http/internal/httpsfv
@@ -692,7 +706,22 @@ var depsRules = `
 	< expvar;
 
 	net/http, net/http/internal/ascii
-	< net/http/cookiejar, net/http/httputil;
+	< net/http/httputil;
+
+	encoding/json, net/http, net/http/internal/ascii
+	< net/http/cookiejar;
+
+	container/list, net/http, net/http/internal/ascii
+	< net/http/httpcache;
+
+	net/http
+	< net/http/sse;
+
+	net/http, net/http/internal/ascii
+	< net/http/websocket;
+
+	log/slog, net/http
+	< net/http/httplog;
 
 	net/http, regexp
 	< net/http/cgi
@@ -0,0 +1,236 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs
+
+import (
+	"errors"
+	"io"
+)
+
+// A WritableFile is a [File] that can be written to.
+// Files returned by [CreateFS.Create] implement WritableFile.
+type WritableFile interface {
+	File
+	io.Writer
+}
+
+// CreateFS is the interface implemented by a file system
+// that can create files.
+type CreateFS interface {
+	FS
+
+	// Create creates or truncates the named file.
+	// If the file already exists, it is truncated.
+	// If the file does not exist, it is created with mode 0o666
+	// (before umask, if the file system has one).
+	// The parent directory of the file must already exist.
+	// If there is an error, it should be of type [*PathError].
+	Create(name string) (WritableFile, error)
+}
+
+// OpenFileFS is the interface implemented by a file system
+// that supports opening files with flags and permissions,
+// such as for appending to an existing file.
+type OpenFileFS interface {
+	FS
+
+	// OpenFile opens the named file with the specified flag
+	// and, if the file is created, permission bits perm (before umask).
+	// The flag is a combination of the O_ flags defined by package os,
+	// such as O_RDONLY or O_WRONLY|O_CREATE|O_APPEND.
+	// If the file is opened for writing,
+	// the returned File should implement [WritableFile].
+	// If there is an error, it should be of type [*PathError].
+	OpenFile(name string, flag int, perm FileMode) (File, error)
+}
+
+// WriteFileFS is the interface implemented by a file system
+// that provides an optimized implementation of [WriteFile].
+type WriteFileFS interface {
+	FS
+
+	// WriteFile writes data to the named file, creating it if necessary.
+	// If the file does not exist, WriteFile creates it with permissions perm
+	// (before umask); otherwise WriteFile truncates it before writing,
+	// without changing permissions.
+	WriteFile(name string, data []byte, perm FileMode) error
+}
+
+// MkdirFS is the interface implemented by a file system
+// that can create directories.
+type MkdirFS interface {
+	FS
+
+	// Mkdir creates a new directory with the specified name and
+	// permission bits (before umask).
+	// The parent directory must already exist.
+	// If there is an error, it should be of type [*PathError].
+	Mkdir(name string, perm FileMode) error
+}
+
+// RemoveFS is the interface implemented by a file system
+// that can remove files and empty directories.
+type RemoveFS interface {
+	FS
+
+	// Remove removes the named file or (empty) directory.
+	// If there is an error, it should be of type [*PathError].
+	Remove(name string) error
+}
+
+// RenameFS is the interface implemented by a file system
+// that can rename files and directories.
+type RenameFS interface {
+	FS
+
+	// Rename renames (moves) oldname to newname.
+	// If newname already exists and is not a directory, Rename replaces it.
+	Rename(oldname, newname string) error
+}
+
+// ChmodFS is the interface implemented by a file system
+// that can change the mode of files.
+type ChmodFS interface {
+	FS
+
+	// Chmod changes the mode of the named file to mode.
+	// If the file is a symbolic link, it changes the mode of the link's target.
+	// If there is an error, it should be of type [*PathError].
+	Chmod(name string, mode FileMode) error
+}
+
+// Create creates or truncates the named file in fsys,
+// returning a file open for writing.
+//
+// If fsys does not implement [CreateFS], then Create returns an error
+// wrapping [errors.ErrUnsupported].
+func Create(fsys FS, name string) (WritableFile, error) {
+	cfs, ok := fsys.(CreateFS)
+	if !ok {
+		return nil, &PathError{Op: "create", Path: name, Err: errors.ErrUnsupported}
+	}
+	return cfs.Create(name)
+}
+
+// OpenFile opens the named file in fsys with the specified flag
+// and permission bits, as described by [OpenFileFS].
+//
+// If fsys does not implement [OpenFileFS], then OpenFile returns an error
+// wrapping [errors.ErrUnsupported].
+func OpenFile(fsys FS, name string, flag int, perm FileMode) (File, error) {
+	ofs, ok := fsys.(OpenFileFS)
+	if !ok {
+		return nil, &PathError{Op: "open", Path: name, Err: errors.ErrUnsupported}
+	}
+	return ofs.OpenFile(name, flag, perm)
+}
+
+// WriteFile writes data to the named file in fsys, creating it if necessary.
+//
+// If fsys implements [WriteFileFS], WriteFile calls fsys.WriteFile.
+// Otherwise, if fsys implements [CreateFS], WriteFile calls fsys.Create
+// and uses Write and Close on the returned file; in that case a file
+// that does not exist is created with the file system's default
+// permissions rather than perm.
+// Otherwise WriteFile returns an error wrapping [errors.ErrUnsupported].
+func WriteFile(fsys FS, name string, data []byte, perm FileMode) error {
+	if fsys, ok := fsys.(WriteFileFS); ok {
+		return fsys.WriteFile(name, data, perm)
+	}
+	f, err := Create(fsys, name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// Mkdir creates a new directory in fsys with the specified name
+// and permission bits.
+//
+// If fsys does not implement [MkdirFS], then Mkdir returns an error
+// wrapping [errors.ErrUnsupported].
+func Mkdir(fsys FS, name string, perm FileMode) error {
+	mfs, ok := fsys.(MkdirFS)
+	if !ok {
+		return &PathError{Op: "mkdir", Path: name, Err: errors.ErrUnsupported}
+	}
+	return mfs.Mkdir(name, perm)
+}
+
+// MkdirAll creates a directory named name in fsys,
+// along with any necessary parents.
+// The permission bits perm are used for all directories
+// that MkdirAll creates. If name is already a directory,
+// MkdirAll does nothing and returns nil.
+//
+// If fsys does not implement [MkdirFS], then MkdirAll returns an error
+// wrapping [errors.ErrUnsupported].
+func MkdirAll(fsys FS, name string, perm FileMode) error {
+	if !ValidPath(name) {
+		return &PathError{Op: "mkdir", Path: name, Err: ErrInvalid}
+	}
+	if info, err := Stat(fsys, name); err == nil {
+		if info.IsDir() {
+			return nil
+		}
+		return &PathError{Op: "mkdir", Path: name, Err: ErrExist}
+	}
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] == '/' {
+			if err := MkdirAll(fsys, name[:i], perm); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	err := Mkdir(fsys, name, perm)
+	if err != nil {
+		// Handle a race with another creator of the directory.
+		if info, err1 := Lstat(fsys, name); err1 == nil && info.IsDir() {
+			return nil
+		}
+	}
+	return err
+}
+
+// Remove removes the named file or empty directory from fsys.
+//
+// If fsys does not implement [RemoveFS], then Remove returns an error
+// wrapping [errors.ErrUnsupported].
+func Remove(fsys FS, name string) error {
+	rfs, ok := fsys.(RemoveFS)
+	if !ok {
+		return &PathError{Op: "remove", Path: name, Err: errors.ErrUnsupported}
+	}
+	return rfs.Remove(name)
+}
+
+// Rename renames (moves) oldname to newname in fsys.
+//
+// If fsys does not implement [RenameFS], then Rename returns an error
+// wrapping [errors.ErrUnsupported].
+func Rename(fsys FS, oldname, newname string) error {
+	rfs, ok := fsys.(RenameFS)
+	if !ok {
+		return &PathError{Op: "rename", Path: oldname, Err: errors.ErrUnsupported}
+	}
+	return rfs.Rename(oldname, newname)
+}
+
+// Chmod changes the mode of the named file in fsys to mode.
+//
+// If fsys does not implement [ChmodFS], then Chmod returns an error
+// wrapping [errors.ErrUnsupported].
+func Chmod(fsys FS, name string, mode FileMode) error {
+	cfs, ok := fsys.(ChmodFS)
+	if !ok {
+		return &PathError{Op: "chmod", Path: name, Err: errors.ErrUnsupported}
+	}
+	return cfs.Chmod(name, mode)
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fs_test
+
+import (
+	"errors"
+	. "io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// createOnly hides all but the Create method of a file system.
+type createOnly struct {
+	FS
+	create func(string) (WritableFile, error)
+}
+
+func (c createOnly) Create(name string) (WritableFile, error) { return c.create(name) }
+
+func TestWriteFile(t *testing.T) {
+	fsys := fstest.MapFS{}
+	check := func(fsys FS, name, want string) {
+		t.Helper()
+		data, err := ReadFile(fsys, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("ReadFile(%q) = %q, want %q", name, data, want)
+		}
+	}
+
+	// Test WriteFileFS.
+	if err := WriteFile(fsys, "a.txt", []byte("direct"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	check(fsys, "a.txt", "direct")
+	if mode := fsys["a.txt"].Mode; mode != 0o600 {
+		t.Errorf("mode = %v, want %v", mode, FileMode(0o600))
+	}
+
+	// Test the fallback to CreateFS.
+	cfs := createOnly{fsys, fsys.Create}
+	if err := WriteFile(cfs, "b.txt", []byte("created"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	check(fsys, "b.txt", "created")
+
+	// Test an unsupported file system.
+	err := WriteFile(struct{ FS }{fsys}, "c.txt", []byte("x"), 0o666)
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("WriteFile on read-only FS: %v, want ErrUnsupported", err)
+	}
+}
+
+func TestWriteUnsupported(t *testing.T) {
+	fsys := struct{ FS }{fstest.MapFS{}}
+	for _, tt := range []struct {
+		op  string
+		err error
+	}{
+		{"create", func() error { _, err := Create(fsys, "f"); return err }()},
+		{"open", func() error { _, err := OpenFile(fsys, "f", os.O_RDWR, 0); return err }()},
+		{"mkdir", Mkdir(fsys, "d", 0o777)},
+		{"mkdir", MkdirAll(fsys, "d/e", 0o777)},
+		{"remove", Remove(fsys, "f")},
+		{"rename", Rename(fsys, "f", "g")},
+		{"chmod", Chmod(fsys, "f", 0o666)},
+	} {
+		var pe *PathError
+		if !errors.As(tt.err, &pe) || pe.Op != tt.op || !errors.Is(tt.err, errors.ErrUnsupported) {
+			t.Errorf("%s: got %v, want PathError with ErrUnsupported", tt.op, tt.err)
+		}
+	}
+}
+
+func TestMkdirAll(t *testing.T) {
+	for _, fsys := range []FS{
+		fstest.MapFS{"file": {Data: []byte("x")}},
+		os.DirFS(t.TempDir()),
+	} {
+		if err := WriteFile(fsys, "file", []byte("x"), 0o666); err != nil {
+			t.Fatal(err)
+		}
+		if err := MkdirAll(fsys, "a/b/c", 0o777); err != nil {
+			t.Fatal(err)
+		}
+		if err := MkdirAll(fsys, "a/b", 0o777); err != nil {
+			t.Fatalf("MkdirAll of existing directory: %v", err)
+		}
+		for _, name := range []string{"a", "a/b", "a/b/c"} {
+			info, err := Stat(fsys, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !info.IsDir() {
+				t.Errorf("%s is not a directory", name)
+			}
+		}
+		if err := MkdirAll(fsys, "file/d", 0o777); err == nil {
+			t.Errorf("MkdirAll below a file succeeded")
+		}
+		if err := MkdirAll(fsys, "file", 0o777); !errors.Is(err, ErrExist) {
+			t.Errorf("MkdirAll of a file: %v, want ErrExist", err)
+		}
+	}
+}
+
+func TestWriteFileDirFS(t *testing.T) {
+	dir := t.TempDir()
+	fsys := os.DirFS(dir)
+	if err := WriteFile(fsys, "f.txt", []byte("on disk"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "f.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "on disk" {
+		t.Errorf("got %q, want %q", data, "on disk")
+	}
+}
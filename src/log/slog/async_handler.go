@@ -0,0 +1,216 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrHandlerClosed is returned by the Handle method of an [AsyncHandler]
+// that has been closed.
+var ErrHandlerClosed = errors.New("slog: handler closed")
+
+// AsyncOptions are options for an [AsyncHandler].
+type AsyncOptions struct {
+	// QueueSize is the maximum number of records waiting to be handled.
+	// If zero, it defaults to 1024.
+	QueueSize int
+
+	// Block controls what happens when the queue is full.
+	// If Block is false, the record is dropped and counted.
+	// If Block is true, Handle waits until there is room in the queue.
+	Block bool
+}
+
+// NewAsyncHandler creates an [AsyncHandler] that passes records to h
+// from a background goroutine. The caller must call [AsyncHandler.Close]
+// to stop the goroutine and wait for the queued records to be handled.
+// If opts is nil, the default options are used.
+func NewAsyncHandler(h Handler, opts *AsyncOptions) *AsyncHandler {
+	if opts == nil {
+		opts = &AsyncOptions{}
+	}
+	size := opts.QueueSize
+	if size <= 0 {
+		size = 1024
+	}
+	q := &asyncQueue{
+		block: opts.Block,
+		c:     make(chan asyncEntry, size),
+		done:  make(chan struct{}),
+	}
+	go q.run()
+	return &AsyncHandler{handler: h, queue: q}
+}
+
+// AsyncHandler is a [Handler] that queues records and passes them
+// to another Handler in a background goroutine, so that logging does
+// not wait for a slow destination.
+//
+// Records are handled in the order they are queued, and the Handle
+// method returns before the record is handled. Handle clones each
+// record, so the record may be modified after Handle returns. The
+// context passed to Handle is passed on without its cancellation.
+//
+// If the queue is full, the record is either dropped or Handle waits
+// for room, according to [AsyncOptions.Block].
+//
+// Handlers returned by the WithAttrs and WithGroup methods share the
+// queue, dropped count and error state of the original handler.
+// Closing any of them closes them all.
+type AsyncHandler struct {
+	handler Handler
+	queue   *asyncQueue
+}
+
+// An asyncEntry is a record, or a flush request, in the queue.
+type asyncEntry struct {
+	ctx     context.Context
+	handler Handler
+	record  Record
+	flushed chan struct{} // if non-nil, close when reached
+}
+
+// An asyncQueue holds the state shared by an AsyncHandler
+// and the handlers derived from it.
+type asyncQueue struct {
+	block   bool
+	c       chan asyncEntry
+	done    chan struct{} // closed when run returns
+	dropped atomic.Uint64
+
+	// mu guards closed and the closing of c.
+	// Senders on c hold a read lock.
+	mu     sync.RWMutex
+	closed bool
+
+	errMu sync.Mutex
+	err   error // first error from a handler since the last flush
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for e := range q.c {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		if err := e.handler.Handle(e.ctx, e.record); err != nil {
+			q.errMu.Lock()
+			if q.err == nil {
+				q.err = err
+			}
+			q.errMu.Unlock()
+		}
+	}
+}
+
+// takeErr returns and clears the first handler error.
+func (q *asyncQueue) takeErr() error {
+	q.errMu.Lock()
+	defer q.errMu.Unlock()
+	err := q.err
+	q.err = nil
+	return err
+}
+
+// Enabled reports whether the wrapped handler is enabled for level.
+func (h *AsyncHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle queues a clone of r to be passed to the wrapped handler.
+// It returns [ErrHandlerClosed] if h has been closed.
+// Errors from the wrapped handler are returned by
+// [AsyncHandler.Flush] and [AsyncHandler.Close].
+func (h *AsyncHandler) Handle(ctx context.Context, r Record) error {
+	q := h.queue
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		q.dropped.Add(1)
+		return ErrHandlerClosed
+	}
+	e := asyncEntry{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.handler,
+		record:  r.Clone(),
+	}
+	if q.block {
+		q.c <- e
+		return nil
+	}
+	select {
+	case q.c <- e:
+	default:
+		q.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs returns a new [AsyncHandler] that wraps the result of
+// calling WithAttrs on h's handler, and shares h's queue.
+func (h *AsyncHandler) WithAttrs(attrs []Attr) Handler {
+	return &AsyncHandler{handler: h.handler.WithAttrs(attrs), queue: h.queue}
+}
+
+// WithGroup returns a new [AsyncHandler] that wraps the result of
+// calling WithGroup on h's handler, and shares h's queue.
+func (h *AsyncHandler) WithGroup(name string) Handler {
+	return &AsyncHandler{handler: h.handler.WithGroup(name), queue: h.queue}
+}
+
+// Flush waits until all records queued before the call have been handled.
+// It returns the first error returned by the wrapped handler since the
+// previous call to Flush or Close, if any.
+// If the context is done before the records are handled, Flush returns
+// the context's error.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	q := h.queue
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return q.takeErr()
+	}
+	flushed := make(chan struct{})
+	select {
+	case q.c <- asyncEntry{flushed: flushed}:
+		q.mu.RUnlock()
+	case <-ctx.Done():
+		q.mu.RUnlock()
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return q.takeErr()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting records, waits until all queued records have been
+// handled, and stops the background goroutine. It returns the first
+// error returned by the wrapped handler since the previous call to Flush
+// or Close, if any. Close may be called more than once.
+func (h *AsyncHandler) Close() error {
+	q := h.queue
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.c)
+	}
+	q.mu.Unlock()
+	<-q.done
+	return q.takeErr()
+}
+
+// Dropped returns the number of records that h and the handlers
+// derived from it have dropped.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.queue.dropped.Load()
+}
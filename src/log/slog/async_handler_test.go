@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedHandler is a handler that waits for a value on gate
+// before handling each record.
+type gatedHandler struct {
+	Handler
+	gate chan error
+}
+
+func (h *gatedHandler) Handle(ctx context.Context, r Record) error {
+	if err := <-h.gate; err != nil {
+		return err
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *gatedHandler) WithAttrs(attrs []Attr) Handler {
+	return &gatedHandler{h.Handler.WithAttrs(attrs), h.gate}
+}
+
+func TestAsyncHandler(t *testing.T) {
+	var buf bytes.Buffer
+	removeTime := func(groups []string, a Attr) Attr {
+		if a.Key == TimeKey && len(groups) == 0 {
+			return Attr{}
+		}
+		return a
+	}
+	h := NewAsyncHandler(NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeTime}), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	l := New(h)
+	l.InfoContext(ctx, "a", "x", 1)
+	cancel()
+	l.With("y", 2).InfoContext(ctx, "b")
+	if err := h.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := "level=INFO msg=a x=1\nlevel=INFO msg=b y=2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	l.Info("c")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.HasSuffix(got, "msg=c\n") {
+		t.Errorf("Close did not deliver the last record: %q", got)
+	}
+	if err := h.Handle(context.Background(), NewRecord(time.Now(), LevelInfo, "d", 0)); err != ErrHandlerClosed {
+		t.Errorf("Handle after Close = %v, want ErrHandlerClosed", err)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if got := h.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+}
+
+func TestAsyncHandlerOverflow(t *testing.T) {
+	var buf bytes.Buffer
+	gate := make(chan error)
+	h := NewAsyncHandler(&gatedHandler{NewTextHandler(&buf, nil), gate}, &AsyncOptions{QueueSize: 2})
+	defer h.Close()
+	r := NewRecord(time.Now(), LevelInfo, "m", 0)
+
+	// The first record is taken by the background goroutine, which waits
+	// on the gate, and the next two fill the queue.
+	h.Handle(context.Background(), r)
+	for len(h.queue.c) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for range 5 {
+		h.Handle(context.Background(), r)
+	}
+	if got := h.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+
+	// Records are not handled until the gate opens.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := h.Flush(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Flush with deadline = %v, want DeadlineExceeded", err)
+	}
+
+	errFail := errors.New("fail")
+	gate <- errFail
+	gate <- nil
+	gate <- nil
+	if err := h.Flush(context.Background()); err != errFail {
+		t.Errorf("Flush = %v, want %v", err, errFail)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("handled %d records, want 2", n)
+	}
+}
+
+func TestAsyncHandlerBlock(t *testing.T) {
+	var buf bytes.Buffer
+	gate := make(chan error)
+	h := NewAsyncHandler(&gatedHandler{NewTextHandler(&buf, nil), gate}, &AsyncOptions{QueueSize: 1, Block: true})
+	r := NewRecord(time.Now(), LevelInfo, "m", 0)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			h.Handle(context.Background(), r)
+		})
+	}
+	for range 10 {
+		gate <- nil
+	}
+	wg.Wait()
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0", got)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 10 {
+		t.Errorf("handled %d records, want 10", n)
+	}
+}
@@ -137,6 +137,13 @@ type HandlerOptions struct {
 	// of the log statement and add a SourceKey attribute to the output.
 	AddSource bool
 
+	// TraceIDs, if non-nil, is called with the context passed to Handle
+	// to obtain the IDs of the trace and span of the log call. If it
+	// returns a non-empty trace ID, the handler adds TraceIDKey and
+	// SpanIDKey attributes to the output. For W3C trace context, use
+	// [net/http/tracecontext.IDs].
+	TraceIDs func(ctx context.Context) (traceID, spanID string)
+
 	// Level reports the minimum record level that will be logged.
 	// The handler discards records with lower levels.
 	// If Level is nil, the handler assumes LevelInfo.
@@ -148,9 +155,11 @@ type HandlerOptions struct {
 	// The attribute's value has been resolved (see [Value.Resolve]).
 	// If ReplaceAttr returns a zero Attr, the attribute is discarded.
 	//
-	// The built-in attributes with keys "time", "level", "source", and "msg"
-	// are passed to this function, except that time is omitted
-	// if zero, and source is omitted if AddSource is false.
+	// The built-in attributes with keys "time", "level", "source", "msg",
+	// "trace_id", and "span_id" are passed to this function, except that
+	// time is omitted if zero, source is omitted if AddSource is false,
+	// and trace_id and span_id are omitted if TraceIDs is nil or
+	// returns an empty trace ID.
 	//
 	// The first argument is a list of currently open groups that contain the
 	// Attr. It must not be retained or modified. ReplaceAttr is never called
@@ -186,6 +195,12 @@ const (
 	// SourceKey is the key used by the built-in handlers for the source file
 	// and line of the log call. The associated value is a *[Source].
 	SourceKey = "source"
+	// TraceIDKey is the key used by the built-in handlers for the trace ID
+	// of the trace context of the log call. The associated value is a string.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the key used by the built-in handlers for the span ID
+	// of the trace context of the log call. The associated value is a string.
+	SpanIDKey = "span_id"
 )
 
 type commonHandler struct {
@@ -266,7 +281,7 @@ func (h *commonHandler) withGroup(name string) *commonHandler {
 
 // handle is the internal implementation of Handler.Handle
 // used by TextHandler and JSONHandler.
-func (h *commonHandler) handle(r Record) error {
+func (h *commonHandler) handle(ctx context.Context, r Record) error {
 	state := h.newHandleState(buffer.New(), true, "")
 	defer state.free()
 	if h.json {
@@ -312,6 +327,13 @@ func (h *commonHandler) handle(r Record) error {
 	} else {
 		state.appendAttr(String(key, msg))
 	}
+	// trace
+	if h.opts.TraceIDs != nil && ctx != nil {
+		if traceID, spanID := h.opts.TraceIDs(ctx); traceID != "" {
+			state.appendAttr(String(TraceIDKey, traceID))
+			state.appendAttr(String(SpanIDKey, spanID))
+		}
+	}
 	state.groups = stateGroups // Restore groups passed to ReplaceAttrs.
 	state.appendNonBuiltIns(r)
 	state.buf.WriteByte('\n')
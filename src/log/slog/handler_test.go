@@ -670,6 +670,56 @@ func TestJSONAndTextHandlersWithUnavailableSource(t *testing.T) {
 	}
 }
 
+func TestJSONAndTextHandlersTraceIDs(t *testing.T) {
+	var buf bytes.Buffer
+	type traceKey struct{}
+	ctx := context.WithValue(context.Background(), traceKey{}, "4bf92f3577b34da6a3ce929d0e0e4736")
+	traceIDs := func(ctx context.Context) (traceID, spanID string) {
+		traceID, _ = ctx.Value(traceKey{}).(string)
+		return traceID, "00f067aa0ba902b7"
+	}
+	opts := &HandlerOptions{TraceIDs: traceIDs}
+	replace := &HandlerOptions{
+		TraceIDs: traceIDs,
+		ReplaceAttr: func(_ []string, a Attr) Attr {
+			if a.Key == SpanIDKey {
+				return Attr{}
+			}
+			return a
+		},
+	}
+
+	for _, test := range []struct {
+		name string
+		h    Handler
+		ctx  context.Context
+		want string
+	}{
+		{"text", NewTextHandler(&buf, opts).WithGroup("g"), ctx,
+			"level=INFO msg=message trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 g.a=1"},
+		{"json", NewJSONHandler(&buf, opts), ctx,
+			`{"level":"INFO","msg":"message","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","a":1}`},
+		{"replace", NewTextHandler(&buf, replace), ctx,
+			"level=INFO msg=message trace_id=4bf92f3577b34da6a3ce929d0e0e4736 a=1"},
+		{"no trace context", NewTextHandler(&buf, opts), context.Background(),
+			"level=INFO msg=message a=1"},
+		{"disabled", NewTextHandler(&buf, nil), ctx,
+			"level=INFO msg=message a=1"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf.Reset()
+			r := NewRecord(time.Time{}, LevelInfo, "message", 0)
+			r.AddAttrs(Int("a", 1))
+			if err := test.h.Handle(test.ctx, r); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(buf.String()); got != test.want {
+				t.Errorf("\ngot  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
+
 func TestSecondWith(t *testing.T) {
 	// Verify that a second call to Logger.With does not corrupt
 	// the original.
@@ -0,0 +1,180 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingOptions are options for a [SamplingHandler].
+type SamplingOptions struct {
+	// Interval is the length of each sampling period.
+	// If zero, it defaults to one second.
+	Interval time.Duration
+
+	// First is the number of records with a given message and level
+	// that are handled in each interval before sampling begins.
+	First int
+
+	// Thereafter controls sampling after the first records of an interval:
+	// every Thereafter'th record with the same message and level is handled,
+	// and the others are dropped.
+	// If zero, all records after the first are dropped until the next interval.
+	Thereafter int
+
+	// Level is the minimum level that is never sampled.
+	// Records at or above Level are always handled; records below it are
+	// sampled. If Level is nil, records at all levels are sampled.
+	Level Leveler
+
+	// PressureLevel is the level below which records are dropped while
+	// the handler is under pressure, so that low-priority records such
+	// as debug records are shed first. Once the handler has dropped a
+	// record in an interval, all records below PressureLevel are dropped,
+	// rather than sampled, until the next interval.
+	// If PressureLevel is nil, records are only dropped by sampling.
+	// PressureLevel has no effect on records at or above Level.
+	PressureLevel Leveler
+}
+
+// NewSamplingHandler creates a [SamplingHandler] that passes a sample of
+// the records it receives to h.
+// If opts is nil, the handler handles the first 100 records with each
+// message and level per second, and every 100th record thereafter.
+func NewSamplingHandler(h Handler, opts *SamplingOptions) *SamplingHandler {
+	if opts == nil {
+		opts = &SamplingOptions{First: 100, Thereafter: 100}
+	}
+	s := &sampler{opts: *opts, counts: make(map[samplingKey]uint64)}
+	if s.opts.Interval <= 0 {
+		s.opts.Interval = time.Second
+	}
+	return &SamplingHandler{handler: h, sampler: s}
+}
+
+// SamplingHandler is a [Handler] that limits the rate of records passed
+// to another Handler.
+//
+// Records are grouped by message and level. In each interval, the
+// handler passes on the first records of each group, then every so
+// many records after that, as described by [SamplingOptions]. Records
+// that are not sampled are dropped and counted. Once a record has been
+// dropped in an interval, records below [SamplingOptions.PressureLevel]
+// are dropped for the rest of the interval. At most 1000 groups
+// are counted separately in each interval; the records of any further
+// groups are sampled together, as if they were one group.
+//
+// The record's time is used to determine its interval, or the current
+// time if the record's time is zero. A record whose time is before
+// the current interval, as happens when records are logged
+// concurrently, is counted in the current interval.
+//
+// Handlers returned by the WithAttrs and WithGroup methods share
+// their sampling state and dropped count with the original handler.
+type SamplingHandler struct {
+	handler Handler
+	sampler *sampler
+}
+
+type samplingKey struct {
+	msg   string
+	level Level
+}
+
+// maxSamplingKeys is the largest number of groups a sampler counts
+// separately in an interval.
+const maxSamplingKeys = 1000
+
+// A sampler holds the state of a SamplingHandler that is shared
+// among the handlers derived from it.
+type sampler struct {
+	opts    SamplingOptions
+	dropped atomic.Uint64
+
+	mu       sync.Mutex
+	start    time.Time // start of the current interval
+	counts   map[samplingKey]uint64
+	overflow uint64 // count of records in groups beyond maxSamplingKeys
+	pressure bool   // a record has been dropped in the current interval
+}
+
+// sample reports whether r should be handled.
+func (s *sampler) sample(r *Record) bool {
+	if s.opts.Level != nil && r.Level >= s.opts.Level.Level() {
+		return true
+	}
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	key := samplingKey{r.Message, r.Level}
+	shed := s.opts.PressureLevel != nil && r.Level < s.opts.PressureLevel.Level()
+
+	s.mu.Lock()
+	if t.Sub(s.start) >= s.opts.Interval {
+		s.start = t
+		clear(s.counts)
+		s.overflow = 0
+		s.pressure = false
+	}
+	handle := false
+	if !s.pressure || !shed {
+		var n uint64
+		if _, ok := s.counts[key]; ok || len(s.counts) < maxSamplingKeys {
+			s.counts[key]++
+			n = s.counts[key]
+		} else {
+			s.overflow++
+			n = s.overflow
+		}
+		first := uint64(max(s.opts.First, 0))
+		handle = n <= first ||
+			s.opts.Thereafter > 0 && (n-first)%uint64(s.opts.Thereafter) == 0
+	}
+	if !handle {
+		s.pressure = true
+	}
+	s.mu.Unlock()
+
+	if !handle {
+		s.dropped.Add(1)
+	}
+	return handle
+}
+
+// Enabled reports whether the wrapped handler is enabled for level.
+func (h *SamplingHandler) Enabled(ctx context.Context, level Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle passes r to the wrapped handler if r is sampled,
+// and otherwise drops it.
+func (h *SamplingHandler) Handle(ctx context.Context, r Record) error {
+	if !h.sampler.sample(&r) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a new [SamplingHandler] that wraps the result of
+// calling WithAttrs on h's handler, and shares h's sampling state.
+func (h *SamplingHandler) WithAttrs(attrs []Attr) Handler {
+	return &SamplingHandler{handler: h.handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup returns a new [SamplingHandler] that wraps the result of
+// calling WithGroup on h's handler, and shares h's sampling state.
+func (h *SamplingHandler) WithGroup(name string) Handler {
+	return &SamplingHandler{handler: h.handler.WithGroup(name), sampler: h.sampler}
+}
+
+// Dropped returns the number of records that h and the handlers
+// derived from it have dropped.
+func (h *SamplingHandler) Dropped() uint64 {
+	return h.sampler.dropped.Load()
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recordingHandler records the messages of the records it handles.
+type recordingHandler struct {
+	msgs *[]string
+}
+
+func (h recordingHandler) Enabled(context.Context, Level) bool { return true }
+func (h recordingHandler) WithAttrs([]Attr) Handler            { return h }
+func (h recordingHandler) WithGroup(string) Handler            { return h }
+
+func (h recordingHandler) Handle(_ context.Context, r Record) error {
+	*h.msgs = append(*h.msgs, r.Message)
+	return nil
+}
+
+func TestSamplingHandler(t *testing.T) {
+	var msgs []string
+	h := NewSamplingHandler(recordingHandler{&msgs}, &SamplingOptions{
+		Interval:   time.Minute,
+		First:      2,
+		Thereafter: 3,
+		Level:      LevelWarn,
+	})
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	handle := func(h Handler, t time.Time, level Level, msg string) {
+		if err := h.Handle(ctx, NewRecord(t, level, msg, 0)); err != nil {
+			panic(err)
+		}
+	}
+
+	for i := range 10 {
+		handle(h, start, LevelInfo, "a"+string(rune('0'+i)))
+	}
+	// Each message is distinct, so none is dropped.
+	if len(msgs) != 10 || h.Dropped() != 0 {
+		t.Fatalf("distinct messages: handled %d, dropped %d", len(msgs), h.Dropped())
+	}
+
+	msgs = nil
+	for i := range 10 {
+		// Sampling state is shared with derived handlers.
+		var hh Handler = h
+		if i%2 == 0 {
+			hh = h.WithAttrs([]Attr{Int("i", i)}).WithGroup("g")
+		}
+		handle(hh, start.Add(time.Second), LevelInfo, "b")
+		handle(hh, start.Add(time.Second), LevelDebug, "b")
+		handle(hh, start.Add(time.Second), LevelError, "e")
+	}
+	// Of 10 records, the first 2 and then the 5th and 8th are handled.
+	if got, want := count(msgs, "b"), 2*4; got != want {
+		t.Errorf("sampled %d records, want %d", got, want)
+	}
+	if got, want := count(msgs, "e"), 10; got != want {
+		t.Errorf("handled %d records at LevelError, want %d", got, want)
+	}
+	if got, want := h.Dropped(), uint64(2*6); got != want {
+		t.Errorf("Dropped() = %d, want %d", got, want)
+	}
+
+	// A new interval resets the counts.
+	msgs = nil
+	handle(h, start.Add(2*time.Minute), LevelInfo, "b")
+	handle(h, start.Add(2*time.Minute), LevelInfo, "b")
+	if len(msgs) != 2 {
+		t.Errorf("new interval: handled %d records, want 2", len(msgs))
+	}
+
+	// A record from before the interval does not reset the counts.
+	msgs = nil
+	handle(h, start, LevelInfo, "b")
+	handle(h, start.Add(2*time.Minute), LevelInfo, "b")
+	if len(msgs) != 0 {
+		t.Errorf("record out of order: handled %d records, want 0", len(msgs))
+	}
+}
+
+func TestSamplingHandlerPressure(t *testing.T) {
+	var msgs []string
+	h := NewSamplingHandler(recordingHandler{&msgs}, &SamplingOptions{
+		Interval:      time.Minute,
+		First:         1,
+		Level:         LevelError,
+		PressureLevel: LevelInfo,
+	})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	handle := func(t time.Time, level Level, msg string) {
+		h.Handle(context.Background(), NewRecord(t, level, msg, 0))
+	}
+
+	handle(start, LevelDebug, "d1")
+	handle(start, LevelInfo, "i1")
+	handle(start, LevelInfo, "i1") // dropped, so under pressure
+	handle(start, LevelDebug, "d2")
+	handle(start, LevelInfo, "i2")
+	handle(start, LevelError, "e")
+	if want := []string{"d1", "i1", "i2", "e"}; !slices.Equal(msgs, want) {
+		t.Errorf("under pressure: handled %q, want %q", msgs, want)
+	}
+	if got := h.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+
+	// A new interval relieves the pressure.
+	msgs = nil
+	handle(start.Add(time.Minute), LevelDebug, "d2")
+	if want := []string{"d2"}; !slices.Equal(msgs, want) {
+		t.Errorf("new interval: handled %q, want %q", msgs, want)
+	}
+}
+
+func TestSamplingHandlerMaxKeys(t *testing.T) {
+	var msgs []string
+	h := NewSamplingHandler(recordingHandler{&msgs}, &SamplingOptions{Interval: time.Hour, First: 1})
+	now := time.Now()
+	for i := range maxSamplingKeys + 10 {
+		h.Handle(context.Background(), NewRecord(now, LevelInfo, fmt.Sprint(i), 0))
+	}
+	// The records beyond the limit are sampled as one group.
+	if got, want := len(msgs), maxSamplingKeys+1; got != want {
+		t.Errorf("handled %d records, want %d", got, want)
+	}
+	if got := len(h.sampler.counts); got != maxSamplingKeys {
+		t.Errorf("counted %d groups, want %d", got, maxSamplingKeys)
+	}
+}
+
+func TestSamplingHandlerDefaults(t *testing.T) {
+	var msgs []string
+	h := NewSamplingHandler(recordingHandler{&msgs}, nil)
+	l := New(h)
+	for range 1000 {
+		l.Info("x")
+	}
+	// The test may cross an interval boundary, so allow for it.
+	if len(msgs) < 109 || len(msgs) > 300 {
+		t.Errorf("handled %d of 1000 records, want about 109", len(msgs))
+	}
+	if got := h.Dropped(); got != 1000-uint64(len(msgs)) {
+		t.Errorf("Dropped() = %d, want %d", got, 1000-len(msgs))
+	}
+
+	// With Thereafter zero, records after the first are dropped.
+	msgs = nil
+	h = NewSamplingHandler(recordingHandler{&msgs}, &SamplingOptions{Interval: time.Hour, First: 1})
+	for range 5 {
+		h.Handle(context.Background(), NewRecord(time.Time{}, LevelInfo, "x", 0))
+	}
+	if !slices.Equal(msgs, []string{"x"}) || h.Dropped() != 4 {
+		t.Errorf("Thereafter 0: handled %q, dropped %d", msgs, h.Dropped())
+	}
+}
+
+func count(s []string, v string) int {
+	n := 0
+	for _, x := range s {
+		if x == v {
+			n++
+		}
+	}
+	return n
+}
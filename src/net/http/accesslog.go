@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// An AccessLogEntry describes a request handled by a [Server],
+// for its AccessLog function.
+//
+// The entry for a request is complete when its handler returns, or
+// panics. If the handler hijacks the connection, the entry is complete
+// when the [net.Conn] returned by Hijack is closed, which may be after
+// the handler returns. Requests rejected by the Server's [ServerLimits]
+// have entries as well.
+type AccessLogEntry struct {
+	// Request is the request as received by the server.
+	Request *Request
+
+	// Pattern is the pattern of the ServeMux that matched the request,
+	// if any. It is set even if the ServeMux was passed a copy of
+	// Request, such as one made by Request.WithContext.
+	Pattern string
+
+	// StatusCode is the status code of the response. It is zero if the
+	// handler panicked or hijacked the connection without writing a
+	// status, or if the server cannot observe the response, as for
+	// HTTP/3 connections served by another package.
+	StatusCode int
+
+	// Written is the number of bytes of response body written by the
+	// handler. For a hijacked HTTP/1 connection, it includes all of the
+	// bytes written to the connection returned by Hijack, directly or
+	// through the returned bufio.ReadWriter, until it was closed.
+	Written int64
+
+	// Hijacked reports whether the handler hijacked the connection.
+	Hijacked bool
+
+	// Start is when the server started handling the request, after
+	// reading its header, and Duration is how long handling it took.
+	// For a hijacked connection, Duration lasts until the connection
+	// was closed.
+	Start    time.Time
+	Duration time.Duration
+
+	handled bool // the handler returned normally
+}
+
+// startAccessLog returns the access log entry for req,
+// which is about to be handled.
+func startAccessLog(req *Request) *AccessLogEntry {
+	e := &AccessLogEntry{Request: req, Start: time.Now()}
+	req.accessLog = e
+	return e
+}
+
+// finishAccessLog completes e, whose response was written to rw,
+// and passes it to the AccessLog function log. If the connection was
+// hijacked, e is passed to log when the connection is closed.
+func finishAccessLog(log func(*AccessLogEntry), rw ResponseWriter, e *AccessLogEntry) {
+	observed := true
+	switch w := rw.(type) {
+	case *response:
+		e.StatusCode = w.status
+		e.Written = w.written
+		if hc := w.conn.hijackedConn(); hc != nil {
+			e.Hijacked = true
+			hc.logOnClose(log, e)
+			return
+		}
+	default:
+		e.StatusCode, e.Written, observed = http2ResponseStats(rw)
+	}
+	if e.StatusCode == 0 && observed && e.handled {
+		// The server replies 200 OK when the handler writes nothing.
+		e.StatusCode = StatusOK
+	}
+	e.Duration = time.Since(e.Start)
+	log(e)
+}
+
+// A hijackedConn is the connection returned by Hijack when the server
+// has an AccessLog. It counts the bytes written to it, and passes the
+// access log entry of the request that hijacked it to the AccessLog
+// function when it is closed.
+type hijackedConn struct {
+	net.Conn
+	written atomic.Int64
+
+	mu     sync.Mutex
+	closed bool
+	log    func(*AccessLogEntry) // set when the handler returns
+	entry  *AccessLogEntry
+}
+
+func (c *hijackedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// ReadFrom lets io.Copy use the connection's ReadFrom method, if any.
+func (c *hijackedConn) ReadFrom(r io.Reader) (int64, error) {
+	n, err := io.Copy(c.Conn, r)
+	c.written.Add(n)
+	return n, err
+}
+
+func (c *hijackedConn) Close() error {
+	err := c.Conn.Close()
+	c.mu.Lock()
+	log, e := c.log, c.entry
+	c.closed = true
+	c.log, c.entry = nil, nil
+	c.mu.Unlock()
+	if log != nil {
+		c.finish(log, e)
+	}
+	return err
+}
+
+// logOnClose passes e to log when c is closed, or now if it has been
+// closed already.
+func (c *hijackedConn) logOnClose(log func(*AccessLogEntry), e *AccessLogEntry) {
+	c.mu.Lock()
+	if !c.closed {
+		c.log, c.entry = log, e
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+	c.finish(log, e)
+}
+
+func (c *hijackedConn) finish(log func(*AccessLogEntry), e *AccessLogEntry) {
+	e.Written += c.written.Load()
+	e.Duration = time.Since(e.Start)
+	log(e)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"io"
+	"net"
+	. "net/http"
+	"testing"
+	"time"
+)
+
+// accessLogEntries returns a channel of the entries passed to the
+// AccessLog function it sets on a Server.
+func accessLogEntries(n int) (chan *AccessLogEntry, func(*Server)) {
+	entries := make(chan *AccessLogEntry, n)
+	return entries, func(s *Server) {
+		s.AccessLog = func(e *AccessLogEntry) {
+			entries <- e
+		}
+	}
+}
+
+func doAccessLogRequest(t *testing.T, cst *clientServerTest, path string) *Response {
+	t.Helper()
+	req, _ := NewRequest("GET", cst.ts.URL+path, nil)
+	req.Header.Set("X-User", "gopher")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	return res
+}
+
+func TestAccessLog(t *testing.T) { run(t, testAccessLog, []testMode{http1Mode, http2Mode}) }
+func testAccessLog(t *testing.T, mode testMode) {
+	entries, setAccessLog := accessLogEntries(1)
+	mux := NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w ResponseWriter, r *Request) {
+		w.WriteHeader(StatusCreated)
+		io.WriteString(w, "item "+r.PathValue("id"))
+	})
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		// The ServeMux is passed a copy of the request.
+		mux.ServeHTTP(w, r.WithContext(r.Context()))
+	}), setAccessLog)
+	doAccessLogRequest(t, cst, "/items/42")
+	e := <-entries
+	if e.Request.URL.Path != "/items/42" || e.Request.Header.Get("X-User") != "gopher" {
+		t.Errorf("entry request = %v %v, want /items/42 from gopher", e.Request.URL, e.Request.Header)
+	}
+	if e.Pattern != "GET /items/{id}" {
+		t.Errorf("entry pattern = %q, want %q", e.Pattern, "GET /items/{id}")
+	}
+	if e.StatusCode != StatusCreated || e.Written != int64(len("item 42")) {
+		t.Errorf("entry status %d, written %d; want %d, %d", e.StatusCode, e.Written, StatusCreated, len("item 42"))
+	}
+	if e.Start.IsZero() || e.Duration < 0 {
+		t.Errorf("entry start %v, duration %v", e.Start, e.Duration)
+	}
+	if e.Hijacked {
+		t.Errorf("entry hijacked = true, want false")
+	}
+}
+
+// Requests rejected by ServerLimits are logged.
+func TestAccessLogLimits(t *testing.T) { run(t, testAccessLogLimits, []testMode{http1Mode, http2Mode}) }
+func testAccessLogLimits(t *testing.T, mode testMode) {
+	entries, setAccessLog := accessLogEntries(2)
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {}), func(s *Server) {
+		s.Limits = &ServerLimits{ClientRate: 0.001}
+		setAccessLog(s)
+	})
+	doAccessLogRequest(t, cst, "/")
+	doAccessLogRequest(t, cst, "/")
+	for _, want := range []int{StatusOK, StatusTooManyRequests} {
+		if got := (<-entries).StatusCode; got != want {
+			t.Errorf("logged status %d, want %d", got, want)
+		}
+	}
+}
+
+func TestAccessLogHijack(t *testing.T) { run(t, testAccessLogHijack, []testMode{http1Mode}) }
+func testAccessLogHijack(t *testing.T, mode testMode) {
+	const (
+		header = "HTTP/1.1 200 OK\r\nContent-Length: 4\r\nConnection: close\r\n\r\n"
+		body1  = "hi"
+		body2  = "!!"
+	)
+	entries, setAccessLog := accessLogEntries(1)
+	returned := make(chan struct{})
+	closeConn := make(chan struct{})
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		defer close(returned)
+		conn, brw, err := NewResponseController(w).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		brw.WriteString(header)
+		brw.Flush()
+		io.WriteString(conn, body1)
+		// Finish the response after the handler returns.
+		go func() {
+			<-closeConn
+			io.WriteString(conn, body2)
+			conn.Close()
+		}()
+	}), func(s *Server) {
+		setAccessLog(s)
+		s.ConnState = func(c net.Conn, state ConnState) {
+			// The ConnState hook sees the underlying connection.
+			if _, ok := c.(*net.TCPConn); state == StateHijacked && !ok {
+				t.Errorf("ConnState called with %T, want *net.TCPConn", c)
+			}
+		}
+	})
+
+	errc := make(chan error)
+	go func() {
+		res, err := cst.c.Get(cst.ts.URL)
+		if err == nil {
+			_, err = io.ReadAll(res.Body)
+			res.Body.Close()
+		}
+		errc <- err
+	}()
+	<-returned
+	// The entry is not logged while the hijacked connection is open.
+	select {
+	case <-entries:
+		t.Fatalf("entry logged before the hijacked connection was closed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(closeConn)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+
+	e := <-entries
+	if !e.Hijacked {
+		t.Errorf("entry hijacked = false, want true")
+	}
+	if got, want := e.Written, int64(len(header+body1+body2)); got != want {
+		t.Errorf("entry written = %d, want %d", got, want)
+	}
+	if e.StatusCode != 0 {
+		t.Errorf("entry status = %d, want 0", e.StatusCode)
+	}
+}
@@ -9,6 +9,7 @@ package http_test
 import (
 	"bytes"
 	"compress/gzip"
+	"compress/zstd"
 	"context"
 	"crypto/rand"
 	"crypto/sha1"
@@ -773,8 +774,8 @@ func testHandlerWritesTooMuch(t *testing.T, mode testMode) {
 func TestH12_AutoGzip(t *testing.T) {
 	h12Compare{
 		Handler: func(w ResponseWriter, r *Request) {
-			if ae := r.Header.Get("Accept-Encoding"); ae != "gzip" {
-				t.Errorf("%s Accept-Encoding = %q; want gzip", r.Proto, ae)
+			if ae := r.Header.Get("Accept-Encoding"); ae != "gzip, zstd" {
+				t.Errorf("%s Accept-Encoding = %q; want gzip, zstd", r.Proto, ae)
 			}
 			w.Header().Set("Content-Encoding", "gzip")
 			gz := gzip.NewWriter(w)
@@ -784,6 +785,55 @@ func TestH12_AutoGzip(t *testing.T) {
 	}.run(t)
 }
 
+// Verify that both our HTTP/1 and HTTP/2 request and auto-decompress zstd.
+func TestH12_AutoZstd(t *testing.T) {
+	const content = "I am some zstd compressed content. Go go go go go go go go go go go go should compress well."
+	h12Compare{
+		Handler: func(w ResponseWriter, r *Request) {
+			if ae := r.Header.Get("Accept-Encoding"); ae != "gzip, zstd" {
+				t.Errorf("%s Accept-Encoding = %q; want gzip, zstd", r.Proto, ae)
+			}
+			w.Header().Set("Content-Encoding", "zstd")
+			zw := zstd.NewWriter(w)
+			io.WriteString(zw, content)
+			zw.Close()
+		},
+		CheckResponse: func(proto string, res *Response) {
+			if got := res.Body.(slurpResult).body; string(got) != content {
+				t.Errorf("%s body = %q; want %q", proto, got, content)
+			}
+			if !res.Uncompressed {
+				t.Errorf("%s Uncompressed = false; want true", proto)
+			}
+		},
+	}.run(t)
+}
+
+func TestTransportZstdGODEBUG(t *testing.T) {
+	run(t, testTransportZstdGODEBUG, testNotParallel, http3SkippedMode)
+}
+func testTransportZstdGODEBUG(t *testing.T, mode testMode) {
+	t.Setenv("GODEBUG", "httpzstd=0")
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if ae := r.Header.Get("Accept-Encoding"); ae != "gzip" {
+			t.Errorf("Accept-Encoding = %q; want gzip", ae)
+		}
+		// Not requested, so not decoded by the Transport.
+		w.Header().Set("Content-Encoding", "zstd")
+		zw := zstd.NewWriter(w)
+		io.WriteString(zw, "compressed")
+		zw.Close()
+	}))
+	res, err := cst.c.Get(cst.ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ce := res.Header.Get("Content-Encoding"); ce != "zstd" || res.Uncompressed {
+		t.Errorf("Content-Encoding = %q, Uncompressed = %v; want zstd, false", ce, res.Uncompressed)
+	}
+}
+
 func TestH12_AutoGzip_Disabled(t *testing.T) {
 	h12Compare{
 		Opts: []any{
@@ -0,0 +1,400 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"compress/zstd"
+	"io"
+	"mime"
+	"net"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// CompressOptions are options for [CompressHandler].
+type CompressOptions struct {
+	// MinSize is the minimum size in bytes of a response body to compress.
+	// Smaller responses are sent uncompressed.
+	// If MinSize is zero, a default of 1024 is used.
+	MinSize int
+
+	// Encodings lists the content codings the handler may use, in order
+	// of preference. The supported codings are "zstd", "gzip" and "deflate".
+	// If Encodings is nil, all three are used, in that order.
+	Encodings []string
+
+	// Compressible reports whether a response with the given
+	// Content-Type should be compressed.
+	// If Compressible is nil, responses are compressed unless their
+	// media type is one that is usually already compressed, such as
+	// an image, audio, video, or archive type.
+	Compressible func(contentType string) bool
+}
+
+// CompressHandler returns a [Handler] that runs h, compressing its
+// responses with a content coding chosen from the request's
+// Accept-Encoding header. If opts is nil, the default options are used.
+//
+// The handler adds "Accept-Encoding" to the response's Vary header.
+// It does not compress a response if:
+//   - the client does not accept any of the configured encodings;
+//   - the request method is HEAD, or the request has a Range header;
+//   - the status code does not permit a body, or is 206 Partial Content;
+//   - h sets a Content-Encoding header, or a Cache-Control header
+//     with the no-transform directive;
+//   - the response's Content-Type is not compressible; or
+//   - the response body is smaller than [CompressOptions.MinSize]
+//     and is not flushed before h returns.
+//
+// If the response has no Content-Type header, one is set from the
+// first bytes of the uncompressed body, as done by [ResponseWriter].
+//
+// When it compresses a response, the handler sets the Content-Encoding
+// header, deletes the Content-Length and Accept-Ranges headers, and
+// converts a strong ETag to a weak one, since the compressed
+// representation is not byte-for-byte identical to the original.
+// Weak ETags still match the If-None-Match conditions evaluated by
+// [ServeContent].
+//
+// The [ResponseWriter] passed to h supports [Flusher] and [Hijacker]
+// and may be used with [ResponseController]. Flushing the response
+// flushes the compressor and then the underlying ResponseWriter.
+func CompressHandler(h Handler, opts *CompressOptions) Handler {
+	c := &compressHandler{
+		handler:      h,
+		minSize:      1024,
+		encodings:    []string{"zstd", "gzip", "deflate"},
+		compressible: compressibleContentType,
+	}
+	if opts != nil {
+		if opts.MinSize > 0 {
+			c.minSize = opts.MinSize
+		}
+		if opts.Encodings != nil {
+			c.encodings = nil
+			for _, e := range opts.Encodings {
+				if _, ok := compressorPools[e]; ok {
+					c.encodings = append(c.encodings, e)
+				}
+			}
+		}
+		if opts.Compressible != nil {
+			c.compressible = opts.Compressible
+		}
+	}
+	return c
+}
+
+type compressHandler struct {
+	handler      Handler
+	minSize      int
+	encodings    []string
+	compressible func(contentType string) bool
+}
+
+func (c *compressHandler) ServeHTTP(w ResponseWriter, r *Request) {
+	h := w.Header()
+	if !httpguts.HeaderValuesContainsToken(h["Vary"], "Accept-Encoding") {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	encoding := ""
+	if r.Method != "HEAD" && r.Header.Get("Range") == "" {
+		encoding = c.negotiate(r.Header["Accept-Encoding"])
+	}
+	if encoding == "" {
+		c.handler.ServeHTTP(w, r)
+		return
+	}
+	cw := &compressWriter{
+		rw:       w,
+		c:        c,
+		encoding: encoding,
+	}
+	// If the handler panics, including with ErrAbortHandler, the
+	// response is incomplete, and is not finished.
+	finish := false
+	defer func() { cw.close(finish) }()
+	c.handler.ServeHTTP(cw, r)
+	finish = true
+}
+
+// negotiate returns the preferred encoding acceptable
+// according to the Accept-Encoding header values, or "".
+func (c *compressHandler) negotiate(accept []string) string {
+	if len(accept) == 0 {
+		return ""
+	}
+	qs := make(map[string]float64)
+	for _, v := range accept {
+		for part := range strings.SplitSeq(v, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding, _ = ascii.ToLower(textproto.TrimString(coding))
+			if coding == "x-gzip" {
+				coding = "gzip"
+			}
+			if coding == "" {
+				continue
+			}
+			q := 1.0
+			for param := range strings.SplitSeq(params, ";") {
+				k, v, _ := strings.Cut(param, "=")
+				if ascii.EqualFold(textproto.TrimString(k), "q") {
+					f, err := strconv.ParseFloat(textproto.TrimString(v), 64)
+					if err != nil || f < 0 || f > 1 {
+						f = 0
+					}
+					q = f
+				}
+			}
+			qs[coding] = q
+		}
+	}
+	best, bestQ := "", 0.0
+	for _, e := range c.encodings {
+		q, ok := qs[e]
+		if !ok {
+			q = qs["*"]
+		}
+		if q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+// compressibleContentType is the default for CompressOptions.Compressible.
+func compressibleContentType(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt, _ = ascii.ToLower(textproto.TrimString(contentType))
+	}
+	switch mt {
+	case "image/svg+xml", "image/bmp", "image/x-icon", "image/vnd.microsoft.icon":
+		return true
+	case "application/octet-stream",
+		"application/gzip", "application/x-gzip", "application/zstd",
+		"application/zip", "application/x-bzip2", "application/x-xz",
+		"application/x-7z-compressed", "application/vnd.rar", "application/x-rar-compressed",
+		"application/pdf", "font/woff", "font/woff2",
+		"multipart/byteranges":
+		return false
+	}
+	for _, prefix := range []string{"image/", "audio/", "video/"} {
+		if strings.HasPrefix(mt, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// A compressor is a compressing writer that can be reused.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+var compressorPools = map[string]*sync.Pool{
+	"gzip":    {New: func() any { return gzip.NewWriter(nil) }},
+	"deflate": {New: func() any { return zlib.NewWriter(nil) }},
+	"zstd":    {New: func() any { return zstd.NewWriter(nil) }},
+}
+
+// A compressWriter is the ResponseWriter passed to the handler
+// wrapped by CompressHandler.
+//
+// It holds back the response until it has enough of the body to decide
+// whether to compress it, or until the handler flushes or returns.
+type compressWriter struct {
+	rw       ResponseWriter
+	c        *compressHandler
+	encoding string
+
+	wroteHeader bool
+	status      int
+	decided     bool       // header written to rw
+	zw          compressor // compressor in use, or nil
+	buf         []byte     // body held back until decided
+	hijacked    bool
+}
+
+var (
+	_ Flusher  = (*compressWriter)(nil)
+	_ Hijacker = (*compressWriter)(nil)
+)
+
+func (w *compressWriter) Header() Header {
+	return w.rw.Header()
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if code >= 100 && code <= 199 && code != StatusSwitchingProtocols {
+		w.rw.WriteHeader(code)
+		return
+	}
+	if w.wroteHeader {
+		if w.decided {
+			// Let the ResponseWriter report the superfluous call.
+			w.rw.WriteHeader(code)
+		}
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if !w.eligible() {
+		w.decide(false)
+	}
+}
+
+// eligible reports whether the response may be compressed,
+// judging by its status and header.
+func (w *compressWriter) eligible() bool {
+	if !bodyAllowedForStatus(w.status) || w.status == StatusPartialContent || w.status == StatusSwitchingProtocols {
+		return false
+	}
+	h := w.rw.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	if httpguts.HeaderValuesContainsToken(h["Cache-Control"], "no-transform") {
+		return false
+	}
+	if cl := h.Get("Content-Length"); cl != "" {
+		if n, err := strconv.ParseInt(cl, 10, 64); err == nil && n < int64(w.c.minSize) {
+			return false
+		}
+	}
+	if ct := h.Get("Content-Type"); ct != "" && !w.c.compressible(ct) {
+		return false
+	}
+	return true
+}
+
+// decide writes the header, compressing the response if compress is
+// true and the response is eligible, and then writes the held back body.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	h := w.rw.Header()
+	if _, haveType := h["Content-Type"]; !haveType && h.Get("Content-Encoding") == "" && bodyAllowedForStatus(w.status) {
+		h.Set("Content-Type", DetectContentType(w.buf))
+	}
+	if compress && w.eligible() {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		if etag := h.Get("Etag"); strings.HasPrefix(etag, `"`) {
+			h.Set("Etag", "W/"+etag)
+		}
+		w.zw = compressorPools[w.encoding].Get().(compressor)
+		w.zw.Reset(w.rw)
+	}
+	w.rw.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.zw != nil {
+		_, err := w.zw.Write(buf)
+		return err
+	}
+	_, err := w.rw.Write(buf)
+	return err
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.hijacked {
+		return 0, ErrHijacked
+	}
+	if !w.wroteHeader {
+		w.WriteHeader(StatusOK)
+	}
+	if w.decided {
+		if w.zw != nil {
+			return w.zw.Write(p)
+		}
+		return w.rw.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.c.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush implements [Flusher].
+func (w *compressWriter) Flush() {
+	w.FlushError()
+}
+
+// FlushError flushes the compressor and the underlying ResponseWriter.
+// It is called by [ResponseController.Flush].
+func (w *compressWriter) FlushError() error {
+	if w.hijacked {
+		return ErrHijacked
+	}
+	if !w.wroteHeader {
+		w.WriteHeader(StatusOK)
+	}
+	if !w.decided {
+		// A flushed response is a stream; compress it
+		// even if it is small so far.
+		if err := w.decide(true); err != nil {
+			return err
+		}
+	}
+	if w.zw != nil {
+		if err := w.zw.Flush(); err != nil {
+			return err
+		}
+	}
+	return NewResponseController(w.rw).Flush()
+}
+
+// Hijack implements [Hijacker]. Any response held back by the
+// compressor is discarded.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := NewResponseController(w.rw).Hijack()
+	if err == nil {
+		w.hijacked = true
+		w.buf = nil
+	}
+	return conn, brw, err
+}
+
+// Unwrap returns the underlying ResponseWriter,
+// for use by [ResponseController].
+func (w *compressWriter) Unwrap() ResponseWriter {
+	return w.rw
+}
+
+// close finishes the response after the handler returns, if finish
+// is true, and returns the compressor to its pool.
+func (w *compressWriter) close(finish bool) {
+	if finish && !w.hijacked && w.wroteHeader {
+		if !w.decided {
+			// The body is smaller than the minimum size.
+			w.decide(false)
+		}
+		if w.zw != nil {
+			w.zw.Close()
+		}
+	}
+	if w.zw != nil {
+		w.zw.Reset(nil)
+		compressorPools[w.encoding].Put(w.zw)
+		w.zw = nil
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"compress/zstd"
+	"io"
+	. "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var compressBody = strings.Repeat("Hello, compressed world! ", 100)
+
+func decompress(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	var err error
+	switch encoding {
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(body))
+	case "zstd":
+		r = zstd.NewReader(bytes.NewReader(body))
+	case "":
+		return string(body)
+	default:
+		t.Fatalf("unknown encoding %q", encoding)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decompressing %s: %v", encoding, err)
+	}
+	return string(b)
+}
+
+func TestCompressHandlerNegotiation(t *testing.T) {
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, compressBody)
+	}), nil)
+	for _, tt := range []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"x-gzip", "gzip"},
+		{"GZIP, deflate", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip, deflate, zstd", "zstd"},
+		{"gzip;q=1.0, zstd;q=0.5", "gzip"},
+		{"zstd;q=0, gzip;q=0.1", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.5, zstd;q=0", "gzip"},
+		{"identity", ""},
+		{"br", ""},
+		{"gzip;q=0", ""},
+		{"gzip;q=bogus", ""},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Encoding", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		res := rec.Result()
+		if got := res.Header.Get("Content-Encoding"); got != tt.want {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want %q", tt.accept, got, tt.want)
+			continue
+		}
+		if got := res.Header.Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("Accept-Encoding %q: Vary = %q", tt.accept, got)
+		}
+		if got := res.Header.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+			t.Errorf("Accept-Encoding %q: Content-Type = %q", tt.accept, got)
+		}
+		if got := decompress(t, tt.want, rec.Body.Bytes()); got != compressBody {
+			t.Errorf("Accept-Encoding %q: body = %q", tt.accept, got)
+		}
+	}
+}
+
+func TestCompressHandlerSkip(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opts    *CompressOptions
+		method  string
+		reqHdr  Header
+		handler func(w ResponseWriter)
+		want    bool
+	}{{
+		name:    "default",
+		handler: func(w ResponseWriter) { io.WriteString(w, compressBody) },
+		want:    true,
+	}, {
+		name:    "small",
+		handler: func(w ResponseWriter) { io.WriteString(w, "small") },
+	}, {
+		name: "small chunks",
+		handler: func(w ResponseWriter) {
+			for range 100 {
+				io.WriteString(w, "0123456789abcdef")
+			}
+		},
+		want: true,
+	}, {
+		name: "small Content-Length",
+		handler: func(w ResponseWriter) {
+			w.Header().Set("Content-Length", "5")
+			io.WriteString(w, "small")
+		},
+	}, {
+		name:    "MinSize",
+		opts:    &CompressOptions{MinSize: 1},
+		handler: func(w ResponseWriter) { io.WriteString(w, "small") },
+		want:    true,
+	}, {
+		name: "already encoded",
+		handler: func(w ResponseWriter) {
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, compressBody)
+		},
+	}, {
+		name: "no-transform",
+		handler: func(w ResponseWriter) {
+			w.Header().Set("Cache-Control", "public, no-transform")
+			io.WriteString(w, compressBody)
+		},
+	}, {
+		name: "image",
+		handler: func(w ResponseWriter) {
+			w.Header().Set("Content-Type", "image/png")
+			io.WriteString(w, compressBody)
+		},
+	}, {
+		name: "svg",
+		handler: func(w ResponseWriter) {
+			w.Header().Set("Content-Type", "image/svg+xml")
+			io.WriteString(w, compressBody)
+		},
+		want: true,
+	}, {
+		name:    "sniffed gzip",
+		handler: func(w ResponseWriter) { w.Write(append([]byte("\x1f\x8b\x08"), compressBody...)) },
+	}, {
+		name: "Compressible",
+		opts: &CompressOptions{Compressible: func(ct string) bool { return !strings.HasPrefix(ct, "text/") }},
+		handler: func(w ResponseWriter) {
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, compressBody)
+		},
+	}, {
+		name: "no content",
+		handler: func(w ResponseWriter) {
+			w.WriteHeader(StatusNoContent)
+		},
+	}, {
+		name:    "HEAD",
+		method:  "HEAD",
+		handler: func(w ResponseWriter) { io.WriteString(w, compressBody) },
+	}, {
+		name:    "Range",
+		reqHdr:  Header{"Range": {"bytes=0-10"}},
+		handler: func(w ResponseWriter) { io.WriteString(w, compressBody) },
+	}, {
+		name:    "Encodings",
+		opts:    &CompressOptions{Encodings: []string{"br", "deflate"}},
+		handler: func(w ResponseWriter) { io.WriteString(w, compressBody) },
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			for k, v := range tt.reqHdr {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+				tt.handler(w)
+			}), tt.opts).ServeHTTP(rec, req)
+			compressed := rec.Result().Header.Get("Content-Encoding") == "gzip"
+			if compressed != tt.want {
+				t.Errorf("compressed = %v, want %v", compressed, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressHandlerServeContent(t *testing.T) {
+	modtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("ETag", `"v1"`)
+		ServeContent(w, r, "file.txt", modtime, strings.NewReader(compressBody))
+	}), nil)
+
+	serve := func(hdr Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header = hdr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(Header{"Accept-Encoding": {"gzip"}})
+	res := rec.Result()
+	if res.StatusCode != 200 || res.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("got status %v, Content-Encoding %q", res.Status, res.Header.Get("Content-Encoding"))
+	}
+	if got := res.Header.Get("Etag"); got != `W/"v1"` {
+		t.Errorf("ETag = %q, want weak", got)
+	}
+	if cl, ar := res.Header.Get("Content-Length"), res.Header.Get("Accept-Ranges"); cl != "" || ar != "" {
+		t.Errorf("Content-Length = %q, Accept-Ranges = %q; want neither", cl, ar)
+	}
+	if got := decompress(t, "gzip", rec.Body.Bytes()); got != compressBody {
+		t.Errorf("body = %q", got)
+	}
+
+	// The weak ETag matches If-None-Match.
+	rec = serve(Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {`W/"v1"`}})
+	if rec.Code != StatusNotModified || rec.Header().Get("Content-Encoding") != "" {
+		t.Errorf("If-None-Match: got status %d, Content-Encoding %q; want 304 and none", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+
+	// A range request is served uncompressed.
+	rec = serve(Header{"Accept-Encoding": {"gzip"}, "Range": {"bytes=0-4"}})
+	if rec.Code != StatusPartialContent || rec.Body.String() != compressBody[:5] || rec.Header().Get("Etag") != `"v1"` {
+		t.Errorf("Range: got status %d, body %q, ETag %q", rec.Code, rec.Body.String(), rec.Header().Get("Etag"))
+	}
+}
+
+func TestCompressHandlerFlush(t *testing.T) {
+	run(t, testCompressHandlerFlush, http3SkippedMode)
+}
+func testCompressHandlerFlush(t *testing.T, mode testMode) {
+	next := make(chan bool)
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := range 3 {
+			io.WriteString(w, "data: event\n\n")
+			if i%2 == 0 {
+				w.(Flusher).Flush()
+			} else if err := NewResponseController(w).Flush(); err != nil {
+				t.Error(err)
+			}
+			<-next
+		}
+	}), nil))
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ce := res.Header.Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", ce)
+	}
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, len("data: event\n\n"))
+	for range 3 {
+		// Each event is readable before the handler continues.
+		if _, err := io.ReadFull(zr, buf); err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != "data: event\n\n" {
+			t.Fatalf("read %q", buf)
+		}
+		next <- true
+	}
+	if rest, err := io.ReadAll(zr); err != nil || len(rest) != 0 {
+		t.Errorf("after events: read %q, %v", rest, err)
+	}
+}
+
+func TestCompressHandlerPanic(t *testing.T) {
+	run(t, testCompressHandlerPanic, http3SkippedMode)
+}
+func testCompressHandlerPanic(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		io.WriteString(w, compressBody)
+		w.(Flusher).Flush()
+		panic(ErrAbortHandler)
+	}), nil))
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	zr, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The compressed stream is not finished.
+	if b, err := io.ReadAll(zr); err == nil {
+		t.Errorf("read %d bytes of aborted response without error", len(b))
+	}
+}
+
+func TestCompressHandlerHijack(t *testing.T) {
+	cst := newClientServerTest(t, http1Mode, CompressHandler(HandlerFunc(func(w ResponseWriter, r *Request) {
+		conn, _, err := w.(Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+	}), nil))
+	req, _ := NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if b, _ := io.ReadAll(res.Body); string(b) != "ok" {
+		t.Errorf("body = %q, want ok", b)
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookiejar
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An Entry is a cookie stored in a [Jar], with the attributes
+// described in RFC 6265 section 5.3.
+type Entry struct {
+	Name     string
+	Value    string
+	Quoted   bool // whether Value is sent in double quotes
+	Domain   string
+	Path     string
+	SameSite http.SameSite
+	Secure   bool
+	HttpOnly bool
+
+	// Persistent reports whether the cookie expires at Expires.
+	// A cookie that is not persistent is a session cookie,
+	// and its Expires is the zero time.
+	Persistent bool
+	Expires    time.Time
+
+	// HostOnly reports whether the cookie is sent only to Domain,
+	// rather than to Domain and its subdomains.
+	HostOnly bool
+
+	Creation   time.Time
+	LastAccess time.Time
+}
+
+// All returns an iterator over the cookies in the jar that have not
+// expired, in order of creation. The iterator yields a snapshot of
+// the jar taken when iteration begins.
+func (j *Jar) All() iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		for _, e := range j.snapshot(time.Now()) {
+			if !yield(e.export()) {
+				return
+			}
+		}
+	}
+}
+
+// snapshot returns the unexpired entries in order of creation.
+func (j *Jar) snapshot(now time.Time) []entry {
+	j.mu.Lock()
+	var list []entry
+	for _, submap := range j.entries {
+		for _, e := range submap {
+			if e.Persistent && !e.Expires.After(now) {
+				continue
+			}
+			list = append(list, e)
+		}
+	}
+	j.mu.Unlock()
+	slices.SortFunc(list, func(a, b entry) int {
+		if r := a.Creation.Compare(b.Creation); r != 0 {
+			return r
+		}
+		return cmp.Compare(a.seqNum, b.seqNum)
+	})
+	return list
+}
+
+func (e *entry) export() Entry {
+	x := Entry{
+		Name:       e.Name,
+		Value:      e.Value,
+		Quoted:     e.Quoted,
+		Domain:     e.Domain,
+		Path:       e.Path,
+		Secure:     e.Secure,
+		HttpOnly:   e.HttpOnly,
+		Persistent: e.Persistent,
+		HostOnly:   e.HostOnly,
+		Creation:   e.Creation,
+		LastAccess: e.LastAccess,
+	}
+	if e.Persistent {
+		x.Expires = e.Expires
+	}
+	switch e.SameSite {
+	case "SameSite":
+		x.SameSite = http.SameSiteDefaultMode
+	case "SameSite=Strict":
+		x.SameSite = http.SameSiteStrictMode
+	case "SameSite=Lax":
+		x.SameSite = http.SameSiteLaxMode
+	}
+	return x
+}
+
+// Add adds the given entries to the jar, replacing any cookies with
+// the same name, domain and path. It is intended for restoring cookies
+// saved from the [Jar.All] method of this or another jar.
+//
+// Entries that have expired are ignored. A zero Creation or LastAccess
+// time is set to the current time. Entries whose Domain is not a valid
+// host name or IP address, or that are domain cookies for a public suffix,
+// are not added, and Add returns an error describing them.
+func (j *Jar) Add(entries ...Entry) error {
+	return j.add(entries, time.Now())
+}
+
+func (j *Jar) add(entries []Entry, now time.Time) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var errs []error
+	for _, x := range entries {
+		e, err := j.importEntry(x, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cookie %q for domain %q: %w", x.Name, x.Domain, err))
+			continue
+		}
+		if e.Persistent && !e.Expires.After(now) {
+			continue
+		}
+		key := jarKey(e.Domain, j.psList)
+		submap := j.entries[key]
+		if submap == nil {
+			submap = make(map[string]entry)
+			j.entries[key] = submap
+		}
+		e.seqNum = j.nextSeqNum
+		j.nextSeqNum++
+		submap[e.id()] = e
+	}
+	return errors.Join(errs...)
+}
+
+// importEntry validates x and converts it to an entry.
+func (j *Jar) importEntry(x Entry, now time.Time) (entry, error) {
+	domain, err := canonicalHost(strings.TrimPrefix(x.Domain, "."))
+	if err != nil {
+		return entry{}, err
+	}
+	if domain == "" {
+		return entry{}, errMalformedDomain
+	}
+	hostOnly := x.HostOnly || isIP(domain)
+	if !hostOnly && j.psList != nil && j.psList.PublicSuffix(domain) == domain {
+		return entry{}, errIllegalDomain
+	}
+	e := entry{
+		Name:       x.Name,
+		Value:      x.Value,
+		Quoted:     x.Quoted,
+		Domain:     domain,
+		Path:       x.Path,
+		Secure:     x.Secure,
+		HttpOnly:   x.HttpOnly,
+		Persistent: x.Persistent,
+		HostOnly:   hostOnly,
+		Expires:    endOfTime,
+		Creation:   x.Creation,
+		LastAccess: x.LastAccess,
+	}
+	if e.Path == "" || e.Path[0] != '/' {
+		e.Path = "/"
+	}
+	if e.Persistent {
+		e.Expires = x.Expires
+	}
+	if e.Creation.IsZero() {
+		e.Creation = now
+	}
+	if e.LastAccess.IsZero() {
+		e.LastAccess = now
+	}
+	switch x.SameSite {
+	case http.SameSiteDefaultMode:
+		e.SameSite = "SameSite"
+	case http.SameSiteStrictMode:
+		e.SameSite = "SameSite=Strict"
+	case http.SameSiteLaxMode:
+		e.SameSite = "SameSite=Lax"
+	}
+	return e, nil
+}
+
+// RemoveDomain removes the cookies whose Domain is domain or a
+// subdomain of it, and returns the number of cookies removed.
+func (j *Jar) RemoveDomain(domain string) int {
+	domain, err := canonicalHost(strings.TrimPrefix(domain, "."))
+	if err != nil || domain == "" {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	n := 0
+	for key, submap := range j.entries {
+		for id, e := range submap {
+			if e.Domain == domain || hasDotSuffix(e.Domain, domain) {
+				delete(submap, id)
+				n++
+			}
+		}
+		if len(submap) == 0 {
+			delete(j.entries, key)
+		}
+	}
+	return n
+}
+
+// jsonEntry is the JSON representation of an Entry.
+type jsonEntry struct {
+	Name       string    `json:"name"`
+	Value      string    `json:"value"`
+	Quoted     bool      `json:"quoted,omitzero"`
+	Domain     string    `json:"domain"`
+	Path       string    `json:"path"`
+	SameSite   string    `json:"sameSite,omitzero"`
+	Secure     bool      `json:"secure,omitzero"`
+	HttpOnly   bool      `json:"httpOnly,omitzero"`
+	Persistent bool      `json:"persistent,omitzero"`
+	Expires    time.Time `json:"expires,omitzero"`
+	HostOnly   bool      `json:"hostOnly,omitzero"`
+	Creation   time.Time `json:"creation"`
+	LastAccess time.Time `json:"lastAccess"`
+}
+
+var sameSiteNames = map[http.SameSite]string{
+	http.SameSiteDefaultMode: "Default",
+	http.SameSiteLaxMode:     "Lax",
+	http.SameSiteStrictMode:  "Strict",
+	http.SameSiteNoneMode:    "None",
+}
+
+// Save writes the cookies in the jar that have not expired to w,
+// as a JSON array with one object per cookie, in order of creation.
+// Session cookies are included.
+// The cookies can be restored with [Jar.Load].
+func (j *Jar) Save(w io.Writer) error {
+	list := []jsonEntry{}
+	for x := range j.All() {
+		list = append(list, jsonEntry{
+			Name:       x.Name,
+			Value:      x.Value,
+			Quoted:     x.Quoted,
+			Domain:     x.Domain,
+			Path:       x.Path,
+			SameSite:   sameSiteNames[x.SameSite],
+			Secure:     x.Secure,
+			HttpOnly:   x.HttpOnly,
+			Persistent: x.Persistent,
+			Expires:    x.Expires,
+			HostOnly:   x.HostOnly,
+			Creation:   x.Creation,
+			LastAccess: x.LastAccess,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(list)
+}
+
+// Load reads cookies written by [Jar.Save] from r and adds them to
+// the jar as described for [Jar.Add].
+func (j *Jar) Load(r io.Reader) error {
+	var list []jsonEntry
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return fmt.Errorf("cookiejar: %w", err)
+	}
+	entries := make([]Entry, 0, len(list))
+	for _, x := range list {
+		e := Entry{
+			Name:       x.Name,
+			Value:      x.Value,
+			Quoted:     x.Quoted,
+			Domain:     x.Domain,
+			Path:       x.Path,
+			Secure:     x.Secure,
+			HttpOnly:   x.HttpOnly,
+			Persistent: x.Persistent,
+			Expires:    x.Expires,
+			HostOnly:   x.HostOnly,
+			Creation:   x.Creation,
+			LastAccess: x.LastAccess,
+		}
+		for mode, name := range sameSiteNames {
+			if x.SameSite == name {
+				e.SameSite = mode
+			}
+		}
+		entries = append(entries, e)
+	}
+	return j.Add(entries...)
+}
+
+// netscapeHeader is the first line of a Netscape cookies.txt file.
+const netscapeHeader = "# Netscape HTTP Cookie File"
+
+// httpOnlyPrefix marks the domain of an HttpOnly cookie
+// in a cookies.txt file, as done by curl and browsers.
+const httpOnlyPrefix = "#HttpOnly_"
+
+// SaveNetscape writes the cookies in the jar that have not expired to w
+// in the Netscape cookies.txt format used by curl, wget, and browser
+// extensions. Session cookies are written with an expiration time of 0.
+//
+// The format does not record the SameSite, Quoted, creation, and last
+// access attributes of cookies.
+func (j *Jar) SaveNetscape(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(netscapeHeader + "\n\n")
+	for x := range j.All() {
+		domain := x.Domain
+		if !x.HostOnly {
+			domain = "." + domain
+		}
+		if x.HttpOnly {
+			domain = httpOnlyPrefix + domain
+		}
+		var expires int64
+		if x.Persistent {
+			expires = x.Expires.Unix()
+		}
+		fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, netscapeBool(!x.HostOnly), x.Path, netscapeBool(x.Secure),
+			expires, x.Name, x.Value)
+	}
+	return bw.Flush()
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// LoadNetscape reads cookies in the Netscape cookies.txt format from r
+// and adds them to the jar as described for [Jar.Add].
+// A cookie with an expiration time of 0 is a session cookie.
+func (j *Jar) LoadNetscape(r io.Reader) error {
+	var entries []Entry
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimRight(s.Text(), "\r")
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(text, httpOnlyPrefix); ok {
+			text = rest
+			httpOnly = true
+		}
+		if textproto.TrimString(text) == "" || text[0] == '#' {
+			continue
+		}
+		f := strings.Split(text, "\t")
+		if len(f) != 7 {
+			return fmt.Errorf("cookiejar: line %d: got %d fields, want 7", line, len(f))
+		}
+		includeSubdomains, err1 := parseNetscapeBool(f[1])
+		secure, err2 := parseNetscapeBool(f[3])
+		expires, err3 := strconv.ParseInt(f[4], 10, 64)
+		if err := cmp.Or(err1, err2, err3); err != nil {
+			return fmt.Errorf("cookiejar: line %d: %w", line, err)
+		}
+		e := Entry{
+			Name:     f[5],
+			Value:    f[6],
+			Domain:   f[0],
+			Path:     f[2],
+			Secure:   secure,
+			HttpOnly: httpOnly,
+			HostOnly: !includeSubdomains,
+		}
+		if expires != 0 {
+			e.Persistent = true
+			e.Expires = time.Unix(expires, 0)
+		}
+		entries = append(entries, e)
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return j.Add(entries...)
+}
+
+func parseNetscapeBool(s string) (bool, error) {
+	switch s {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookiejar
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setTestCookies sets cookies in jar as if from responses to
+// requests for the given URLs.
+func setTestCookies(t *testing.T, jar *Jar, cookies []testResponseCookies) {
+	t.Helper()
+	for _, rc := range cookies {
+		u, err := url.Parse(rc.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cs []*http.Cookie
+		for _, line := range rc.lines {
+			c, err := http.ParseSetCookie(line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs = append(cs, c)
+		}
+		jar.SetCookies(u, cs)
+	}
+}
+
+// cookieStrings returns the cookies jar would send to each of the URLs.
+func cookieStrings(t *testing.T, jar *Jar, urls ...string) []string {
+	t.Helper()
+	var out []string
+	for _, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cs []string
+		for _, c := range jar.Cookies(u) {
+			cs = append(cs, c.String())
+		}
+		out = append(out, rawURL+": "+strings.Join(cs, " "))
+	}
+	return out
+}
+
+// testResponseCookies holds the Set-Cookie lines of a response from url.
+type testResponseCookies struct {
+	url   string
+	lines []string
+}
+
+// persistTestCookies are set in order, so that the order of the jar's
+// entries is deterministic.
+var persistTestCookies = []testResponseCookies{
+	{"https://www.host.test/dir/", []string{
+		"a=1",
+		"b=2; Domain=host.test; Path=/; Max-Age=3600; Secure; HttpOnly; SameSite=Lax",
+		`q="quoted value"; Max-Age=3600`,
+		"gone=x; Max-Age=-1",
+	}},
+	{"http://www.google.com/", []string{
+		"c=3; Domain=google.com",
+		"d=4; Expires=Fri, 01 Jan 2100 00:00:00 GMT",
+	}},
+	{"http://192.168.0.10/", []string{"ip=5"}},
+}
+
+var persistTestURLs = []string{
+	"https://www.host.test/dir/x",
+	"https://host.test/",
+	"http://www.google.com/",
+	"http://mail.google.com/",
+	"http://192.168.0.10/",
+}
+
+func TestSaveLoad(t *testing.T) {
+	jar := newTestJar()
+	setTestCookies(t, jar, persistTestCookies)
+	var buf bytes.Buffer
+	if err := jar.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	jar2 := newTestJar()
+	if err := jar2.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := slices.Collect(jar2.All()), slices.Collect(jar.All()); len(got) != 6 || !entriesEqual(got, want) {
+		t.Errorf("after Save and Load:\nAll() = %+v\nwant    %+v", got, want)
+	}
+	want := cookieStrings(t, jar, persistTestURLs...)
+	if got := cookieStrings(t, jar2, persistTestURLs...); !slices.Equal(got, want) {
+		t.Errorf("after Save and Load:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func entriesEqual(a, b []Entry) bool {
+	return slices.EqualFunc(a, b, func(x, y Entry) bool {
+		return x.Name == y.Name && x.Value == y.Value && x.Quoted == y.Quoted &&
+			x.Domain == y.Domain && x.Path == y.Path && x.SameSite == y.SameSite &&
+			x.Secure == y.Secure && x.HttpOnly == y.HttpOnly && x.Persistent == y.Persistent &&
+			x.Expires.Equal(y.Expires) && x.HostOnly == y.HostOnly &&
+			x.Creation.Equal(y.Creation) && x.LastAccess.Equal(y.LastAccess)
+	})
+}
+
+func TestSaveLoadNetscape(t *testing.T) {
+	jar := newTestJar()
+	setTestCookies(t, jar, persistTestCookies)
+	var buf bytes.Buffer
+	if err := jar.SaveNetscape(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, line := range []string{
+		"# Netscape HTTP Cookie File\n",
+		"\nwww.host.test\tFALSE\t/dir\tFALSE\t0\ta\t1\n",
+		"\n#HttpOnly_.host.test\tTRUE\t/\tTRUE\t",
+		"\n.google.com\tTRUE\t/\tFALSE\t0\tc\t3\n",
+		"\nwww.google.com\tFALSE\t/\tFALSE\t4102444800\td\t4\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("SaveNetscape output does not contain %q:\n%s", line, out)
+		}
+	}
+
+	jar2 := newTestJar()
+	if err := jar2.LoadNetscape(strings.NewReader(out)); err != nil {
+		t.Fatal(err)
+	}
+	want := cookieStrings(t, jar, persistTestURLs...)
+	if got := cookieStrings(t, jar2, persistTestURLs...); !slices.Equal(got, want) {
+		t.Errorf("after SaveNetscape and LoadNetscape:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestLoadNetscape(t *testing.T) {
+	const input = "# Netscape HTTP Cookie File\r\n" +
+		"# comment\n" +
+		"\n" +
+		".example.com\tTRUE\t/\tFALSE\t0\tsession\tv\r\n" +
+		"example.com\tFALSE\t/\tFALSE\t1\texpired\tv\n" +
+		"#HttpOnly_example.com\tFALSE\t/p\tTRUE\t4102444800\th\tv\n" +
+		".co.uk\tTRUE\t/\tFALSE\t0\tpsl\tv\n"
+	jar := newTestJar()
+	err := jar.LoadNetscape(strings.NewReader(input))
+	if err == nil || !strings.Contains(err.Error(), `"psl"`) {
+		t.Errorf("LoadNetscape error = %v, want error for cookie psl", err)
+	}
+	var got []string
+	for e := range jar.All() {
+		got = append(got, e.Name)
+		if e.Name == "h" && (!e.HttpOnly || !e.Secure || !e.Persistent || e.Path != "/p" || !e.HostOnly) {
+			t.Errorf("got entry %+v", e)
+		}
+		if e.Name == "session" && (e.Persistent || e.HostOnly || e.Domain != "example.com") {
+			t.Errorf("got entry %+v", e)
+		}
+	}
+	if want := []string{"session", "h"}; !slices.Equal(got, want) {
+		t.Errorf("loaded cookies %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{
+		"example.com\tFALSE\t/\tFALSE\t0\tname\n",
+		"example.com\tfalse\t/\tFALSE\t0\tname\tv\n",
+		"example.com\tFALSE\t/\tFALSE\tnever\tname\tv\n",
+	} {
+		if err := newTestJar().LoadNetscape(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadNetscape(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestAllExpired(t *testing.T) {
+	jar := newTestJar()
+	now := time.Now()
+	err := jar.add([]Entry{
+		{Name: "live", Domain: "example.com", Persistent: true, Expires: now.Add(time.Hour)},
+		{Name: "dead", Domain: "example.com", Persistent: true, Expires: now.Add(time.Millisecond)},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	var got []string
+	for e := range jar.All() {
+		got = append(got, e.Name)
+	}
+	if !slices.Equal(got, []string{"live"}) {
+		t.Errorf("All() = %q, want [live]", got)
+	}
+}
+
+func TestRemoveDomain(t *testing.T) {
+	jar := newTestJar()
+	setTestCookies(t, jar, persistTestCookies)
+	if n := jar.RemoveDomain("www.host.test"); n != 2 {
+		t.Errorf("RemoveDomain(www.host.test) = %d, want 2", n)
+	}
+	if n := jar.RemoveDomain(".GOOGLE.com"); n != 2 {
+		t.Errorf("RemoveDomain(.GOOGLE.com) = %d, want 2", n)
+	}
+	var got []string
+	for e := range jar.All() {
+		got = append(got, e.Domain+":"+e.Name)
+	}
+	if want := []string{"host.test:b", "192.168.0.10:ip"}; !slices.Equal(got, want) {
+		t.Errorf("after RemoveDomain: %q, want %q", got, want)
+	}
+	if n := jar.RemoveDomain("host.test"); n != 1 {
+		t.Errorf("RemoveDomain(host.test) = %d, want 1", n)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -195,6 +196,23 @@ func ExampleNotFoundHandler() {
 	log.Fatal(http.ListenAndServe(":8080", mux))
 }
 
+func ExampleAccessLogEntry() {
+	srv := http.Server{
+		Addr: ":8080",
+		AccessLog: func(e *http.AccessLogEntry) {
+			r := e.Request
+			slog.InfoContext(r.Context(), "http request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", e.StatusCode,
+				"size", e.Written,
+				"duration", e.Duration,
+			)
+		},
+	}
+	log.Fatal(srv.ListenAndServe())
+}
+
 func ExampleProtocols_http1() {
 	srv := http.Server{
 		Addr: ":8443",
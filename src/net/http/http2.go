@@ -183,6 +183,28 @@ func (w http2ResponseWriter) Push(target string, opts *PushOptions) error {
 	return err
 }
 
+// http2ResponseStats returns the status code and number of body bytes
+// written to w, if w is an HTTP/2 ResponseWriter.
+func http2ResponseStats(w ResponseWriter) (status int, written int64, ok bool) {
+	h2w, ok := w.(http2ResponseWriter)
+	if !ok {
+		return 0, 0, false
+	}
+	status, written = http2.ResponseStats(h2w.ResponseWriter)
+	return status, written, true
+}
+
+// http2IsRetryableError reports whether err is an HTTP/2 error after
+// which a request may succeed if sent again: the server refused the
+// stream, or closed the connection with GOAWAY.
+func http2IsRetryableError(err error) bool {
+	if _, ok := errors.AsType[http2.GoAwayError](err); ok {
+		return true
+	}
+	se, ok := errors.AsType[http2.StreamError](err)
+	return ok && se.Code == http2.ErrCodeRefusedStream
+}
+
 type http2ServerConfig struct {
 	s *Server
 }
@@ -209,7 +231,13 @@ func (s http2ServerConfig) IdleTimeout() time.Duration {
 }
 
 func (s http2ServerConfig) HTTP2Config() http2.Config {
-	return mergeHTTP2Config(s.s.HTTP2, s.s.h2Config)
+	c := mergeHTTP2Config(s.s.HTTP2, s.s.h2Config)
+	if l := s.s.Limits; l != nil && l.MaxConcurrentStreams > 0 {
+		if c.MaxConcurrentStreams == 0 || c.MaxConcurrentStreams > l.MaxConcurrentStreams {
+			c.MaxConcurrentStreams = l.MaxConcurrentStreams
+		}
+	}
+	return c
 }
 
 // http2ExternalServerConfig is an HTTP/2 configuration provided by x/net/http2.
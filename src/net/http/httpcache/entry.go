@@ -0,0 +1,525 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// An entry is a stored response.
+type entry struct {
+	key          string
+	requestTime  time.Time   // when the request that produced the response was sent
+	responseTime time.Time   // when the response was received
+	vary         http.Header // request header fields named by the response's Vary field
+
+	status     string
+	statusCode int
+	proto      string
+	header     http.Header
+	cc         cacheControl // parsed from header
+	body       []byte
+}
+
+// heuristicallyCacheable lists the status codes that are cacheable by
+// default, as defined by RFC 9110, Section 15.1, except for 206 Partial
+// Content, which a Transport does not store.
+var heuristicallyCacheable = map[int]bool{
+	200: true, 203: true, 204: true, 300: true, 301: true, 308: true,
+	404: true, 405: true, 410: true, 414: true, 501: true,
+}
+
+// newEntry returns an entry for resp, the response to req,
+// or nil if resp may not be stored.
+// The body of the entry is filled in when it has been read.
+func (t *Transport) newEntry(req *http.Request, resp *http.Response, requestTime, responseTime time.Time) *entry {
+	code := resp.StatusCode
+	if code < 200 || code == http.StatusPartialContent || code == http.StatusNotModified {
+		return nil
+	}
+	if resp.ContentLength > t.maxBodyBytes() {
+		return nil
+	}
+	cc := parseCacheControl(resp.Header["Cache-Control"])
+	if cc.has("no-store") || t.Shared && cc.has("private") && cc["private"] == "" {
+		return nil
+	}
+	if t.Shared && req.Header.Get("Authorization") != "" &&
+		!cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return nil
+	}
+	varyFields, ok := parseVary(resp.Header)
+	if !ok {
+		return nil
+	}
+
+	e := &entry{
+		key:          cacheKey(req.URL),
+		requestTime:  requestTime,
+		responseTime: responseTime,
+		vary:         make(http.Header),
+		status:       resp.Status,
+		statusCode:   code,
+		proto:        resp.Proto,
+		header:       resp.Header.Clone(),
+		cc:           cc,
+	}
+	for _, name := range varyFields {
+		if v, ok := req.Header[name]; ok {
+			e.vary[name] = append([]string(nil), v...)
+		}
+	}
+	removeUnstorableFields(e.header, cc, t.Shared)
+
+	explicit := cc.has("max-age") || (t.Shared && cc.has("s-maxage")) || e.header.Get("Expires") != ""
+	if !explicit && !cc.has("public") && !heuristicallyCacheable[code] {
+		return nil
+	}
+	if e.lifetime(t.Shared) <= 0 && e.header.Get("Etag") == "" && e.header.Get("Last-Modified") == "" {
+		// The response would always be stale and cannot be validated.
+		return nil
+	}
+	return e
+}
+
+// parseVary returns the canonical names of the fields listed in the
+// Vary header field of h. It reports false if the list contains "*".
+func parseVary(h http.Header) (names []string, ok bool) {
+	for _, v := range h["Vary"] {
+		for name := range strings.SplitSeq(v, ",") {
+			name = textproto.TrimString(name)
+			switch name {
+			case "":
+			case "*":
+				return nil, false
+			default:
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return names, true
+}
+
+// removeUnstorableFields removes the fields a cache must not store,
+// as described in RFC 9111, Section 3.1.
+func removeUnstorableFields(h http.Header, cc cacheControl, shared bool) {
+	for _, v := range h["Connection"] {
+		for name := range strings.SplitSeq(v, ",") {
+			h.Del(textproto.TrimString(name))
+		}
+	}
+	for _, name := range []string{
+		"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Transfer-Encoding",
+		"Upgrade", "Proxy-Authenticate", "Proxy-Authentication-Info",
+	} {
+		h.Del(name)
+	}
+	// Fields named by the qualified forms of no-cache, and of private
+	// in a shared cache, may not be used without revalidation.
+	// Not storing them is simplest.
+	qualified := []string{cc["no-cache"]}
+	if shared {
+		qualified = append(qualified, cc["private"])
+	}
+	for _, list := range qualified {
+		for name := range strings.SplitSeq(list, ",") {
+			if name = textproto.TrimString(name); name != "" {
+				h.Del(name)
+			}
+		}
+	}
+}
+
+// matches reports whether e may be used to answer req,
+// judging by the fields named by its Vary header field.
+func (e *entry) matches(req *http.Request) bool {
+	names, ok := parseVary(e.header)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		if fieldValue(e.vary[name]) != fieldValue(req.Header[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue returns the combined value of a field with the given
+// field lines, with whitespace around list elements normalized.
+func fieldValue(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		for elem := range strings.SplitSeq(line, ",") {
+			if b.Len() > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(textproto.TrimString(elem))
+		}
+	}
+	return b.String()
+}
+
+// age returns the current age of e, as defined by
+// RFC 9111, Section 4.2.3.
+func (e *entry) age(now time.Time) time.Duration {
+	date, err := http.ParseTime(e.header.Get("Date"))
+	if err != nil {
+		date = e.responseTime
+	}
+	apparentAge := max(0, e.responseTime.Sub(date))
+	var ageValue time.Duration
+	if secs, err := strconv.ParseInt(e.header.Get("Age"), 10, 64); err == nil && secs > 0 {
+		ageValue = time.Duration(min(secs, maxDeltaSeconds)) * time.Second
+	}
+	responseDelay := e.responseTime.Sub(e.requestTime)
+	correctedInitialAge := max(apparentAge, ageValue+responseDelay)
+	return correctedInitialAge + now.Sub(e.responseTime)
+}
+
+// lifetime returns the freshness lifetime of e, as defined by
+// RFC 9111, Section 4.2.1.
+func (e *entry) lifetime(shared bool) time.Duration {
+	if shared {
+		if d, ok := e.cc.seconds("s-maxage"); ok {
+			return d
+		}
+	}
+	if d, ok := e.cc.seconds("max-age"); ok {
+		return d
+	}
+	date, err := http.ParseTime(e.header.Get("Date"))
+	if err != nil {
+		date = e.responseTime
+	}
+	if v, ok := e.header["Expires"]; ok {
+		expires, err := http.ParseTime(v[0])
+		if err != nil {
+			// An invalid date means the response has already expired.
+			return 0
+		}
+		return max(0, expires.Sub(date))
+	}
+	if !heuristicallyCacheable[e.statusCode] && !e.cc.has("public") {
+		return 0
+	}
+	// Use a tenth of the time since the resource was last modified,
+	// as suggested by RFC 9111, Section 4.2.2.
+	lastModified, err := http.ParseTime(e.header.Get("Last-Modified"))
+	if err != nil || !lastModified.Before(date) {
+		return 0
+	}
+	return date.Sub(lastModified) / 10
+}
+
+// noCache reports whether e must be validated before each use.
+// The qualified form of the no-cache directive only applies to the
+// listed fields, which are not stored.
+func (e *entry) noCache() bool {
+	v, ok := e.cc["no-cache"]
+	return ok && v == ""
+}
+
+// mustRevalidate reports whether e must not be used once it is stale.
+func (e *entry) mustRevalidate(shared bool) bool {
+	return e.cc.has("must-revalidate") ||
+		shared && (e.cc.has("proxy-revalidate") || e.cc.has("s-maxage"))
+}
+
+// satisfies reports whether e may be used to answer a request with the
+// given Cache-Control directives without validation.
+func (e *entry) satisfies(reqCC cacheControl, age, lifetime time.Duration, shared bool) bool {
+	if reqCC.has("no-cache") || e.noCache() {
+		return false
+	}
+	if maxAge, ok := reqCC.seconds("max-age"); ok && age > maxAge {
+		return false
+	}
+	if minFresh, ok := reqCC.seconds("min-fresh"); ok && lifetime-age < minFresh {
+		return false
+	}
+	if age < lifetime {
+		return true
+	}
+	if e.mustRevalidate(shared) {
+		return false
+	}
+	if v, ok := reqCC["max-stale"]; ok {
+		if v == "" {
+			return true
+		}
+		maxStale, _ := reqCC.seconds("max-stale")
+		return age-lifetime <= maxStale
+	}
+	return false
+}
+
+// staleWhileRevalidate reports whether the stale entry e may be used to
+// answer a request while it is validated in the background.
+func (e *entry) staleWhileRevalidate(reqCC cacheControl, age, lifetime time.Duration, shared bool) bool {
+	if reqCC.has("no-cache") || reqCC.has("max-age") || reqCC.has("min-fresh") ||
+		e.noCache() || e.mustRevalidate(shared) {
+		return false
+	}
+	limit, ok := e.cc.seconds("stale-while-revalidate")
+	return ok && age-lifetime <= limit
+}
+
+// staleIfError reports whether the stale entry e may be used to answer
+// a request when validating it fails.
+func (e *entry) staleIfError(reqCC cacheControl, age, lifetime time.Duration, shared bool) bool {
+	if age < lifetime || e.mustRevalidate(shared) {
+		return false
+	}
+	for _, cc := range []cacheControl{reqCC, e.cc} {
+		if limit, ok := cc.seconds("stale-if-error"); ok && age-lifetime <= limit {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionalRequest returns a request validating e,
+// or req if e has no validators.
+func (e *entry) conditionalRequest(req *http.Request) *http.Request {
+	etag := e.header.Get("Etag")
+	lastModified := e.header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return req
+	}
+	r := req.Clone(req.Context())
+	if etag != "" {
+		r.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		r.Header.Set("If-Modified-Since", lastModified)
+	}
+	return r
+}
+
+// update freshens e with the header fields of a 304 Not Modified
+// response, as described in RFC 9111, Section 3.2.
+func (e *entry) update(h http.Header, requestTime, responseTime time.Time) {
+	h = h.Clone()
+	cc := parseCacheControl(h["Cache-Control"])
+	removeUnstorableFields(h, cc, false)
+	delete(h, "Content-Length")
+	for name, v := range h {
+		e.header[name] = v
+	}
+	e.cc = parseCacheControl(e.header["Cache-Control"])
+	e.requestTime = requestTime
+	e.responseTime = responseTime
+}
+
+// response returns a response to req from e.
+func (e *entry) response(req *http.Request, age time.Duration, cacheStatus string) *http.Response {
+	resp := &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		Proto:         e.proto,
+		Header:        e.header.Clone(),
+		ContentLength: int64(len(e.body)),
+		Body:          http.NoBody,
+		Request:       req,
+	}
+	resp.ProtoMajor, resp.ProtoMinor, _ = http.ParseHTTPVersion(e.proto)
+	if len(e.body) > 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(e.body))
+	}
+	resp.Header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	resp.Header.Add("Cache-Status", cacheStatus)
+	return resp
+}
+
+// entryVersion is the first field of an encoded entry.
+const entryVersion = "httpcache1"
+
+var errMalformedEntry = errors.New("httpcache: malformed cache entry")
+
+// encode serializes e.
+//
+// The encoding is a line holding the version, the request and response
+// times, and the protocol version of the response, followed by the
+// request header fields named by the Vary field in the form written by
+// [http.Header.Write] and an empty line, followed by the response in the
+// form written by [http.Response.Write].
+func (e *entry) encode() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(entryVersion)
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(e.requestTime.UnixNano(), 10))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(e.responseTime.UnixNano(), 10))
+	b.WriteByte(' ')
+	b.WriteString(e.proto)
+	b.WriteString("\r\n")
+	if err := e.vary.Write(&b); err != nil {
+		return nil, err
+	}
+	b.WriteString("\r\n")
+	resp := &http.Response{
+		Status:        e.status,
+		StatusCode:    e.statusCode,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header,
+		ContentLength: int64(len(e.body)),
+	}
+	if len(e.body) > 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(e.body))
+	}
+	if err := resp.Write(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// decodeEntry parses an entry encoded by encode.
+func decodeEntry(key string, data []byte) (*entry, error) {
+	br := bufio.NewReader(bytes.NewReader(data))
+	tp := textproto.NewReader(br)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, errMalformedEntry
+	}
+	fields := strings.Split(line, " ")
+	if len(fields) != 4 || fields[0] != entryVersion {
+		return nil, errMalformedEntry
+	}
+	requestTime, err1 := strconv.ParseInt(fields[1], 10, 64)
+	responseTime, err2 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return nil, errMalformedEntry
+	}
+	vary, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, errMalformedEntry
+	}
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		return nil, errMalformedEntry
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errMalformedEntry
+	}
+	// ReadResponse adds the Content-Length written by Write;
+	// the response returned from the cache sets its own.
+	resp.Header.Del("Content-Length")
+	return &entry{
+		key:          key,
+		requestTime:  time.Unix(0, requestTime),
+		responseTime: time.Unix(0, responseTime),
+		vary:         http.Header(vary),
+		status:       resp.Status,
+		statusCode:   resp.StatusCode,
+		proto:        fields[3],
+		header:       resp.Header,
+		cc:           parseCacheControl(resp.Header["Cache-Control"]),
+		body:         body,
+	}, nil
+}
+
+// maxDeltaSeconds is the largest delta-seconds value used,
+// as recommended by RFC 9111, Section 1.2.2.
+const maxDeltaSeconds = 1 << 31
+
+// cacheControl holds the directives of a Cache-Control header field,
+// keyed by their lower-case names. Directives without an argument
+// have an empty value.
+type cacheControl map[string]string
+
+// parseCacheControl parses the Cache-Control field lines values.
+// If a directive appears more than once, the first is used.
+func parseCacheControl(values []string) cacheControl {
+	var cc cacheControl
+	for _, s := range values {
+		for s != "" {
+			var name, value string
+			i := strings.IndexAny(s, ",=")
+			if i < 0 {
+				name, s = s, ""
+			} else {
+				name = s[:i]
+				sep := s[i]
+				s = s[i+1:]
+				if sep == '=' {
+					value, s = directiveArgument(s)
+				}
+			}
+			name, ok := ascii.ToLower(textproto.TrimString(name))
+			if !ok || !httpguts.ValidHeaderFieldName(name) {
+				continue
+			}
+			if cc == nil {
+				cc = make(cacheControl)
+			}
+			if _, dup := cc[name]; !dup {
+				cc[name] = value
+			}
+		}
+	}
+	return cc
+}
+
+// directiveArgument returns the token or quoted string at the start
+// of s, and the rest of s following the next comma.
+func directiveArgument(s string) (arg, rest string) {
+	s = textproto.TrimString(s)
+	quoted := strings.HasPrefix(s, `"`)
+	if quoted {
+		var b strings.Builder
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			}
+			b.WriteByte(s[i])
+		}
+		arg = b.String()
+		s = s[min(i+1, len(s)):]
+	}
+	before, rest, _ := strings.Cut(s, ",")
+	if !quoted {
+		arg = textproto.TrimString(before)
+	}
+	return arg, rest
+}
+
+func (cc cacheControl) has(name string) bool {
+	_, ok := cc[name]
+	return ok
+}
+
+// seconds returns the delta-seconds argument of the named directive,
+// and reports whether the directive is present. An invalid argument
+// is treated as zero.
+func (cc cacheControl) seconds(name string) (time.Duration, bool) {
+	v, ok := cc[name]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			n = maxDeltaSeconds
+		} else {
+			return 0, true
+		}
+	}
+	return time.Duration(min(n, maxDeltaSeconds)) * time.Second, true
+}
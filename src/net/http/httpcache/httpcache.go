@@ -0,0 +1,426 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httpcache implements an HTTP client cache as described by
+// RFC 9111, in the form of an [http.RoundTripper].
+//
+// A [Transport] stores responses to GET requests in a [Cache] and uses
+// them to answer later requests for the same URL while they are fresh.
+// Stale responses are validated with conditional requests using their
+// ETag and Last-Modified header fields. The Transport supports the
+// Cache-Control directives of RFC 9111 and the stale-while-revalidate
+// and stale-if-error extensions of RFC 5861.
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// ErrCacheMiss is returned by the Get method of a [Cache]
+// when it holds no data for a key.
+var ErrCacheMiss = errors.New("httpcache: cache miss")
+
+// A Cache stores the serialized responses held by a [Transport].
+//
+// Keys are absolute request URLs. The data is opaque to the cache.
+// A Cache may discard data at any time.
+//
+// Implementations must be safe for concurrent use by multiple goroutines.
+// Errors other than [ErrCacheMiss] returned by Get are treated as cache
+// misses; errors returned by Put and Delete are ignored.
+type Cache interface {
+	// Get returns the data stored for key,
+	// or ErrCacheMiss if there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores data for key, replacing any existing data.
+	// The cache may retain data; the caller must not modify it
+	// after Put returns.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Delete removes the data stored for key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// defaultCacheSize is the size of the MemoryCache used
+// by a Transport with no Cache.
+const defaultCacheSize = 32 << 20
+
+// defaultMaxBodyBytes is the default value of Transport.MaxBodyBytes.
+const defaultMaxBodyBytes = 10 << 20
+
+// Transport is an [http.RoundTripper] that caches responses.
+//
+// Transport stores the responses to GET requests that RFC 9111 permits
+// it to store, and answers requests from them while they are fresh.
+// A stale response is validated by sending the request with
+// If-None-Match and If-Modified-Since header fields derived from the
+// stored response; a 304 Not Modified reply refreshes the stored
+// response, which is then used to answer the request.
+//
+// If a stale response carries a stale-while-revalidate directive and
+// the staleness is within the given limit, it is returned immediately
+// and validated in the background. If validating a stale response
+// fails with an error or a 500, 502, 503, or 504 status, and the stored
+// response or the request carries a stale-if-error directive whose limit
+// has not passed, the stale response is returned instead.
+//
+// A response is stored only once its body has been read to the end,
+// and only if the body is no larger than MaxBodyBytes. Transport holds
+// a single response for each URL: if the stored response has a Vary
+// header field and a request's values for the listed fields differ from
+// those of the request that produced it, the request is forwarded and
+// its response replaces the stored one.
+//
+// Requests that have a body, a Range header field, a Cache-Control
+// no-store directive, or a precondition such as If-None-Match are
+// forwarded without using the cache. A successful response to a request
+// with an unsafe method such as POST invalidates the stored responses
+// for the request URL and for the URLs in its Location and
+// Content-Location header fields, if they have the same origin.
+//
+// Each response returned by Transport for a GET request has a
+// Cache-Status header field, as defined by RFC 9211, describing how the
+// cache handled it. Responses from the cache also have an Age header
+// field.
+type Transport struct {
+	// Transport is the RoundTripper used to make requests.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// Cache stores responses.
+	// If nil, a MemoryCache holding up to 32 MiB is used.
+	Cache Cache
+
+	// Shared configures the Transport to behave as a shared cache,
+	// such as one in a proxy serving several users, rather than as a
+	// private cache. A shared cache does not store responses with a
+	// Cache-Control private directive, or responses to requests with an
+	// Authorization header field unless the response explicitly permits
+	// it, and it uses the s-maxage and proxy-revalidate directives.
+	Shared bool
+
+	// MaxBodyBytes is the size in bytes of the largest response body
+	// stored in the cache. If zero, 10 MiB is used.
+	MaxBodyBytes int64
+
+	cacheOnce    sync.Once
+	defaultCache *MemoryCache
+
+	mu           sync.Mutex
+	revalidating map[string]bool // keys being revalidated in the background
+}
+
+func (t *Transport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) cache() Cache {
+	if t.Cache != nil {
+		return t.Cache
+	}
+	t.cacheOnce.Do(func() {
+		t.defaultCache = NewMemoryCache(defaultCacheSize)
+	})
+	return t.defaultCache
+}
+
+func (t *Transport) maxBodyBytes() int64 {
+	if t.MaxBodyBytes > 0 {
+		return t.MaxBodyBytes
+	}
+	return defaultMaxBodyBytes
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "" && req.Method != "GET" {
+		resp, err := t.transport().RoundTrip(req)
+		if err == nil && !isSafe(req.Method) && resp.StatusCode >= 200 && resp.StatusCode < 400 {
+			t.invalidate(req, resp)
+		}
+		return resp, err
+	}
+
+	reqCC := parseCacheControl(req.Header["Cache-Control"])
+	if reqCC == nil && httpguts.HeaderValuesContainsToken(req.Header["Pragma"], "no-cache") {
+		reqCC = cacheControl{"no-cache": ""}
+	}
+	if (req.Body != nil && req.Body != http.NoBody) ||
+		req.Header.Get("Range") != "" ||
+		reqCC.has("no-store") ||
+		hasPreconditions(req.Header) {
+		return t.fetch(req, reqCC, nil, "bypass")
+	}
+
+	e, fwd := t.lookup(req)
+	if e == nil {
+		if reqCC.has("only-if-cached") {
+			return gatewayTimeout(req), nil
+		}
+		return t.fetch(req, reqCC, nil, fwd)
+	}
+
+	now := time.Now()
+	age := e.age(now)
+	lifetime := e.lifetime(t.Shared)
+	switch {
+	case e.satisfies(reqCC, age, lifetime, t.Shared):
+		return e.response(req, age, hitStatus(lifetime-age)), nil
+	case e.staleWhileRevalidate(reqCC, age, lifetime, t.Shared):
+		resp := e.response(req, age, hitStatus(lifetime-age))
+		t.revalidate(req, reqCC, e)
+		return resp, nil
+	case reqCC.has("only-if-cached"):
+		return gatewayTimeout(req), nil
+	}
+	fwd = "stale"
+	if age < lifetime && !e.noCache() {
+		fwd = "request"
+	}
+	return t.fetch(req, reqCC, e, fwd)
+}
+
+// lookup returns the stored response for req, if it can be used.
+// Otherwise it returns nil and the reason for forwarding req,
+// in the form of an RFC 9211 fwd parameter.
+func (t *Transport) lookup(req *http.Request) (*entry, string) {
+	ctx := req.Context()
+	key := cacheKey(req.URL)
+	data, err := t.cache().Get(ctx, key)
+	if err != nil {
+		return nil, "uri-miss"
+	}
+	e, err := decodeEntry(key, data)
+	if err != nil {
+		t.cache().Delete(ctx, key)
+		return nil, "uri-miss"
+	}
+	if !e.matches(req) {
+		return nil, "vary-miss"
+	}
+	return e, ""
+}
+
+// fetch forwards req, validating the stored response e if it is not nil,
+// and stores the response if permitted. The fwd parameter is the reason
+// for forwarding the request reported in the Cache-Status header field.
+func (t *Transport) fetch(req *http.Request, reqCC cacheControl, e *entry, fwd string) (*http.Response, error) {
+	outreq := req
+	if e != nil {
+		outreq = e.conditionalRequest(req)
+	}
+	requestTime := time.Now()
+	resp, err := t.transport().RoundTrip(outreq)
+	responseTime := time.Now()
+
+	if e != nil && (err != nil || isServerError(resp.StatusCode)) {
+		age := e.age(responseTime)
+		lifetime := e.lifetime(t.Shared)
+		if e.staleIfError(reqCC, age, lifetime, t.Shared) {
+			status := cacheStatusPrefix + "fwd=" + fwd
+			if resp != nil {
+				status += "; fwd-status=" + strconv.Itoa(resp.StatusCode)
+				discard(resp)
+			}
+			return e.response(req, age, status), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := cacheStatusPrefix + "fwd=" + fwd + "; fwd-status=" + strconv.Itoa(resp.StatusCode)
+	if e != nil && outreq != req && resp.StatusCode == http.StatusNotModified {
+		discard(resp)
+		e.update(resp.Header, requestTime, responseTime)
+		t.put(req.Context(), e)
+		return e.response(req, e.age(responseTime), status), nil
+	}
+	if fwd != "bypass" && !reqCC.has("no-store") {
+		if ne := t.newEntry(req, resp, requestTime, responseTime); ne != nil {
+			ctx := context.WithoutCancel(req.Context())
+			resp.Body = &storingReader{
+				body: resp.Body,
+				max:  t.maxBodyBytes(),
+				done: func(body []byte) {
+					ne.body = body
+					t.put(ctx, ne)
+				},
+			}
+			status += "; stored"
+		}
+	}
+	resp.Header.Add("Cache-Status", status)
+	return resp, nil
+}
+
+// revalidate starts validating the stored response e in the background,
+// unless another request is already doing so.
+func (t *Transport) revalidate(req *http.Request, reqCC cacheControl, e *entry) {
+	t.mu.Lock()
+	if t.revalidating[e.key] {
+		t.mu.Unlock()
+		return
+	}
+	if t.revalidating == nil {
+		t.revalidating = make(map[string]bool)
+	}
+	t.revalidating[e.key] = true
+	t.mu.Unlock()
+
+	r := req.Clone(context.WithoutCancel(req.Context()))
+	go func() {
+		defer func() {
+			t.mu.Lock()
+			delete(t.revalidating, e.key)
+			t.mu.Unlock()
+		}()
+		resp, err := t.fetch(r, reqCC, e, "stale")
+		if err != nil {
+			return
+		}
+		// Reading the body to the end stores the response.
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+}
+
+func (t *Transport) put(ctx context.Context, e *entry) {
+	data, err := e.encode()
+	if err != nil {
+		return
+	}
+	t.cache().Put(ctx, e.key, data)
+}
+
+// invalidate removes the responses stored for the target of req and
+// the URLs in the Location and Content-Location fields of resp,
+// as described in RFC 9111, Section 4.4.
+func (t *Transport) invalidate(req *http.Request, resp *http.Response) {
+	ctx := req.Context()
+	c := t.cache()
+	c.Delete(ctx, cacheKey(req.URL))
+	for _, name := range []string{"Location", "Content-Location"} {
+		v := resp.Header.Get(name)
+		if v == "" {
+			continue
+		}
+		u, err := req.URL.Parse(v)
+		if err != nil || u.Scheme != req.URL.Scheme || u.Host != req.URL.Host {
+			continue
+		}
+		c.Delete(ctx, cacheKey(u))
+	}
+}
+
+// cacheKey returns the key under which the response for u is stored.
+func cacheKey(u *url.URL) string {
+	u2 := *u
+	u2.Fragment = ""
+	u2.RawFragment = ""
+	return u2.String()
+}
+
+// cacheStatusPrefix begins the Cache-Status header field value
+// added by a Transport, identifying the cache.
+const cacheStatusPrefix = "go; "
+
+// hitStatus returns the Cache-Status value of a response served from
+// the cache with the given remaining freshness, which is negative if
+// the response is stale.
+func hitStatus(ttl time.Duration) string {
+	return cacheStatusPrefix + "hit; ttl=" + strconv.FormatInt(int64(ttl/time.Second), 10)
+}
+
+// gatewayTimeout returns the response to a request with an
+// only-if-cached directive that cannot be answered from the cache.
+func gatewayTimeout(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "504 " + http.StatusText(http.StatusGatewayTimeout),
+		StatusCode: http.StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Cache-Status": {cacheStatusPrefix + "fwd=miss"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+}
+
+func isSafe(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return true
+	}
+	return false
+}
+
+func isServerError(code int) bool {
+	switch code {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func hasPreconditions(h http.Header) bool {
+	for _, name := range []string{"If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since", "If-Range"} {
+		if _, ok := h[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// discard reads some of the body of resp, so that the connection
+// can be reused, and closes it.
+func discard(resp *http.Response) {
+	io.CopyN(io.Discard, resp.Body, 4<<10)
+	resp.Body.Close()
+}
+
+// A storingReader is the body of a response that may be stored.
+// It keeps a copy of the body and calls done with it at EOF.
+type storingReader struct {
+	body io.ReadCloser
+	max  int64
+	buf  []byte
+	done func(body []byte) // nil once called or if the body is too large
+}
+
+func (r *storingReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if r.done != nil {
+		if int64(len(r.buf)+n) > r.max {
+			r.done = nil
+			r.buf = nil
+		} else {
+			r.buf = append(r.buf, p[:n]...)
+		}
+	}
+	if err == io.EOF && r.done != nil {
+		r.done(r.buf)
+		r.done = nil
+		r.buf = nil
+	}
+	return n, err
+}
+
+func (r *storingReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,520 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httpcache"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// An origin is a RoundTripper that serves requests with a handler,
+// recording the requests it receives.
+type origin struct {
+	handler http.HandlerFunc
+
+	mu       sync.Mutex
+	requests []*http.Request
+	err      error // if non-nil, returned by RoundTrip
+}
+
+func (o *origin) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.mu.Lock()
+	o.requests = append(o.requests, req)
+	err := o.err
+	o.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	o.handler(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+func (o *origin) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.requests)
+}
+
+func (o *origin) last() *http.Request {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.requests[len(o.requests)-1]
+}
+
+type result struct {
+	status      int
+	body        string
+	cacheStatus string
+	age         string
+}
+
+func get(t *testing.T, rt http.RoundTripper, url string, header ...string) result {
+	t.Helper()
+	return do(t, rt, "GET", url, header...)
+}
+
+func do(t *testing.T, rt http.RoundTripper, method, url string, header ...string) result {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Add(header[i], header[i+1])
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: reading body: %v", method, url, err)
+	}
+	return result{
+		status:      resp.StatusCode,
+		body:        string(body),
+		cacheStatus: resp.Header.Get("Cache-Status"),
+		age:         resp.Header.Get("Age"),
+	}
+}
+
+func TestFreshnessAndValidation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Etag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			io.WriteString(w, "hello")
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		const url = "http://example.com/a"
+
+		r := get(t, tr, url)
+		if r.body != "hello" || r.cacheStatus != "go; fwd=uri-miss; fwd-status=200; stored" {
+			t.Errorf("first request: %+v", r)
+		}
+
+		time.Sleep(10 * time.Second)
+		r = get(t, tr, url)
+		if r.body != "hello" || r.age != "10" || r.cacheStatus != "go; hit; ttl=50" {
+			t.Errorf("fresh request: %+v", r)
+		}
+		if n := o.count(); n != 1 {
+			t.Errorf("origin received %d requests, want 1", n)
+		}
+
+		time.Sleep(60 * time.Second)
+		r = get(t, tr, url)
+		if r.body != "hello" || r.age != "0" || r.cacheStatus != "go; fwd=stale; fwd-status=304" {
+			t.Errorf("stale request: %+v", r)
+		}
+		if n := o.count(); n != 2 {
+			t.Fatalf("origin received %d requests, want 2", n)
+		}
+		if got := o.last().Header.Get("If-None-Match"); got != `"v1"` {
+			t.Errorf("validation request If-None-Match = %q, want %q", got, `"v1"`)
+		}
+
+		// The 304 response freshened the stored response.
+		r = get(t, tr, url)
+		if r.body != "hello" || r.cacheStatus != "go; hit; ttl=60" || o.count() != 2 {
+			t.Errorf("request after validation: %+v after %d origin requests", r, o.count())
+		}
+	})
+}
+
+func TestHeuristicFreshness(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			lastModified := time.Now().Add(-100 * time.Second)
+			w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+			if ims, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !lastModified.After(ims) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			io.WriteString(w, "hello")
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		const url = "http://example.com/a"
+
+		get(t, tr, url)
+		time.Sleep(9 * time.Second)
+		if r := get(t, tr, url); r.cacheStatus != "go; hit; ttl=1" {
+			t.Errorf("request within heuristic lifetime: %+v", r)
+		}
+		time.Sleep(time.Second)
+		r := get(t, tr, url)
+		if r.body != "hello" || !strings.HasPrefix(r.cacheStatus, "go; fwd=stale") {
+			t.Errorf("request after heuristic lifetime: %+v", r)
+		}
+		if o.count() != 2 || o.last().Header.Get("If-Modified-Since") == "" {
+			t.Errorf("origin received %d requests, last without If-Modified-Since", o.count())
+		}
+	})
+}
+
+func TestVary(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+			fmt.Fprintf(w, "lang=%s", r.Header.Get("Accept-Language"))
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		const url = "http://example.com/a"
+
+		for _, tt := range []struct {
+			lang        string
+			body        string
+			cacheStatus string
+		}{
+			{"en", "lang=en", "go; fwd=uri-miss; fwd-status=200; stored"},
+			{"en", "lang=en", "go; hit; ttl=60"},
+			{"fr", "lang=fr", "go; fwd=vary-miss; fwd-status=200; stored"},
+			{"fr", "lang=fr", "go; hit; ttl=60"},
+			{"en", "lang=en", "go; fwd=vary-miss; fwd-status=200; stored"},
+		} {
+			r := get(t, tr, url, "Accept-Language", tt.lang)
+			if r.body != tt.body || r.cacheStatus != tt.cacheStatus {
+				t.Errorf("Accept-Language %s: got %+v, want body %q, Cache-Status %q", tt.lang, r, tt.body, tt.cacheStatus)
+			}
+		}
+
+		o.handler = func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "*")
+		}
+		get(t, tr, "http://example.com/star")
+		if r := get(t, tr, "http://example.com/star"); r.cacheStatus != "go; fwd=uri-miss; fwd-status=200" {
+			t.Errorf("Vary: * response was stored: %+v", r)
+		}
+	})
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		version := 1
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=10, stale-while-revalidate=60")
+			fmt.Fprintf(w, "v%d", version)
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		const url = "http://example.com/a"
+
+		get(t, tr, url)
+		version = 2
+		time.Sleep(20 * time.Second)
+		if r := get(t, tr, url); r.body != "v1" || r.cacheStatus != "go; hit; ttl=-10" {
+			t.Errorf("stale request: %+v", r)
+		}
+		synctest.Wait()
+		if n := o.count(); n != 2 {
+			t.Errorf("origin received %d requests, want 2", n)
+		}
+		if r := get(t, tr, url); r.body != "v2" || r.cacheStatus != "go; hit; ttl=10" {
+			t.Errorf("request after revalidation: %+v", r)
+		}
+
+		// Beyond the stale-while-revalidate limit, the request waits.
+		version = 3
+		time.Sleep(100 * time.Second)
+		if r := get(t, tr, url); r.body != "v3" {
+			t.Errorf("request beyond stale-while-revalidate limit: %+v", r)
+		}
+	})
+}
+
+func TestStaleIfError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		status := http.StatusOK
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=10, stale-if-error=60")
+			w.WriteHeader(status)
+			fmt.Fprintf(w, "status %d", status)
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		const url = "http://example.com/a"
+
+		get(t, tr, url)
+		time.Sleep(20 * time.Second)
+		status = http.StatusServiceUnavailable
+		if r := get(t, tr, url); r.status != 200 || r.cacheStatus != "go; fwd=stale; fwd-status=503" {
+			t.Errorf("request with 503 from origin: %+v", r)
+		}
+		o.err = errors.New("connection refused")
+		if r := get(t, tr, url); r.status != 200 || r.cacheStatus != "go; fwd=stale" {
+			t.Errorf("request with error from origin: %+v", r)
+		}
+
+		time.Sleep(60 * time.Second)
+		req, _ := http.NewRequest("GET", url, nil)
+		if _, err := tr.RoundTrip(req); err != o.err {
+			t.Errorf("request beyond stale-if-error limit returned %v, want %v", err, o.err)
+		}
+	})
+}
+
+func TestRequestDirectives(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			io.WriteString(w, "hello")
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		const url = "http://example.com/a"
+
+		if r := get(t, tr, url, "Cache-Control", "only-if-cached"); r.status != http.StatusGatewayTimeout {
+			t.Errorf("only-if-cached with empty cache: %+v", r)
+		}
+		if o.count() != 0 {
+			t.Errorf("only-if-cached request was forwarded")
+		}
+		get(t, tr, url)
+		time.Sleep(30 * time.Second)
+
+		for _, tt := range []struct {
+			header      []string
+			cacheStatus string
+		}{
+			{[]string{"Cache-Control", "only-if-cached"}, "go; hit; ttl=30"},
+			{[]string{"Cache-Control", "max-age=40"}, "go; hit; ttl=30"},
+			{[]string{"Cache-Control", "max-age=20"}, "go; fwd=request; fwd-status=200; stored"},
+			{[]string{"Cache-Control", "min-fresh=61"}, "go; fwd=request; fwd-status=200; stored"},
+			{[]string{"Cache-Control", "no-cache"}, "go; fwd=request; fwd-status=200; stored"},
+			{[]string{"Pragma", "no-cache"}, "go; fwd=request; fwd-status=200; stored"},
+			{[]string{"Cache-Control", "no-store"}, "go; fwd=bypass; fwd-status=200"},
+			{[]string{"Range", "bytes=0-1"}, "go; fwd=bypass; fwd-status=200"},
+			{[]string{"If-None-Match", `"x"`}, "go; fwd=bypass; fwd-status=200"},
+		} {
+			r := get(t, tr, url, tt.header...)
+			if r.cacheStatus != tt.cacheStatus {
+				t.Errorf("%q: Cache-Status = %q, want %q", tt.header, r.cacheStatus, tt.cacheStatus)
+			}
+		}
+
+		time.Sleep(90 * time.Second)
+		if r := get(t, tr, url, "Cache-Control", "max-stale=100"); r.cacheStatus != "go; hit; ttl=-30" {
+			t.Errorf("max-stale=100: %+v", r)
+		}
+		if r := get(t, tr, url, "Cache-Control", "max-stale"); r.cacheStatus != "go; hit; ttl=-30" {
+			t.Errorf("max-stale: %+v", r)
+		}
+		if r := get(t, tr, url, "Cache-Control", "max-stale=10"); r.cacheStatus != "go; fwd=stale; fwd-status=200; stored" {
+			t.Errorf("max-stale=10: %+v", r)
+		}
+	})
+}
+
+func TestStorability(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		shared       bool
+		reqHeader    []string
+		status       int
+		header       []string
+		stored       bool
+		mustValidate bool
+	}{
+		{name: "max-age", header: []string{"Cache-Control", "max-age=60"}, stored: true},
+		{name: "no validators or lifetime", stored: false},
+		{name: "Expires", header: []string{"Expires", "Sat, 01 Jan 2000 01:00:00 GMT"}, stored: true},
+		{name: "invalid Expires", header: []string{"Expires", "0", "Etag", `"x"`}, stored: true, mustValidate: true},
+		{name: "no-store", header: []string{"Cache-Control", "max-age=60, no-store"}, stored: false},
+		{name: "no-cache", header: []string{"Cache-Control", "max-age=60, no-cache", "Etag", `"x"`}, stored: true, mustValidate: true},
+		{name: "qualified no-cache", header: []string{"Cache-Control", `max-age=60, no-cache="Set-Cookie"`}, stored: true},
+		{name: "private", header: []string{"Cache-Control", "max-age=60, private"}, stored: true},
+		{name: "shared private", shared: true, header: []string{"Cache-Control", "max-age=60, private"}, stored: false},
+		{name: "shared s-maxage", shared: true, header: []string{"Cache-Control", "max-age=0, s-maxage=60"}, stored: true},
+		{name: "s-maxage", header: []string{"Cache-Control", "max-age=0, s-maxage=60", "Etag", `"x"`}, stored: true, mustValidate: true},
+		{name: "shared Authorization", shared: true, reqHeader: []string{"Authorization", "x"}, header: []string{"Cache-Control", "max-age=60"}, stored: false},
+		{name: "shared Authorization public", shared: true, reqHeader: []string{"Authorization", "x"}, header: []string{"Cache-Control", "max-age=60, public"}, stored: true},
+		{name: "private Authorization", reqHeader: []string{"Authorization", "x"}, header: []string{"Cache-Control", "max-age=60"}, stored: true},
+		{name: "404", status: 404, header: []string{"Cache-Control", "max-age=60"}, stored: true},
+		{name: "302", status: 302, header: []string{"Location", "/b"}, stored: false},
+		{name: "302 max-age", status: 302, header: []string{"Location", "/b", "Cache-Control", "max-age=60"}, stored: true},
+		{name: "206", status: 206, header: []string{"Cache-Control", "max-age=60"}, stored: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+					for i := 0; i < len(tt.header); i += 2 {
+						w.Header().Set(tt.header[i], tt.header[i+1])
+					}
+					w.WriteHeader(max(tt.status, 200))
+				}}
+				tr := &httpcache.Transport{Transport: o, Shared: tt.shared}
+				const url = "http://example.com/a"
+				r := get(t, tr, url, tt.reqHeader...)
+				if got := strings.HasSuffix(r.cacheStatus, "; stored"); got != tt.stored {
+					t.Errorf("first response Cache-Status = %q, want stored = %v", r.cacheStatus, tt.stored)
+				}
+				r = get(t, tr, url, tt.reqHeader...)
+				wantHit := tt.stored && !tt.mustValidate
+				if got := strings.Contains(r.cacheStatus, "; hit"); got != wantHit {
+					t.Errorf("second response Cache-Status = %q, want hit = %v", r.cacheStatus, wantHit)
+				}
+			})
+		})
+	}
+}
+
+func TestStoredHeader(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", `max-age=60, no-cache="Set-Cookie"`)
+			w.Header().Set("Set-Cookie", "a=b")
+			w.Header().Set("Connection", "X-Hop")
+			w.Header().Set("X-Hop", "1")
+			w.Header().Set("Keep-Alive", "timeout=5")
+			w.Header().Set("X-Kept", "1")
+			io.WriteString(w, "hello")
+		}}
+		tr := &httpcache.Transport{Transport: o}
+		get(t, tr, "http://example.com/")
+		req, _ := http.NewRequest("GET", "http://example.com/", nil)
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		for _, name := range []string{"Set-Cookie", "Connection", "X-Hop", "Keep-Alive"} {
+			if v := resp.Header.Get(name); v != "" {
+				t.Errorf("cached response has %s: %q", name, v)
+			}
+		}
+		if resp.Header.Get("X-Kept") != "1" || resp.ContentLength != 5 || resp.Request != req {
+			t.Errorf("cached response: Header %v, ContentLength %d", resp.Header, resp.ContentLength)
+		}
+	})
+}
+
+func TestBodyNotReadNotStored(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			io.WriteString(w, strings.Repeat("x", 100))
+		}}
+		cache := httpcache.NewMemoryCache(0)
+		tr := &httpcache.Transport{Transport: o, Cache: cache, MaxBodyBytes: 50}
+		get(t, tr, "http://example.com/big")
+		if n := cache.Len(); n != 0 {
+			t.Errorf("body larger than MaxBodyBytes was stored")
+		}
+
+		tr.MaxBodyBytes = 0
+		req, _ := http.NewRequest("GET", "http://example.com/a", nil)
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.CopyN(io.Discard, resp.Body, 10)
+		resp.Body.Close()
+		if n := cache.Len(); n != 0 {
+			t.Errorf("partially read body was stored")
+		}
+	})
+}
+
+func TestInvalidation(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			if r.Method == "POST" {
+				w.Header().Set("Location", "/b")
+				w.Header().Set("Content-Location", "http://other.example/c")
+				w.WriteHeader(http.StatusCreated)
+			}
+		}}
+		cache := httpcache.NewMemoryCache(0)
+		tr := &httpcache.Transport{Transport: o, Cache: cache}
+		for _, url := range []string{"http://example.com/a", "http://example.com/b", "http://other.example/c"} {
+			get(t, tr, url)
+		}
+		if n := cache.Len(); n != 3 {
+			t.Fatalf("cache holds %d responses, want 3", n)
+		}
+		do(t, tr, "POST", "http://example.com/a")
+		for _, tt := range []struct {
+			url string
+			hit bool
+		}{
+			{"http://example.com/a", false},
+			{"http://example.com/b", false},
+			{"http://other.example/c", true},
+		} {
+			r := get(t, tr, tt.url, "Cache-Control", "only-if-cached")
+			if got := r.status == 200; got != tt.hit {
+				t.Errorf("after POST, %s cached = %v, want %v", tt.url, got, tt.hit)
+			}
+		}
+	})
+}
+
+func TestCorruptEntry(t *testing.T) {
+	cache := httpcache.NewMemoryCache(0)
+	ctx := context.Background()
+	const url = "http://example.com/a"
+	cache.Put(ctx, url, []byte("garbage"))
+	o := &origin{handler: func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	}}
+	tr := &httpcache.Transport{Transport: o, Cache: cache}
+	if r := get(t, tr, url); r.body != "hello" {
+		t.Errorf("request with corrupt entry: %+v", r)
+	}
+	if _, err := cache.Get(ctx, url); err != httpcache.ErrCacheMiss {
+		t.Errorf("corrupt entry not deleted: Get returned %v", err)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := httpcache.NewMemoryCache(10)
+	c.Put(ctx, "a", []byte("1234"))
+	c.Put(ctx, "b", []byte("1234"))
+	c.Get(ctx, "a")
+	c.Put(ctx, "c", []byte("1234")) // evicts b, the least recently used
+	c.Put(ctx, "d", []byte("12345678901"))
+	for _, tt := range []struct {
+		key  string
+		want string
+	}{
+		{"a", "1234"},
+		{"b", ""},
+		{"c", "1234"},
+		{"d", ""},
+	} {
+		data, err := c.Get(ctx, tt.key)
+		if tt.want == "" {
+			if err != httpcache.ErrCacheMiss {
+				t.Errorf("Get(%q) = %q, %v; want ErrCacheMiss", tt.key, data, err)
+			}
+		} else if string(data) != tt.want || err != nil {
+			t.Errorf("Get(%q) = %q, %v; want %q", tt.key, data, err, tt.want)
+		}
+	}
+	c.Delete(ctx, "a")
+	if _, err := c.Get(ctx, "a"); err != httpcache.ErrCacheMiss || c.Len() != 1 {
+		t.Errorf("after Delete, Get returned %v, Len = %d", err, c.Len())
+	}
+}
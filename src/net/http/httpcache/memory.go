@@ -0,0 +1,93 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httpcache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// MemoryCache is a [Cache] that holds data in memory. When the total size
+// of the data exceeds its limit, it discards the least recently used data.
+//
+// A MemoryCache is safe for concurrent use by multiple goroutines.
+type MemoryCache struct {
+	maxSize int64
+
+	mu    sync.Mutex
+	size  int64
+	lru   *list.List // of *memoryItem, most recently used first
+	items map[string]*list.Element
+}
+
+type memoryItem struct {
+	key  string
+	data []byte
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxSize bytes of data.
+// If maxSize is zero or negative, the size is unlimited.
+func NewMemoryCache(maxSize int64) *MemoryCache {
+	return &MemoryCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// Get implements [Cache]. The caller must not modify the returned data.
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*memoryItem).data, nil
+}
+
+// Put implements [Cache]. Data larger than the size limit
+// of the cache is not stored.
+func (c *MemoryCache) Put(ctx context.Context, key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(key)
+	if c.maxSize > 0 && int64(len(data)) > c.maxSize {
+		return nil
+	}
+	c.items[key] = c.lru.PushFront(&memoryItem{key: key, data: data})
+	c.size += int64(len(data))
+	for c.maxSize > 0 && c.size > c.maxSize {
+		c.remove(c.lru.Back().Value.(*memoryItem).key)
+	}
+	return nil
+}
+
+// Delete implements [Cache].
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(key)
+	return nil
+}
+
+// Len returns the number of keys in the cache.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+func (c *MemoryCache) remove(key string) {
+	el, ok := c.items[key]
+	if !ok {
+		return
+	}
+	c.lru.Remove(el)
+	delete(c.items, key)
+	c.size -= int64(len(el.Value.(*memoryItem).data))
+}
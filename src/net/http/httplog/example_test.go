@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httplog_test
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httplog"
+	"os"
+)
+
+func ExampleAccessLog() {
+	// Log the first 100 requests in each second,
+	// and every tenth request after that.
+	h := slog.NewSamplingHandler(slog.NewJSONHandler(os.Stderr, nil), &slog.SamplingOptions{
+		First:      100,
+		Thereafter: 10,
+	})
+	srv := &http.Server{
+		Addr: ":8080",
+		AccessLog: httplog.AccessLog(slog.New(h), &httplog.Options{
+			Attrs: func(e *http.AccessLogEntry) []slog.Attr {
+				return []slog.Attr{slog.String("user_agent", e.Request.UserAgent())}
+			},
+		}),
+	}
+	log.Fatal(srv.ListenAndServe())
+}
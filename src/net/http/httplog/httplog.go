@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package httplog writes the access log of an [http.Server] as
+// [log/slog] records.
+//
+// [AccessLog] returns a function for the Server's AccessLog field that
+// logs one record for each request:
+//
+//	srv := &http.Server{
+//		AccessLog: httplog.AccessLog(slog.Default(), nil),
+//	}
+package httplog
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Options are options for [AccessLog].
+type Options struct {
+	// Level is the level of the records. If nil, records are
+	// logged at slog.LevelInfo.
+	Level slog.Leveler
+
+	// Attrs, if non-nil, returns attributes to add to the record of
+	// a request, after the standard ones.
+	Attrs func(*http.AccessLogEntry) []slog.Attr
+
+	// Sample, if non-nil, reports whether to log a request. It may be
+	// used to log only a fraction of the requests, or only those that
+	// failed. To limit the rate of records instead, log to a
+	// [slog.SamplingHandler].
+	Sample func(*http.AccessLogEntry) bool
+}
+
+// AccessLog returns a function for [http.Server.AccessLog] that logs
+// each request to logger as a record with the message "http request",
+// in the context of the request. The record has these attributes:
+//
+//   - "method", "path", "proto" and "remote_addr": the Method,
+//     URL.Path, Proto and RemoteAddr of the request
+//   - "pattern": the pattern of the ServeMux that matched the request,
+//     if any
+//   - "status": the status code of the response
+//   - "size": the number of bytes written in the response body, or on
+//     the connection if it was hijacked
+//   - "duration": how long handling the request took
+//   - "hijacked": true, if the handler hijacked the connection
+//
+// If opts is nil, the default options are used.
+func AccessLog(logger *slog.Logger, opts *Options) func(*http.AccessLogEntry) {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	return func(e *http.AccessLogEntry) {
+		level := slog.LevelInfo
+		if o.Level != nil {
+			level = o.Level.Level()
+		}
+		r := e.Request
+		ctx := r.Context()
+		if !logger.Enabled(ctx, level) || o.Sample != nil && !o.Sample(e) {
+			return
+		}
+		attrs := make([]slog.Attr, 0, 10)
+		attrs = append(attrs,
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+		)
+		if e.Pattern != "" {
+			attrs = append(attrs, slog.String("pattern", e.Pattern))
+		}
+		attrs = append(attrs,
+			slog.Int("status", e.StatusCode),
+			slog.Int64("size", e.Written),
+			slog.Duration("duration", e.Duration),
+			slog.String("proto", r.Proto),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+		if e.Hijacked {
+			attrs = append(attrs, slog.Bool("hijacked", true))
+		}
+		if o.Attrs != nil {
+			attrs = append(attrs, o.Attrs(e)...)
+		}
+		logger.LogAttrs(ctx, level, "http request", attrs...)
+	}
+}
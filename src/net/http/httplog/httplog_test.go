@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httplog_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httplog"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLogger returns a logger writing text records without times
+// to buf.
+func testLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func testEntry(path string, status int) *http.AccessLogEntry {
+	r := httptest.NewRequest("GET", path, nil)
+	return &http.AccessLogEntry{
+		Request:    r,
+		Pattern:    "GET /items/{id}",
+		StatusCode: status,
+		Written:    7,
+		Duration:   3 * time.Millisecond,
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := httplog.AccessLog(testLogger(&buf), nil)
+	log(testEntry("/items/42", http.StatusCreated))
+	const want = `level=INFO msg="http request" method=GET path=/items/42 pattern="GET /items/{id}" status=201 size=7 duration=3ms proto=HTTP/1.1 remote_addr=192.0.2.1:1234` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	e := testEntry("/", 0)
+	e.Pattern = ""
+	e.Hijacked = true
+	log(e)
+	if got := buf.String(); strings.Contains(got, "pattern=") || !strings.Contains(got, " hijacked=true") {
+		t.Errorf("hijacked request without pattern: %s", got)
+	}
+}
+
+func TestAccessLogOptions(t *testing.T) {
+	var buf bytes.Buffer
+	log := httplog.AccessLog(testLogger(&buf), &httplog.Options{
+		Level: slog.LevelDebug,
+		Attrs: func(e *http.AccessLogEntry) []slog.Attr {
+			return []slog.Attr{slog.String("user", e.Request.Header.Get("X-User"))}
+		},
+		Sample: func(e *http.AccessLogEntry) bool {
+			return e.StatusCode >= 500
+		},
+	})
+	log(testEntry("/a", http.StatusOK))
+	e := testEntry("/b", http.StatusInternalServerError)
+	e.Request.Header.Set("X-User", "gopher")
+	log(e)
+	got := buf.String()
+	if strings.Contains(got, "path=/a") {
+		t.Errorf("request not sampled was logged: %s", got)
+	}
+	if !strings.HasPrefix(got, "level=DEBUG ") || !strings.HasSuffix(got, " user=gopher\n") {
+		t.Errorf("got %s, want debug record with user attribute", got)
+	}
+}
+
+// The records are logged by a Server.
+func TestAccessLogServer(t *testing.T) {
+	records := make(chan string, 1)
+	var buf bytes.Buffer
+	logger := testLogger(&buf)
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	}))
+	log := httplog.AccessLog(logger, nil)
+	s.Config.AccessLog = func(e *http.AccessLogEntry) {
+		log(e)
+		records <- buf.String()
+	}
+	s.Start()
+	defer s.Close()
+	res, err := s.Client().Get(s.URL + "/" + url.PathEscape("a b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	if got := <-records; !strings.Contains(got, `path="/a b" status=200 size=5 `) {
+		t.Errorf("got %s, want record of request", got)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// recordFlag is the -httptest.record flag. It is only registered
+// when the flag is given, so that the flag is not listed in the
+// usage of every test binary linking this package.
+var recordFlag bool
+
+func init() {
+	if strSliceContainsPrefix(os.Args, "-httptest.record") || strSliceContainsPrefix(os.Args, "--httptest.record") {
+		flag.BoolVar(&recordFlag, "httptest.record", false, "if true, ReplayTransports record exchanges to their golden files.")
+	}
+}
+
+// ReplayOptions are options for a [ReplayTransport].
+type ReplayOptions struct {
+	// Record controls whether the ReplayTransport records exchanges
+	// instead of replaying them. Recording is also enabled by
+	// passing the -httptest.record flag to the test binary.
+	Record bool
+
+	// Transport is the RoundTripper used to send requests
+	// when recording. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// RedactHeaders lists the header fields of requests and responses
+	// whose values are replaced by "REDACTED" in the golden file.
+	// If nil, the Authorization, Proxy-Authorization, Cookie, and
+	// Set-Cookie fields are redacted.
+	RedactHeaders []string
+
+	// Redact, if non-nil, is called with each recorded exchange before
+	// it is written to the golden file, after RedactHeaders is applied.
+	// It may modify the header, URL, and body of req and resp.
+	Redact func(req *http.Request, resp *http.Response)
+
+	// Match reports whether a request being replayed matches a
+	// recorded request. The bodies of both requests may be read.
+	// If nil, requests match if they have the same method, URL, and body.
+	Match func(recorded, req *http.Request) bool
+}
+
+// A ReplayTransport is an [http.RoundTripper] for testing clients
+// against recorded exchanges with a server.
+//
+// When recording, a ReplayTransport sends each request to the server
+// and returns its response, keeping a copy of both. When the test ends,
+// it writes the exchanges to its golden file, unless the test failed.
+//
+// Otherwise, a ReplayTransport answers each request with the response
+// of the first recorded exchange whose request matches it, as reported
+// by [ReplayOptions.Match], and that has not yet been replayed. If no
+// exchange matches, RoundTrip reports a test error and returns an error.
+//
+// The golden file holds the request and response of each exchange in
+// turn, in the wire format written by [http.Request.WriteProxy] and
+// [http.Response.Write]. It may be edited by hand.
+type ReplayTransport struct {
+	t    testing.TB
+	path string
+	opts ReplayOptions
+
+	mu        sync.Mutex
+	exchanges []*exchange
+}
+
+// An exchange is a recorded request and its response.
+type exchange struct {
+	req      *http.Request
+	reqBody  []byte
+	resp     *http.Response
+	respBody []byte
+	replayed bool
+}
+
+// NewReplayTransport returns a [ReplayTransport] using the golden file
+// at path. When replaying, it calls t.Fatal if the file cannot be read.
+// If opts is nil, the default options are used.
+func NewReplayTransport(t testing.TB, path string, opts *ReplayOptions) *ReplayTransport {
+	t.Helper()
+	rt := &ReplayTransport{t: t, path: path}
+	if opts != nil {
+		rt.opts = *opts
+	}
+	if recordFlag {
+		rt.opts.Record = true
+	}
+	if rt.opts.Record {
+		t.Cleanup(rt.write)
+		return rt
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("httptest: reading golden file: %v (use -httptest.record to create it)", err)
+	}
+	rt.exchanges, err = readExchanges(data)
+	if err != nil {
+		t.Fatalf("httptest: %s: %v", path, err)
+	}
+	return rt
+}
+
+// Recording reports whether rt is recording exchanges.
+func (rt *ReplayTransport) Recording() bool {
+	return rt.opts.Record
+}
+
+// Client returns an HTTP client that sends requests with rt.
+func (rt *ReplayTransport) Client() *http.Client {
+	return &http.Client{Transport: rt}
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (rt *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if rt.opts.Record {
+		return rt.record(req, reqBody)
+	}
+	return rt.replay(req, reqBody)
+}
+
+func (rt *ReplayTransport) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	outreq := req
+	if req.Body != nil {
+		outreq = req.Clone(req.Context())
+		outreq.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	transport := rt.opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(outreq)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	e := &exchange{
+		req:      req.Clone(req.Context()),
+		reqBody:  reqBody,
+		resp:     new(http.Response),
+		respBody: respBody,
+	}
+	*e.resp = *resp
+	e.resp.Header = resp.Header.Clone()
+	e.resp.Request = e.req
+	rt.mu.Lock()
+	rt.exchanges = append(rt.exchanges, e)
+	rt.mu.Unlock()
+	return resp, nil
+}
+
+func (rt *ReplayTransport) replay(req *http.Request, reqBody []byte) (*http.Response, error) {
+	match := rt.opts.Match
+	if match == nil {
+		match = defaultReplayMatch
+	}
+	// Match a copy of req, whose body can be replaced.
+	mreq := req.Clone(req.Context())
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, e := range rt.exchanges {
+		if e.replayed {
+			continue
+		}
+		mreq.Body = io.NopCloser(bytes.NewReader(reqBody))
+		e.req.Body = io.NopCloser(bytes.NewReader(e.reqBody))
+		if !match(e.req, mreq) {
+			continue
+		}
+		e.replayed = true
+		resp := new(http.Response)
+		*resp = *e.resp
+		resp.Header = e.resp.Header.Clone()
+		resp.Body = io.NopCloser(bytes.NewReader(e.respBody))
+		resp.ContentLength = int64(len(e.respBody))
+		resp.Request = req
+		return resp, nil
+	}
+	rt.t.Errorf("httptest: no recorded response for %s %s in %s", req.Method, req.URL, rt.path)
+	return nil, fmt.Errorf("httptest: no recorded response for %s %s", req.Method, req.URL)
+}
+
+// defaultReplayMatch is the default for ReplayOptions.Match.
+func defaultReplayMatch(recorded, req *http.Request) bool {
+	if recorded.Method != req.Method || recorded.URL.String() != req.URL.String() {
+		return false
+	}
+	b1, err1 := io.ReadAll(recorded.Body)
+	b2, err2 := io.ReadAll(req.Body)
+	return err1 == nil && err2 == nil && bytes.Equal(b1, b2)
+}
+
+// write writes the recorded exchanges to the golden file.
+func (rt *ReplayTransport) write() {
+	if rt.t.Failed() {
+		rt.t.Logf("httptest: test failed; not writing golden file %s", rt.path)
+		return
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	var buf bytes.Buffer
+	for _, e := range rt.exchanges {
+		if err := rt.writeExchange(&buf, e); err != nil {
+			rt.t.Errorf("httptest: recording %s %s: %v", e.req.Method, e.req.URL, err)
+			return
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(rt.path), 0o777); err != nil {
+		rt.t.Errorf("httptest: %v", err)
+		return
+	}
+	if err := os.WriteFile(rt.path, buf.Bytes(), 0o666); err != nil {
+		rt.t.Errorf("httptest: %v", err)
+	}
+}
+
+func (rt *ReplayTransport) writeExchange(w io.Writer, e *exchange) error {
+	req := e.req
+	req.Header = req.Header.Clone()
+	req.Body = io.NopCloser(bytes.NewReader(e.reqBody))
+	resp := e.resp
+	resp.Header = resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(e.respBody))
+
+	redact := rt.opts.RedactHeaders
+	if redact == nil {
+		redact = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	}
+	for _, name := range redact {
+		for _, h := range []http.Header{req.Header, resp.Header} {
+			if v := h.Values(name); len(v) > 0 {
+				h.Del(name)
+				for range v {
+					h.Add(name, "REDACTED")
+				}
+			}
+		}
+	}
+	if rt.opts.Redact != nil {
+		rt.opts.Redact(req, resp)
+	}
+
+	reqBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = nil
+	if len(reqBody) > 0 {
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	req.ContentLength = int64(len(reqBody))
+	req.TransferEncoding = nil
+	req.Close = false
+	if err := req.WriteProxy(w); err != nil {
+		return err
+	}
+
+	resp.Body = nil
+	if len(respBody) > 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	}
+	resp.ContentLength = int64(len(respBody))
+	resp.TransferEncoding = nil
+	resp.Close = false
+	resp.Uncompressed = false
+	resp.Trailer = nil
+	return resp.Write(w)
+}
+
+// readExchanges parses the contents of a golden file.
+func readExchanges(data []byte) ([]*exchange, error) {
+	var exchanges []*exchange
+	br := bufio.NewReader(bytes.NewReader(data))
+	for {
+		if _, err := br.Peek(1); err == io.EOF {
+			return exchanges, nil
+		}
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d: reading request: %v", len(exchanges)+1, err)
+		}
+		reqBody, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d: reading request body: %v", len(exchanges)+1, err)
+		}
+		req.RequestURI = ""
+		resp, err := http.ReadResponse(br, req)
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d: reading response: %v", len(exchanges)+1, err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d: reading response body: %v", len(exchanges)+1, err)
+		}
+		exchanges = append(exchanges, &exchange{
+			req:      req,
+			reqBody:  reqBody,
+			resp:     resp,
+			respBody: respBody,
+		})
+	}
+}
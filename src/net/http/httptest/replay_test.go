@@ -0,0 +1,160 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httptest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// errorCapturingTB is a testing.TB that records errors
+// instead of failing the test.
+type errorCapturingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *errorCapturingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestReplayTransport(t *testing.T) {
+	var serverRequests int
+	ts := NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverRequests++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Token", "secret-token")
+		fmt.Fprintf(w, "%s %s %s auth=%t", r.Method, r.URL.Path, body, r.Header.Get("Authorization") != "")
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "exchanges.txt")
+	redact := func(req *http.Request, resp *http.Response) {
+		resp.Header.Set("X-Token", "REDACTED")
+	}
+
+	type exchange struct {
+		method, path, body string
+	}
+	exchanges := []exchange{
+		{"GET", "/a", ""},
+		{"POST", "/b", "one"},
+		{"POST", "/b", "two"},
+		{"GET", "/a", ""},
+	}
+	do := func(t *testing.T, c *http.Client, x exchange) string {
+		t.Helper()
+		req, _ := http.NewRequest(x.method, ts.URL+x.path, strings.NewReader(x.body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	var recorded []string
+	t.Run("record", func(t *testing.T) {
+		rt := NewReplayTransport(t, path, &ReplayOptions{Record: true, Redact: redact})
+		if !rt.Recording() {
+			t.Fatal("Recording() = false, want true")
+		}
+		for _, x := range exchanges {
+			recorded = append(recorded, do(t, rt.Client(), x))
+		}
+	})
+	if serverRequests != len(exchanges) {
+		t.Fatalf("server received %d requests while recording, want %d", serverRequests, len(exchanges))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"Bearer secret", "session=secret", "secret-token"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("golden file contains %q:\n%s", secret, data)
+		}
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		rt := NewReplayTransport(t, path, nil)
+		// Replay the POSTs in a different order: they are matched by body.
+		for _, i := range []int{2, 1, 0, 3} {
+			if got := do(t, rt.Client(), exchanges[i]); got != recorded[i] {
+				t.Errorf("replayed response %d = %q, want %q", i, got, recorded[i])
+			}
+		}
+	})
+	if serverRequests != len(exchanges) {
+		t.Errorf("server received requests while replaying")
+	}
+
+	t.Run("unmatched", func(t *testing.T) {
+		tb := &errorCapturingTB{TB: t}
+		rt := NewReplayTransport(tb, path, nil)
+		do(t, rt.Client(), exchanges[0])
+		do(t, rt.Client(), exchanges[3])
+		// Each exchange is replayed once.
+		req, _ := http.NewRequest("GET", ts.URL+"/a", nil)
+		if _, err := rt.Client().Do(req); err == nil {
+			t.Errorf("third GET /a succeeded, want error")
+		}
+		if len(tb.errors) != 1 {
+			t.Errorf("reported errors %q, want 1", tb.errors)
+		}
+	})
+
+	t.Run("custom match", func(t *testing.T) {
+		rt := NewReplayTransport(t, path, &ReplayOptions{
+			Match: func(recorded, req *http.Request) bool {
+				return recorded.URL.Path == req.URL.Path
+			},
+		})
+		if got := do(t, rt.Client(), exchange{"POST", "/b", "other"}); got != recorded[1] {
+			t.Errorf("response = %q, want %q", got, recorded[1])
+		}
+	})
+}
+
+func TestReplayTransportMissingFile(t *testing.T) {
+	tb := &fatalCapturingTB{TB: t}
+	func() {
+		defer func() {
+			if r := recover(); r != errFatal {
+				panic(r)
+			}
+		}()
+		NewReplayTransport(tb, filepath.Join(t.TempDir(), "missing.txt"), nil)
+	}()
+	if !strings.Contains(tb.fatal, "-httptest.record") {
+		t.Errorf("Fatal message = %q, want mention of -httptest.record", tb.fatal)
+	}
+}
+
+var errFatal = new(int)
+
+// fatalCapturingTB is a testing.TB that records a call to Fatalf
+// and panics with errFatal.
+type fatalCapturingTB struct {
+	testing.TB
+	fatal string
+}
+
+func (tb *fatalCapturingTB) Fatalf(format string, args ...any) {
+	tb.fatal = fmt.Sprintf(format, args...)
+	panic(errFatal)
+}
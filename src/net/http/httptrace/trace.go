@@ -160,6 +160,11 @@ type ClientTrace struct {
 	// request and any body. It may be called multiple times
 	// in the case of retried requests.
 	WroteRequest func(WroteRequestInfo)
+
+	// WillRetry is called by a net/http RetryTransport when an
+	// attempt to send the request has failed and the request will
+	// be sent again, before waiting to retry.
+	WillRetry func(RetryInfo)
 }
 
 // WroteRequestInfo contains information provided to the WroteRequest
@@ -169,6 +174,23 @@ type WroteRequestInfo struct {
 	Err error
 }
 
+// RetryInfo contains information provided to the WillRetry hook.
+type RetryInfo struct {
+	// Attempt is the number of the attempt that failed,
+	// starting at 1 for the first attempt.
+	Attempt int
+
+	// StatusCode is the status code of the failed attempt's
+	// response, or 0 if the attempt returned an error.
+	StatusCode int
+
+	// Err is the error returned by the failed attempt, if any.
+	Err error
+
+	// Delay is how long the client waits before the next attempt.
+	Delay time.Duration
+}
+
 // compose modifies t such that it respects the previously-registered hooks in old,
 // subject to the composition policy requested in t.Compose.
 func (t *ClientTrace) compose(old *ClientTrace) {
@@ -191,6 +213,7 @@ func (t *ClientTrace) compose(old *ClientTrace) {
 	t.WroteHeaders = compose0to0(t.WroteHeaders, old.WroteHeaders)
 	t.Wait100Continue = compose0to0(t.Wait100Continue, old.Wait100Continue)
 	t.WroteRequest = compose1to0(t.WroteRequest, old.WroteRequest)
+	t.WillRetry = compose1to0(t.WillRetry, old.WillRetry)
 }
 
 func compose0to0[F func()](f1, f2 F) F {
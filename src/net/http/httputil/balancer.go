@@ -0,0 +1,521 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoBackend is the error reported for a request to a [Balancer]
+// that has no backends.
+var ErrNoBackend = errors.New("httputil: no backend available")
+
+// A Balancer distributes the requests of a [ReverseProxy] among
+// several backends.
+//
+// A Balancer is used by calling its Rewrite method from the proxy's
+// Rewrite function, and setting the proxy's Transport and ErrorHandler
+// to the Balancer and its ErrorHandler method:
+//
+//	lb := &httputil.Balancer{
+//		Backends: []*httputil.Backend{{URL: url1}, {URL: url2}},
+//	}
+//	proxy := &httputil.ReverseProxy{
+//		Rewrite: func(r *httputil.ProxyRequest) {
+//			lb.Rewrite(r)
+//			r.SetXForwarded()
+//		},
+//		Transport:    lb,
+//		ErrorHandler: lb.ErrorHandler,
+//	}
+//
+// [Balancer.Rewrite] selects a backend for each request using the
+// Balancer's Strategy, among the backends that are available. A backend
+// is unavailable while it is ejected after failing requests, or after
+// failing the active health checks run by [Balancer.RunHealthChecks].
+// If no backend is available, Rewrite selects among all backends.
+//
+// A request to a backend fails if sending it returns an error, other
+// than because the request's context is done. When a backend has failed
+// MaxFails consecutive requests, it is ejected for FailTimeout.
+// Failures are recorded by [Balancer.RoundTrip] and, when the Balancer
+// is not the proxy's Transport, by [Balancer.ErrorHandler].
+//
+// When the Balancer is the proxy's Transport, a failed request is
+// retried on another backend if it is idempotent and can be sent again:
+// its method is GET, HEAD, OPTIONS, TRACE, PUT, or DELETE, or it has an
+// Idempotency-Key or X-Idempotency-Key header, and it has no body. The
+// Balancer also counts the requests in progress on each backend, which
+// the [LeastConnections] strategy uses.
+//
+// The fields of a Balancer must not be modified after it is first used.
+type Balancer struct {
+	// Backends is the set of backends.
+	Backends []*Backend
+
+	// Strategy selects the backend for each request.
+	// If nil, backends are selected in turn, as by RoundRobin.
+	Strategy BalancerStrategy
+
+	// Transport is the RoundTripper used to send requests to backends,
+	// including health checks. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	// MaxFails is the number of consecutive failed requests after which
+	// a backend is ejected. If zero, a backend is ejected after one
+	// failed request. If negative, backends are not ejected.
+	MaxFails int
+
+	// FailTimeout is how long an ejected backend remains unavailable.
+	// If zero, 10 seconds is used.
+	FailTimeout time.Duration
+
+	// MaxRetries is the maximum number of other backends a failed
+	// request is retried on. If zero, a request is retried once.
+	// If negative, requests are not retried.
+	MaxRetries int
+
+	// HealthCheck configures the active health checks run by
+	// RunHealthChecks. If nil, the defaults described in
+	// HealthCheck are used.
+	HealthCheck *HealthCheck
+
+	// ErrorLog specifies an optional logger for errors reported
+	// to ErrorHandler. If nil, logging is done via the log
+	// package's standard logger.
+	ErrorLog *log.Logger
+
+	rr roundRobin // the default strategy
+}
+
+// A Backend is a server to which a [Balancer] sends requests.
+type Backend struct {
+	// URL is the target of requests, as set by ProxyRequest.SetURL.
+	URL *url.URL
+
+	active atomic.Int64 // requests in progress
+
+	mu           sync.Mutex
+	fails        int       // consecutive failed requests
+	ejectedUntil time.Time // when an ejected backend is available again
+	down         bool      // failed the health checks
+	checks       int       // consecutive health checks with the opposite result to down
+}
+
+// ActiveRequests returns the number of requests in progress on b,
+// including requests whose response body is being read.
+// Requests are only counted when the Balancer is the proxy's Transport.
+func (b *Backend) ActiveRequests() int {
+	return int(b.active.Load())
+}
+
+// Available reports whether b may be selected for requests:
+// whether it is neither ejected nor failing its health checks.
+func (b *Backend) Available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.availableLocked(time.Now())
+}
+
+func (b *Backend) availableLocked(now time.Time) bool {
+	return !b.down && !now.Before(b.ejectedUntil)
+}
+
+// A BalancerStrategy selects the backend for a request.
+type BalancerStrategy interface {
+	// Select returns one of backends, which is not empty,
+	// to send r to. It may be called concurrently.
+	Select(r *http.Request, backends []*Backend) *Backend
+}
+
+// RoundRobin returns a strategy selecting backends in turn.
+func RoundRobin() BalancerStrategy {
+	return new(roundRobin)
+}
+
+type roundRobin struct {
+	n atomic.Uint64
+}
+
+func (s *roundRobin) Select(_ *http.Request, backends []*Backend) *Backend {
+	return backends[(s.n.Add(1)-1)%uint64(len(backends))]
+}
+
+// LeastConnections returns a strategy selecting the backend with the
+// fewest requests in progress, as reported by [Backend.ActiveRequests].
+// Ties are broken at random.
+func LeastConnections() BalancerStrategy {
+	return leastConnections{}
+}
+
+type leastConnections struct{}
+
+func (leastConnections) Select(_ *http.Request, backends []*Backend) *Backend {
+	start := rand.N(len(backends))
+	var best *Backend
+	for i := range backends {
+		b := backends[(start+i)%len(backends)]
+		if best == nil || b.ActiveRequests() < best.ActiveRequests() {
+			best = b
+		}
+	}
+	return best
+}
+
+// Random returns a strategy selecting a backend at random.
+func Random() BalancerStrategy {
+	return random{}
+}
+
+type random struct{}
+
+func (random) Select(_ *http.Request, backends []*Backend) *Backend {
+	return backends[rand.N(len(backends))]
+}
+
+// balancerContextKey is the context key of the balancedRequest
+// of an outbound request.
+type balancerContextKey struct{}
+
+// A balancedRequest records the backends tried for a request.
+type balancedRequest struct {
+	lb        *Balancer
+	in        *http.Request
+	url       *url.URL   // outbound URL before SetURL
+	tried     []*Backend // the last one is the current backend
+	roundTrip bool       // sent by Balancer.RoundTrip
+}
+
+func (br *balancedRequest) backend() *Backend {
+	if len(br.tried) == 0 {
+		return nil
+	}
+	return br.tried[len(br.tried)-1]
+}
+
+// Rewrite selects a backend for the request and routes the outbound
+// request to it, as [ProxyRequest.SetURL] does. If the Balancer has no
+// backends, Rewrite does not modify the request, and RoundTrip returns
+// ErrNoBackend.
+func (lb *Balancer) Rewrite(pr *ProxyRequest) {
+	br := &balancedRequest{lb: lb, in: pr.In}
+	u := *pr.Out.URL
+	br.url = &u
+	if b := lb.selectBackend(pr.In, nil); b != nil {
+		br.tried = append(br.tried, b)
+		pr.SetURL(b.URL)
+	}
+	pr.Out = pr.Out.WithContext(context.WithValue(pr.Out.Context(), balancerContextKey{}, br))
+}
+
+// selectBackend selects an available backend for r, other than the
+// ones in tried.
+func (lb *Balancer) selectBackend(r *http.Request, tried []*Backend) *Backend {
+	now := time.Now()
+	var candidates []*Backend
+	for _, b := range lb.Backends {
+		if slices.Contains(tried, b) {
+			continue
+		}
+		b.mu.Lock()
+		ok := b.availableLocked(now)
+		b.mu.Unlock()
+		if ok {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		if len(tried) > 0 {
+			// Don't retry on unavailable backends.
+			return nil
+		}
+		candidates = lb.Backends
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	if lb.Strategy != nil {
+		return lb.Strategy.Select(r, candidates)
+	}
+	return lb.rr.Select(r, candidates)
+}
+
+func (lb *Balancer) transport() http.RoundTripper {
+	if lb.Transport != nil {
+		return lb.Transport
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements [http.RoundTripper]. It sends a request routed by
+// [Balancer.Rewrite] to the selected backend, retrying it on another
+// backend if it fails and can be retried. Other requests are sent
+// unmodified.
+func (lb *Balancer) RoundTrip(req *http.Request) (*http.Response, error) {
+	br, _ := req.Context().Value(balancerContextKey{}).(*balancedRequest)
+	if br == nil || br.lb != lb {
+		return lb.transport().RoundTrip(req)
+	}
+	br.roundTrip = true
+	if br.backend() == nil {
+		return nil, ErrNoBackend
+	}
+	retries := lb.MaxRetries
+	if retries == 0 {
+		retries = 1
+	}
+	if !canRetry(req) {
+		retries = 0
+	}
+	r := req
+	for {
+		b := br.backend()
+		b.active.Add(1)
+		resp, err := lb.transport().RoundTrip(r)
+		if err == nil {
+			lb.recordSuccess(b)
+			release := sync.OnceFunc(func() { b.active.Add(-1) })
+			body := &backendBody{ReadCloser: resp.Body, release: release}
+			if rwc, ok := resp.Body.(io.ReadWriteCloser); ok {
+				resp.Body = switchedBody{body, rwc}
+			} else {
+				resp.Body = body
+			}
+			return resp, nil
+		}
+		b.active.Add(-1)
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		lb.recordFailure(b)
+		if retries <= 0 {
+			return nil, err
+		}
+		next := lb.selectBackend(br.in, br.tried)
+		if next == nil {
+			return nil, err
+		}
+		retries--
+		br.tried = append(br.tried, next)
+		r = req.Clone(req.Context())
+		u := *br.url
+		r.URL = &u
+		rewriteRequestURL(r, next.URL)
+	}
+}
+
+// canRetry reports whether req is idempotent and has no body.
+func canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	_, ok := req.Header["X-Idempotency-Key"]
+	return ok
+}
+
+// A backendBody is the body of a response from a backend.
+// Closing it ends the request.
+type backendBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *backendBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// A switchedBody is the writable body of a 101 Switching Protocols
+// response from a backend.
+type switchedBody struct {
+	*backendBody
+	w io.Writer
+}
+
+func (b switchedBody) Write(p []byte) (int, error) {
+	return b.w.Write(p)
+}
+
+// ErrorHandler is a ReverseProxy.ErrorHandler for requests routed by
+// [Balancer.Rewrite]. If the Balancer is not the proxy's Transport, it
+// records the failure of the backend the request was sent to. It logs
+// the error, and replies with 503 Service Unavailable if the error is
+// ErrNoBackend, and 502 Bad Gateway otherwise.
+func (lb *Balancer) ErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	br, _ := r.Context().Value(balancerContextKey{}).(*balancedRequest)
+	if br != nil && br.lb == lb && !br.roundTrip && r.Context().Err() == nil {
+		if b := br.backend(); b != nil {
+			lb.recordFailure(b)
+		}
+	}
+	if lb.ErrorLog != nil {
+		lb.ErrorLog.Printf("http: proxy error: %v", err)
+	} else {
+		log.Printf("http: proxy error: %v", err)
+	}
+	if errors.Is(err, ErrNoBackend) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusBadGateway)
+}
+
+func (lb *Balancer) recordSuccess(b *Backend) {
+	b.mu.Lock()
+	b.fails = 0
+	b.mu.Unlock()
+}
+
+func (lb *Balancer) recordFailure(b *Backend) {
+	if lb.MaxFails < 0 {
+		return
+	}
+	maxFails := max(lb.MaxFails, 1)
+	failTimeout := lb.FailTimeout
+	if failTimeout == 0 {
+		failTimeout = 10 * time.Second
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fails++
+	if b.fails >= maxFails {
+		b.fails = 0
+		b.ejectedUntil = time.Now().Add(failTimeout)
+	}
+}
+
+// HealthCheck configures the active health checks of a [Balancer].
+type HealthCheck struct {
+	// Path is the path of the GET request sent to check a backend,
+	// joined to the path of the backend's URL. If empty, the request
+	// is sent to the backend's URL.
+	Path string
+
+	// Interval is the time between checks of each backend.
+	// If zero, 10 seconds is used.
+	Interval time.Duration
+
+	// Timeout is the maximum duration of a check.
+	// If zero, 5 seconds or Interval is used, whichever is less.
+	Timeout time.Duration
+
+	// Healthy reports whether the response to a check shows that the
+	// backend is healthy. It need not read or close the response body.
+	// If nil, a response with a 2xx status code is healthy.
+	Healthy func(resp *http.Response) bool
+
+	// UnhealthyThreshold is the number of consecutive failed checks
+	// after which a backend is unavailable. If zero, 2 is used.
+	UnhealthyThreshold int
+
+	// HealthyThreshold is the number of consecutive successful checks
+	// after which an unavailable backend is available again.
+	// If zero, 2 is used.
+	HealthyThreshold int
+}
+
+// RunHealthChecks checks the health of the Balancer's backends
+// periodically until ctx is done, and then returns ctx's error.
+// Each backend is checked as configured by the Balancer's HealthCheck.
+// Backends are considered healthy until they fail their checks.
+func (lb *Balancer) RunHealthChecks(ctx context.Context) error {
+	hc := lb.HealthCheck
+	if hc == nil {
+		hc = &HealthCheck{}
+	}
+	interval := hc.Interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var wg sync.WaitGroup
+		for _, b := range lb.Backends {
+			wg.Go(func() {
+				lb.check(ctx, hc, interval, b)
+			})
+		}
+		wg.Wait()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// check checks the health of b once.
+func (lb *Balancer) check(ctx context.Context, hc *HealthCheck, interval time.Duration, b *Backend) {
+	timeout := hc.Timeout
+	if timeout <= 0 {
+		timeout = min(5*time.Second, interval)
+	}
+	cctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	u := *b.URL
+	if hc.Path != "" {
+		u.Path, u.RawPath = joinURLPath(b.URL, &url.URL{Path: hc.Path})
+	}
+	req, err := http.NewRequestWithContext(cctx, "GET", u.String(), nil)
+	if err != nil {
+		return
+	}
+	resp, err := lb.transport().RoundTrip(req)
+	if ctx.Err() != nil {
+		// The health checks are stopping.
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return
+	}
+	healthy := false
+	if err == nil {
+		if hc.Healthy != nil {
+			healthy = hc.Healthy(resp)
+		} else {
+			healthy = resp.StatusCode >= 200 && resp.StatusCode < 300
+		}
+		io.CopyN(io.Discard, resp.Body, 4<<10)
+		resp.Body.Close()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	threshold := hc.UnhealthyThreshold
+	if b.down {
+		threshold = hc.HealthyThreshold
+	}
+	if threshold <= 0 {
+		threshold = 2
+	}
+	if healthy == !b.down {
+		b.checks = 0
+		return
+	}
+	b.checks++
+	if b.checks >= threshold {
+		b.down = !b.down
+		b.checks = 0
+	}
+}
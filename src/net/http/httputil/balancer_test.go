@@ -0,0 +1,282 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package httputil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// newBackends starts n backend servers named a, b, and so on, each
+// replying with its name and the request path.
+func newBackends(t *testing.T, n int) []*Backend {
+	var backends []*Backend
+	for i := range n {
+		name := string(rune('a' + i))
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, name+" "+r.URL.Path)
+		}))
+		t.Cleanup(s.Close)
+		u, _ := url.Parse(s.URL + "/" + name)
+		backends = append(backends, &Backend{URL: u})
+	}
+	return backends
+}
+
+// deadBackend returns a backend whose server is not running.
+func deadBackend(t *testing.T) *Backend {
+	s := httptest.NewServer(http.NotFoundHandler())
+	u, _ := url.Parse(s.URL + "/dead")
+	s.Close()
+	return &Backend{URL: u}
+}
+
+func balancedProxy(t *testing.T, lb *Balancer) *httptest.Server {
+	if lb.ErrorLog == nil {
+		lb.ErrorLog = log.New(io.Discard, "", 0)
+	}
+	proxy := httptest.NewServer(&ReverseProxy{
+		Rewrite:      lb.Rewrite,
+		Transport:    lb,
+		ErrorHandler: lb.ErrorHandler,
+	})
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func getBody(t *testing.T, method, url string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestBalancerRoundRobin(t *testing.T) {
+	lb := &Balancer{Backends: newBackends(t, 3)}
+	proxy := balancedProxy(t, lb)
+	var got []string
+	for range 6 {
+		_, body := getBody(t, "GET", proxy.URL+"/x")
+		got = append(got, body)
+	}
+	want := []string{"a /a/x", "b /b/x", "c /c/x", "a /a/x", "b /b/x", "c /c/x"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("responses = %q, want %q", got, want)
+	}
+	for _, b := range lb.Backends {
+		if n := b.ActiveRequests(); n != 0 {
+			t.Errorf("%v: ActiveRequests() = %d after requests, want 0", b.URL, n)
+		}
+	}
+}
+
+// firstStrategy selects the first backend.
+type firstStrategy struct{}
+
+func (firstStrategy) Select(_ *http.Request, backends []*Backend) *Backend {
+	return backends[0]
+}
+
+func TestBalancerRetry(t *testing.T) {
+	dead := deadBackend(t)
+	lb := &Balancer{
+		Backends: append([]*Backend{dead}, newBackends(t, 1)...),
+		Strategy: firstStrategy{},
+		MaxFails: 2,
+	}
+	proxy := balancedProxy(t, lb)
+
+	// A POST request is not retried.
+	if code, _ := getBody(t, "POST", proxy.URL+"/x"); code != http.StatusBadGateway {
+		t.Errorf("POST to dead backend: status %d, want 502", code)
+	}
+	if !dead.Available() {
+		t.Errorf("backend ejected after one failure, want two")
+	}
+	// A GET request is retried on the other backend.
+	if code, body := getBody(t, "GET", proxy.URL+"/x"); code != 200 || body != "a /a/x" {
+		t.Errorf("GET: %d %q, want 200 from the other backend", code, body)
+	}
+	if dead.Available() {
+		t.Errorf("backend not ejected after two failures")
+	}
+	// The dead backend is no longer selected.
+	if code, body := getBody(t, "POST", proxy.URL+"/y"); code != 200 || body != "a /a/y" {
+		t.Errorf("POST after ejection: %d %q, want 200 from the other backend", code, body)
+	}
+}
+
+func TestBalancerErrorHandler(t *testing.T) {
+	// With another Transport, failures are recorded by ErrorHandler.
+	dead := deadBackend(t)
+	lb := &Balancer{
+		Backends: append([]*Backend{dead}, newBackends(t, 1)...),
+		Strategy: firstStrategy{},
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	proxy := httptest.NewServer(&ReverseProxy{
+		Rewrite:      lb.Rewrite,
+		ErrorHandler: lb.ErrorHandler,
+	})
+	defer proxy.Close()
+	if code, _ := getBody(t, "GET", proxy.URL); code != http.StatusBadGateway {
+		t.Errorf("GET to dead backend: status %d, want 502", code)
+	}
+	if dead.Available() {
+		t.Errorf("backend not ejected after failure")
+	}
+	if code, body := getBody(t, "GET", proxy.URL+"/x"); code != 200 || body != "a /a/x" {
+		t.Errorf("GET after ejection: %d %q, want 200 from the other backend", code, body)
+	}
+}
+
+func TestBalancerNoBackends(t *testing.T) {
+	proxy := balancedProxy(t, &Balancer{})
+	if code, _ := getBody(t, "GET", proxy.URL); code != http.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503", code)
+	}
+}
+
+func TestBalancerAllUnavailable(t *testing.T) {
+	// When all backends are ejected, requests are still sent to them.
+	lb := &Balancer{Backends: newBackends(t, 2)}
+	for _, b := range lb.Backends {
+		lb.recordFailure(b)
+	}
+	proxy := balancedProxy(t, lb)
+	if code, _ := getBody(t, "GET", proxy.URL); code != 200 {
+		t.Errorf("status %d, want 200", code)
+	}
+}
+
+func TestBalancerLeastConnections(t *testing.T) {
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started.Done()
+		<-release
+		io.WriteString(w, "slow")
+	}))
+	defer slow.Close()
+	slowURL, _ := url.Parse(slow.URL)
+	lb := &Balancer{
+		Backends: append([]*Backend{{URL: slowURL}}, newBackends(t, 1)...),
+		Strategy: LeastConnections(),
+	}
+	proxy := balancedProxy(t, lb)
+
+	// Occupy the slow backend. The backends are selected at random
+	// while both are idle.
+	started.Add(1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			resp, err := http.Get(proxy.URL + "/x")
+			if err != nil {
+				return
+			}
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(b) == "slow" {
+				return
+			}
+		}
+	}()
+	started.Wait()
+	if n := lb.Backends[0].ActiveRequests(); n != 1 {
+		t.Fatalf("slow backend has %d active requests, want 1", n)
+	}
+	for range 3 {
+		if _, body := getBody(t, "GET", proxy.URL+"/x"); body != "a /a/x" {
+			t.Errorf("response from %q, want the idle backend", body)
+		}
+	}
+	close(release)
+	<-done
+}
+
+func TestBalancerHealthChecks(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var mu sync.Mutex
+		healthy := map[string]bool{"a": true, "b": true}
+		var paths []string
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			paths = append(paths, req.URL.Host+req.URL.Path)
+			if !healthy[req.URL.Host] {
+				return nil, errors.New("connection refused")
+			}
+			return &http.Response{StatusCode: 200, Body: http.NoBody, Request: req}, nil
+		})
+		a, _ := url.Parse("http://a/base")
+		b, _ := url.Parse("http://b/")
+		lb := &Balancer{
+			Backends:  []*Backend{{URL: a}, {URL: b}},
+			Transport: transport,
+			HealthCheck: &HealthCheck{
+				Path:     "/healthz",
+				Interval: time.Second,
+			},
+		}
+		ctx, cancel := context.WithCancel(t.Context())
+		errc := make(chan error)
+		go func() { errc <- lb.RunHealthChecks(ctx) }()
+		synctest.Wait()
+		mu.Lock()
+		if got, want := strings.Join(paths, " "), "a/base/healthz b/healthz"; got != want && got != "b/healthz a/base/healthz" {
+			t.Errorf("checked %q, want %q", got, want)
+		}
+		healthy["b"] = false
+		mu.Unlock()
+
+		time.Sleep(time.Second)
+		synctest.Wait()
+		if !lb.Backends[1].Available() {
+			t.Errorf("backend unavailable after one failed check, want two")
+		}
+		time.Sleep(time.Second)
+		synctest.Wait()
+		if lb.Backends[1].Available() {
+			t.Errorf("backend available after two failed checks")
+		}
+		if !lb.Backends[0].Available() {
+			t.Errorf("healthy backend unavailable")
+		}
+
+		mu.Lock()
+		healthy["b"] = true
+		mu.Unlock()
+		time.Sleep(2 * time.Second)
+		synctest.Wait()
+		if !lb.Backends[1].Available() {
+			t.Errorf("backend unavailable after two successful checks")
+		}
+
+		cancel()
+		if err := <-errc; err != context.Canceled {
+			t.Errorf("RunHealthChecks = %v, want context.Canceled", err)
+		}
+	})
+}
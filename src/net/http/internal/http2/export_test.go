@@ -223,8 +223,8 @@ func InvalidHTTP1LookingFrameHeader() FrameHeader {
 	return invalidHTTP1LookingFrameHeader()
 }
 
-func EncodeRequestHeaders(req *ClientRequest, addGzipHeader bool, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
-	return encodeRequestHeaders(req, addGzipHeader, peerMaxHeaderListSize, headerf)
+func EncodeRequestHeaders(req *ClientRequest, acceptEncoding string, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
+	return encodeRequestHeaders(req, acceptEncoding, peerMaxHeaderListSize, headerf)
 }
 
 func (w *responseWriter) hasWriteBuffer() bool {
@@ -2982,6 +2982,13 @@ func (w *responseWriter) handlerDone() {
 	responseWriterStatePool.Put(rws)
 }
 
+// ResponseStats returns the status code written by the handler of w,
+// or 0 if it has not written one, and the number of body bytes it has
+// written. It must be called before the handler returns.
+func ResponseStats(w *ResponseWriter) (status int, written int64) {
+	return w.rws.status, w.rws.wroteBytes
+}
+
 // Push errors.
 var (
 	ErrRecursivePush    = errors.New("http2: recursive push not allowed")
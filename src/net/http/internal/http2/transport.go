@@ -11,6 +11,7 @@ import (
 	"bytes"
 	"compress/flate"
 	"compress/gzip"
+	"compress/zstd"
 	"context"
 	"crypto/rand"
 	"crypto/tls"
@@ -233,11 +234,13 @@ type clientStream struct {
 	ctx       context.Context
 	reqCancel <-chan struct{}
 
-	trace         *httptrace.ClientTrace // or nil
-	ID            uint32
-	bufPipe       pipe // buffered pipe with the flow-controlled response payload
-	requestedGzip bool
-	isHead        bool
+	trace          *httptrace.ClientTrace // or nil
+	ID             uint32
+	bufPipe        pipe // buffered pipe with the flow-controlled response payload
+	requestedGzip  bool
+	requestedZstd  bool
+	acceptEncoding string // Accept-Encoding header added if requestedGzip
+	isHead         bool
 
 	abortOnce sync.Once
 	abort     chan struct{} // closed to signal stream should end immediately
@@ -1144,6 +1147,10 @@ func (cc *ClientConn) roundTrip(req *ClientRequest, streamf func(*clientStream))
 	cs := &req.stream
 
 	cs.requestedGzip = httpcommon.IsRequestGzip(req.Method, req.Header, cc.t.disableCompression())
+	if cs.requestedGzip {
+		cs.acceptEncoding = httpcommon.AcceptEncoding()
+		cs.requestedZstd = strings.Contains(cs.acceptEncoding, "zstd")
+	}
 
 	go cs.doRequest(req, streamf)
 
@@ -1509,7 +1516,7 @@ func (cs *clientStream) encodeAndWriteHeaders(req *ClientRequest) error {
 	// sent by writeRequestBody below, along with any Trailers,
 	// again in form HEADERS{1}, CONTINUATION{0,})
 	cc.hbuf.Reset()
-	res, err := encodeRequestHeaders(req, cs.requestedGzip, cc.peerMaxHeaderListSize, func(name, value string) {
+	res, err := encodeRequestHeaders(req, cs.acceptEncoding, cc.peerMaxHeaderListSize, func(name, value string) {
 		cc.writeHeader(name, value)
 	})
 	if err != nil {
@@ -1525,7 +1532,7 @@ func (cs *clientStream) encodeAndWriteHeaders(req *ClientRequest) error {
 	return err
 }
 
-func encodeRequestHeaders(req *ClientRequest, addGzipHeader bool, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
+func encodeRequestHeaders(req *ClientRequest, acceptEncoding string, peerMaxHeaderListSize uint64, headerf func(name, value string)) (httpcommon.EncodeHeadersResult, error) {
 	return httpcommon.EncodeHeaders(req.Context, httpcommon.EncodeHeadersParam{
 		Request: httpcommon.Request{
 			Header:              req.Header,
@@ -1535,7 +1542,7 @@ func encodeRequestHeaders(req *ClientRequest, addGzipHeader bool, peerMaxHeaderL
 			Method:              req.Method,
 			ActualContentLength: actualContentLength(req),
 		},
-		AddGzipHeader:         addGzipHeader,
+		AcceptEncoding:        acceptEncoding,
 		PeerMaxHeaderListSize: peerMaxHeaderListSize,
 		DefaultUserAgent:      defaultUserAgent,
 	}, headerf)
@@ -2434,11 +2441,12 @@ func (rl *clientConnReadLoop) handleResponse(cs *clientStream, f *MetaHeadersFra
 	cs.bytesRemain = res.ContentLength
 	res.Body = transportResponseBody{cs}
 
-	if cs.requestedGzip && asciiEqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+	if ce := res.Header.Get("Content-Encoding"); cs.requestedGzip && asciiEqualFold(ce, "gzip") ||
+		cs.requestedZstd && asciiEqualFold(ce, "zstd") {
 		res.Header.Del("Content-Encoding")
 		res.Header.Del("Content-Length")
 		res.ContentLength = -1
-		res.Body = &gzipReader{body: res.Body}
+		res.Body = &decompressReader{body: res.Body, zstd: asciiEqualFold(ce, "zstd")}
 		res.Uncompressed = true
 	}
 	return res, nil
@@ -3091,16 +3099,17 @@ func (rt erringRoundTripper) RoundTrip(*ClientRequest) (*ClientResponse, error)
 
 var errConcurrentReadOnResBody = errors.New("http2: concurrent read on response body")
 
-// gzipReader wraps a response body so it can lazily
-// get gzip.Reader from the pool on the first call to Read.
-// After Close is called it puts gzip.Reader to the pool immediately
+// decompressReader wraps a response body so it can lazily
+// get a gzip.Reader or zstd.Reader from a pool on the first call to Read.
+// After Close is called it puts the reader to the pool immediately
 // if there is no Read in progress or later when Read completes.
-type gzipReader struct {
+type decompressReader struct {
 	_    incomparable
 	body io.ReadCloser // underlying Response.Body
+	zstd bool          // body is zstd rather than gzip compressed
 	mu   sync.Mutex    // guards zr and zerr
-	zr   *gzip.Reader  // stores gzip reader from the pool between reads
-	zerr error         // sticky gzip reader init error or sentinel value to detect concurrent read and read after close
+	zr   io.Reader     // stores *gzip.Reader or *zstd.Reader from the pool between reads
+	zerr error         // sticky reader init error or sentinel value to detect concurrent read and read after close
 }
 
 type eofReader struct{}
@@ -3108,98 +3117,113 @@ type eofReader struct{}
 func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
 func (eofReader) ReadByte() (byte, error)  { return 0, io.EOF }
 
-var gzipPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
+var (
+	gzipPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
+	zstdPool = sync.Pool{New: func() any { return zstd.NewReader(nil) }}
+)
 
-// gzipPoolGet gets a gzip.Reader from the pool and resets it to read from r.
-func gzipPoolGet(r io.Reader) (*gzip.Reader, error) {
+// decompressPoolGet gets a gzip.Reader, or a zstd.Reader if isZstd
+// is true, from the pool and resets it to read from r.
+func decompressPoolGet(r io.Reader, isZstd bool) (io.Reader, error) {
+	if isZstd {
+		zr := zstdPool.Get().(*zstd.Reader)
+		zr.Reset(r)
+		return zr, nil
+	}
 	zr := gzipPool.Get().(*gzip.Reader)
 	if err := zr.Reset(r); err != nil {
-		gzipPoolPut(zr)
+		decompressPoolPut(zr)
 		return nil, err
 	}
 	return zr, nil
 }
 
-// gzipPoolPut puts a gzip.Reader back into the pool.
-func gzipPoolPut(zr *gzip.Reader) {
-	// Reset will allocate bufio.Reader if we pass it anything
-	// other than a flate.Reader, so ensure that it's getting one.
-	var r flate.Reader = eofReader{}
-	zr.Reset(r)
-	gzipPool.Put(zr)
+// decompressPoolPut puts a gzip.Reader or zstd.Reader back into its pool.
+func decompressPoolPut(zr io.Reader) {
+	switch zr := zr.(type) {
+	case *gzip.Reader:
+		// Reset will allocate bufio.Reader if we pass it anything
+		// other than a flate.Reader, so ensure that it's getting one.
+		var r flate.Reader = eofReader{}
+		zr.Reset(r)
+		gzipPool.Put(zr)
+	case *zstd.Reader:
+		zr.Reset(eofReader{})
+		zstdPool.Put(zr)
+	}
 }
 
-// acquire returns a gzip.Reader for reading response body.
+// acquire returns a reader for reading the decompressed response body.
 // The reader must be released after use.
-func (gz *gzipReader) acquire() (*gzip.Reader, error) {
-	gz.mu.Lock()
-	defer gz.mu.Unlock()
-	if gz.zerr != nil {
-		return nil, gz.zerr
-	}
-	if gz.zr == nil {
-		// gzipPoolGet might block indefinitely since it reads the gzip header.
-		// Therefore, drop mu temporarily when using gzipPoolGet.
+func (dr *decompressReader) acquire() (io.Reader, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.zerr != nil {
+		return nil, dr.zerr
+	}
+	if dr.zr == nil {
+		// decompressPoolGet might block indefinitely since it reads the gzip header.
+		// Therefore, drop mu temporarily when using decompressPoolGet.
 		// We set zerr to errConcurrentReadOnResBody to prevent concurrent read
 		// even when mu is temporarily dropped.
-		gz.zerr = errConcurrentReadOnResBody
-		gz.mu.Unlock()
-		zr, err := gzipPoolGet(gz.body)
-		gz.mu.Lock()
-		// Guard against Close being called while gzipPoolGet is running.
-		if gz.zerr != errConcurrentReadOnResBody {
+		dr.zerr = errConcurrentReadOnResBody
+		dr.mu.Unlock()
+		zr, err := decompressPoolGet(dr.body, dr.zstd)
+		dr.mu.Lock()
+		// Guard against Close being called while decompressPoolGet is running.
+		if dr.zerr != errConcurrentReadOnResBody {
 			if zr != nil {
-				gzipPoolPut(zr)
+				decompressPoolPut(zr)
 			}
-			return nil, gz.zerr
+			return nil, dr.zerr
 		}
-		gz.zr, gz.zerr = zr, err
-		if gz.zerr != nil {
-			return nil, gz.zerr
+		dr.zr, dr.zerr = zr, err
+		if dr.zerr != nil {
+			return nil, dr.zerr
 		}
 	}
-	ret := gz.zr
-	gz.zr, gz.zerr = nil, errConcurrentReadOnResBody
+	ret := dr.zr
+	dr.zr, dr.zerr = nil, errConcurrentReadOnResBody
 	return ret, nil
 }
 
-// release returns the gzip.Reader to the pool if Close was called during Read.
-func (gz *gzipReader) release(zr *gzip.Reader) {
-	gz.mu.Lock()
-	defer gz.mu.Unlock()
-	if gz.zerr == errConcurrentReadOnResBody {
-		gz.zr, gz.zerr = zr, nil
+// release returns the reader to the pool if Close was called during Read.
+func (dr *decompressReader) release(zr io.Reader) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.zerr == errConcurrentReadOnResBody {
+		dr.zr, dr.zerr = zr, nil
 	} else { // fs.ErrClosed
-		gzipPoolPut(zr)
+		decompressPoolPut(zr)
 	}
 }
 
-// close returns the gzip.Reader to the pool immediately or
+// close returns the reader to the pool immediately or
 // signals release to do so after Read completes.
-func (gz *gzipReader) close() {
-	gz.mu.Lock()
-	defer gz.mu.Unlock()
-	if gz.zerr == nil && gz.zr != nil {
-		gzipPoolPut(gz.zr)
-		gz.zr = nil
+func (dr *decompressReader) close() {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.zerr == nil && dr.zr != nil {
+		decompressPoolPut(dr.zr)
+		dr.zr = nil
 	}
-	gz.zerr = fs.ErrClosed
+	dr.zerr = fs.ErrClosed
 }
 
-func (gz *gzipReader) Read(p []byte) (n int, err error) {
-	zr, err := gz.acquire()
+func (dr *decompressReader) Read(p []byte) (n int, err error) {
+	zr, err := dr.acquire()
 	if err != nil {
 		return 0, err
 	}
-	defer gz.release(zr)
+	defer dr.release(zr)
 
 	return zr.Read(p)
 }
 
-func (gz *gzipReader) Close() error {
-	gz.close()
+func (dr *decompressReader) Close() error {
+	dr.close()
 
-	return gz.body.Close()
+	return dr.body.Close()
 }
 
 // isConnectionCloseRequest reports whether req should use its own
@@ -54,10 +54,10 @@ func TestActualContentLength(t *testing.T) {
 	}
 }
 
-// Tests that gzipReader doesn't crash on a second Read call following
+// Tests that decompressReader doesn't crash on a second Read call following
 // the first Read call's gzip.NewReader returning an error.
 func TestGzipReader_DoubleReadCrash(t *testing.T) {
-	gz := &gzipReader{
+	gz := &decompressReader{
 		body: io.NopCloser(strings.NewReader("0123456789")),
 	}
 	var buf [1]byte
@@ -76,7 +76,7 @@ func TestGzipReader_ReadAfterClose(t *testing.T) {
 	w := gzip.NewWriter(&body)
 	w.Write([]byte("012345679"))
 	w.Close()
-	gz := &gzipReader{
+	gz := &decompressReader{
 		body: io.NopCloser(&body),
 	}
 	var buf [1]byte
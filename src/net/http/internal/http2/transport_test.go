@@ -1298,7 +1298,7 @@ func testTransportChecksRequestHeaderListSize(t *testing.T) {
 				Method:              req.Method,
 				ActualContentLength: req.ContentLength,
 			},
-			AddGzipHeader:         true,
+			AcceptEncoding:        httpcommon.AcceptEncoding(),
 			PeerMaxHeaderListSize: 0xffffffffffffffff,
 		}, func(name, value string) {
 			hf := hpack.HeaderField{Name: name, Value: value}
@@ -2708,7 +2708,6 @@ func TestTransportRequestPathPseudo(t *testing.T) {
 				Method:              tt.req.Method,
 				ActualContentLength: tt.req.ContentLength,
 			},
-			AddGzipHeader:         false,
 			PeerMaxHeaderListSize: 0xffffffffffffffff,
 		}, func(name, value string) {
 			henc.WriteField(hpack.HeaderField{Name: name, Value: value})
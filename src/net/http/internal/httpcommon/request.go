@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"internal/godebug"
 	"net/http/httptrace"
 	"net/textproto"
 	"net/url"
@@ -39,9 +40,9 @@ type Request struct {
 type EncodeHeadersParam struct {
 	Request Request
 
-	// AddGzipHeader indicates that an "accept-encoding: gzip" header should be
-	// added to the request.
-	AddGzipHeader bool
+	// AcceptEncoding, if non-empty, is the value of an "accept-encoding"
+	// header to add to the request. See AcceptEncoding.
+	AcceptEncoding string
 
 	// PeerMaxHeaderListSize, when non-zero, is the peer's MAX_HEADER_LIST_SIZE setting.
 	PeerMaxHeaderListSize uint64
@@ -216,8 +217,8 @@ func EncodeHeaders(ctx context.Context, param EncodeHeadersParam, headerf func(n
 		if shouldSendReqContentLength(req.Method, req.ActualContentLength) {
 			f("content-length", strconv.FormatInt(req.ActualContentLength, 10))
 		}
-		if param.AddGzipHeader {
-			f("accept-encoding", "gzip")
+		if param.AcceptEncoding != "" {
+			f("accept-encoding", param.AcceptEncoding)
 		}
 		if !didUA {
 			f("user-agent", param.DefaultUserAgent)
@@ -263,8 +264,8 @@ func EncodeHeaders(ctx context.Context, param EncodeHeadersParam, headerf func(n
 	return res, nil
 }
 
-// IsRequestGzip reports whether we should add an Accept-Encoding: gzip header
-// for a request.
+// IsRequestGzip reports whether we should add an Accept-Encoding header
+// with the value returned by AcceptEncoding for a request.
 func IsRequestGzip(method string, header map[string][]string, disableCompression bool) bool {
 	// TODO(bradfitz): this is a copy of the logic in net/http. Unify somewhere?
 	if !disableCompression &&
@@ -501,3 +502,17 @@ func NewServerRequest(rp ServerRequestParam) ServerRequestResult {
 		Trailer:       trailer,
 	}
 }
+
+var httpzstd = godebug.New("httpzstd")
+
+// AcceptEncoding returns the value of the Accept-Encoding header that
+// a Transport adds to requests when IsRequestGzip reports true.
+// The Transport transparently decodes responses using any of these
+// content codings.
+func AcceptEncoding() string {
+	if httpzstd.Value() == "0" {
+		httpzstd.IncNonDefault()
+		return "gzip"
+	}
+	return "gzip, zstd"
+}
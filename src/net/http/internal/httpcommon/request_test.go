@@ -43,7 +43,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -64,7 +64,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -89,7 +89,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -114,7 +114,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -139,7 +139,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -159,7 +159,7 @@ func TestEncodeHeaders(t *testing.T) {
 		wantHeaders: []header{
 			{":authority", "example.tld"},
 			{":method", "CONNECT"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -185,7 +185,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":path", "/"},
 			{":protocol", "foo"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -211,7 +211,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"trailer", "A,B"},
 			{"user-agent", "default-user-agent"},
 		},
@@ -237,7 +237,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "GopherTron 9000"},
 		},
 	}, {
@@ -262,7 +262,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 		},
 	}, {
 		name: "ignore host header",
@@ -286,7 +286,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -311,7 +311,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "GET"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 			// Cookie header is split into separate header fields.
 			{"cookie", "a=b"},
@@ -337,7 +337,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "POST"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 			{"content-length", "0"},
 		},
@@ -360,7 +360,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "POST"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 			{"content-length", "0"},
 		},
@@ -384,7 +384,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "POST"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 			{"content-length", "10"},
 		},
@@ -408,7 +408,7 @@ func TestEncodeHeaders(t *testing.T) {
 			{":method", "POST"},
 			{":path", "/"},
 			{":scheme", "https"},
-			{"accept-encoding", "gzip"},
+			{"accept-encoding", "gzip, zstd"},
 			{"user-agent", "default-user-agent"},
 		},
 	}, {
@@ -486,7 +486,7 @@ func TestEncodeHeaders(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			var gotHeaders []header
 			if IsRequestGzip(test.in.Request.Method, test.in.Request.Header, test.disableCompression) {
-				test.in.AddGzipHeader = true
+				test.in.AcceptEncoding = AcceptEncoding()
 			}
 
 			got, err := EncodeHeaders(context.Background(), test.in, func(name, value string) {
@@ -0,0 +1,286 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ServerLimits limits the requests handled by a [Server].
+//
+// The limits are applied to each request before it is passed to the
+// Server's Handler, and before its body is read. A request that exceeds
+// a limit is rejected: the Handler is not called, and the client receives
+// a response with a Retry-After header. An HTTP/1 connection is closed
+// after the response, as the request's body has not been read; HTTP/2
+// and HTTP/3 connections remain open for other requests.
+//
+// Requests are counted the same way whatever protocol they arrive on:
+// an HTTP/1 request and an HTTP/2 or HTTP/3 stream each count as one
+// request.
+type ServerLimits struct {
+	// MaxConcurrentRequests is the maximum number of requests the
+	// server handles at once. Requests beyond the limit are rejected
+	// with LimitServerConcurrency. Zero means no limit.
+	MaxConcurrentRequests int
+
+	// MaxConcurrentRequestsPerClient is the maximum number of requests
+	// the server handles at once for a single client. Requests beyond
+	// the limit are rejected with LimitClientConcurrency.
+	// Zero means no limit.
+	MaxConcurrentRequestsPerClient int
+
+	// ClientRate is the sustained number of requests per second the
+	// server accepts from a single client, and ClientBurst is the number
+	// of requests a client may make at once after being idle. Requests
+	// beyond the rate are rejected with LimitClientRate.
+	// If ClientRate is zero, the rate is not limited.
+	// If ClientBurst is zero, it is ClientRate rounded up, or 1 if greater.
+	ClientRate  float64
+	ClientBurst int
+
+	// MaxConcurrentStreams is the maximum number of requests a client
+	// may have in progress at once on a single connection.
+	// An HTTP/1 connection handles one request at a time.
+	// For HTTP/2 connections, the limit is advertised to clients,
+	// unless HTTP2Config.MaxConcurrentStreams is set to a lower value.
+	// Zero means the protocol's default.
+	MaxConcurrentStreams int
+
+	// ClientKey returns a string identifying the client that sent r,
+	// for the per-client limits.
+	// If ClientKey is nil, the IP address in r.RemoteAddr is used.
+	// Servers behind a proxy may use ClientKey to identify clients
+	// by a header set by the proxy.
+	ClientKey func(r *Request) string
+
+	// StatusCode is the status code of the response to a rejected
+	// request. If zero, requests rejected by MaxConcurrentRequests
+	// receive 503 Service Unavailable, and requests rejected by a
+	// per-client limit receive 429 Too Many Requests.
+	StatusCode int
+
+	// RetryAfter is the delay suggested to clients by the Retry-After
+	// header of the response to a request rejected by a concurrency
+	// limit, rounded up to a whole number of seconds. If zero, 1 second
+	// is used. Responses to requests rejected by ClientRate suggest the
+	// time until the client may make another request.
+	RetryAfter time.Duration
+
+	// OnReject, if non-nil, is called with each rejected request and the
+	// limit that rejected it, before the response is written.
+	// It can be used to record metrics.
+	OnReject func(r *Request, reason LimitReason)
+}
+
+// A LimitReason identifies the limit of a [ServerLimits]
+// that caused a request to be rejected.
+type LimitReason int
+
+const (
+	LimitServerConcurrency LimitReason = iota + 1 // ServerLimits.MaxConcurrentRequests
+	LimitClientConcurrency                        // ServerLimits.MaxConcurrentRequestsPerClient
+	LimitClientRate                               // ServerLimits.ClientRate
+)
+
+func (r LimitReason) String() string {
+	switch r {
+	case LimitServerConcurrency:
+		return "server concurrency"
+	case LimitClientConcurrency:
+		return "client concurrency"
+	case LimitClientRate:
+		return "client rate"
+	}
+	return "LimitReason(" + strconv.Itoa(int(r)) + ")"
+}
+
+// A serverLimiter enforces the ServerLimits of a Server.
+type serverLimiter struct {
+	limits ServerLimits
+	burst  float64
+	active atomic.Int64 // requests being handled
+
+	mu      sync.Mutex
+	clients map[string]*clientLimitState
+	sweepAt int // size of clients at which to remove idle entries
+}
+
+// clientLimitState is the state of a single client of a serverLimiter.
+type clientLimitState struct {
+	active int       // requests being handled
+	tokens float64   // requests the client may make now
+	last   time.Time // when tokens was last updated
+}
+
+func newServerLimiter(limits *ServerLimits) *serverLimiter {
+	l := &serverLimiter{
+		limits:  *limits,
+		clients: make(map[string]*clientLimitState),
+		sweepAt: 64,
+	}
+	l.burst = float64(limits.ClientBurst)
+	if l.burst <= 0 {
+		l.burst = max(1, math.Ceil(limits.ClientRate))
+	}
+	return l
+}
+
+func (s *Server) limiter() *serverLimiter {
+	s.limiterOnce.Do(func() {
+		s.limiterState = newServerLimiter(s.Limits)
+	})
+	return s.limiterState
+}
+
+func (l *serverLimiter) perClient() bool {
+	return l.limits.MaxConcurrentRequestsPerClient > 0 || l.limits.ClientRate > 0
+}
+
+// admit reports whether r may be handled, and otherwise writes the
+// response rejecting it. If admit returns true, the caller must call
+// done with the returned key when it has finished handling r.
+func (l *serverLimiter) admit(w ResponseWriter, r *Request) (key string, ok bool) {
+	if limit := l.limits.MaxConcurrentRequests; limit > 0 {
+		if l.active.Add(1) > int64(limit) {
+			l.active.Add(-1)
+			l.reject(w, r, LimitServerConcurrency, 0)
+			return "", false
+		}
+	}
+	if l.perClient() {
+		key = l.clientKey(r)
+		if reason, wait := l.admitClient(key, time.Now()); reason != 0 {
+			if l.limits.MaxConcurrentRequests > 0 {
+				l.active.Add(-1)
+			}
+			l.reject(w, r, reason, wait)
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// admitClient records the start of a request from the client with the
+// given key, unless the request exceeds a per-client limit. In that
+// case it returns the limit and, for LimitClientRate, the time until
+// the client may make another request.
+func (l *serverLimiter) admitClient(key string, now time.Time) (LimitReason, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := l.clients[key]
+	if c == nil {
+		if len(l.clients) >= l.sweepAt {
+			l.sweepLocked(now)
+		}
+		c = &clientLimitState{tokens: l.burst, last: now}
+		l.clients[key] = c
+	}
+	if rate := l.limits.ClientRate; rate > 0 {
+		l.refillLocked(c, now)
+		if c.tokens < 1 {
+			wait := time.Duration((1 - c.tokens) / rate * float64(time.Second))
+			return LimitClientRate, wait
+		}
+	}
+	if limit := l.limits.MaxConcurrentRequestsPerClient; limit > 0 && c.active >= limit {
+		return LimitClientConcurrency, 0
+	}
+	if l.limits.ClientRate > 0 {
+		c.tokens--
+	}
+	c.active++
+	return 0, 0
+}
+
+func (l *serverLimiter) refillLocked(c *clientLimitState, now time.Time) {
+	if elapsed := now.Sub(c.last); elapsed > 0 {
+		c.tokens = min(l.burst, c.tokens+elapsed.Seconds()*l.limits.ClientRate)
+	}
+	c.last = now
+}
+
+// sweepLocked removes the clients with no requests in progress
+// whose rate limit has fully recovered, which are equivalent to
+// clients that have not been seen.
+func (l *serverLimiter) sweepLocked(now time.Time) {
+	for key, c := range l.clients {
+		if c.active > 0 {
+			continue
+		}
+		if l.limits.ClientRate > 0 {
+			l.refillLocked(c, now)
+			if c.tokens < l.burst {
+				continue
+			}
+		}
+		delete(l.clients, key)
+	}
+	l.sweepAt = max(64, 2*len(l.clients))
+}
+
+// done records the end of a request admitted by admit.
+func (l *serverLimiter) done(key string) {
+	if l.limits.MaxConcurrentRequests > 0 {
+		l.active.Add(-1)
+	}
+	if !l.perClient() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := l.clients[key]
+	if c == nil {
+		return
+	}
+	c.active--
+	if c.active == 0 && l.limits.ClientRate == 0 {
+		delete(l.clients, key)
+	}
+}
+
+func (l *serverLimiter) clientKey(r *Request) string {
+	if l.limits.ClientKey != nil {
+		return l.limits.ClientKey(r)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func (l *serverLimiter) reject(w ResponseWriter, r *Request, reason LimitReason, wait time.Duration) {
+	if l.limits.OnReject != nil {
+		l.limits.OnReject(r, reason)
+	}
+	code := l.limits.StatusCode
+	if code == 0 {
+		code = StatusTooManyRequests
+		if reason == LimitServerConcurrency {
+			code = StatusServiceUnavailable
+		}
+	}
+	if reason != LimitClientRate {
+		wait = l.limits.RetryAfter
+		if wait <= 0 {
+			wait = time.Second
+		}
+	}
+	secs := max(1, int64((wait+time.Second-1)/time.Second))
+	// The request's body has not been read, so an HTTP/1 connection
+	// cannot be reused. HTTP/2 resets the stream of an unread body,
+	// and takes "Connection: close" as a request to shut down the
+	// whole connection.
+	if r.ProtoMajor == 1 {
+		w.Header().Set("Connection", "close")
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	Error(w, StatusText(code), code)
+}
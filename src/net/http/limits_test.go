@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"io"
+	. "net/http"
+	"net/http/httptrace"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// limitsTest runs a server with the given limits whose handler
+// blocks until the test releases it.
+type limitsTest struct {
+	t       *testing.T
+	cst     *clientServerTest
+	entered chan struct{}
+	release chan struct{}
+
+	mu      sync.Mutex
+	reasons []LimitReason
+}
+
+func newLimitsTest(t *testing.T, mode testMode, limits *ServerLimits) *limitsTest {
+	lt := &limitsTest{
+		t:       t,
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	limits.OnReject = func(r *Request, reason LimitReason) {
+		lt.mu.Lock()
+		defer lt.mu.Unlock()
+		lt.reasons = append(lt.reasons, reason)
+	}
+	lt.cst = newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if r.URL.Query().Has("block") {
+			lt.entered <- struct{}{}
+			<-lt.release
+		}
+	}), func(s *Server) {
+		s.Limits = limits
+	})
+	return lt
+}
+
+// get makes a request, returning its status and Retry-After header.
+func (lt *limitsTest) get(query, client string) (int, string) {
+	lt.t.Helper()
+	req, _ := NewRequest("GET", lt.cst.ts.URL+"/?"+query, nil)
+	req.Header.Set("X-Client", client)
+	res, err := lt.cst.c.Do(req)
+	if err != nil {
+		lt.t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	if res.StatusCode != StatusOK && lt.cst.mode == http1Mode && !res.Close {
+		lt.t.Errorf("rejected request: connection not closed")
+	}
+	return res.StatusCode, res.Header.Get("Retry-After")
+}
+
+// block starts a request that blocks in the handler, and waits for it
+// to be handled. The returned channel is closed when the request ends.
+func (lt *limitsTest) block(client string) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if code, _ := lt.get("block", client); code != 200 {
+			lt.t.Errorf("blocking request: status %d, want 200", code)
+		}
+	}()
+	<-lt.entered
+	return done
+}
+
+func (lt *limitsTest) wantReasons(want ...LimitReason) {
+	lt.t.Helper()
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	if !slices.Equal(lt.reasons, want) {
+		lt.t.Errorf("OnReject reasons = %v, want %v", lt.reasons, want)
+	}
+}
+
+func TestServerLimitsConcurrency(t *testing.T) {
+	run(t, testServerLimitsConcurrency, []testMode{http1Mode, http2Mode})
+}
+func testServerLimitsConcurrency(t *testing.T, mode testMode) {
+	lt := newLimitsTest(t, mode, &ServerLimits{
+		MaxConcurrentRequests: 2,
+		RetryAfter:            1500 * time.Millisecond,
+	})
+	done1 := lt.block("a")
+	done2 := lt.block("b")
+	if code, retryAfter := lt.get("", "c"); code != StatusServiceUnavailable || retryAfter != "2" {
+		t.Errorf("request beyond limit: status %d, Retry-After %q; want 503, 2", code, retryAfter)
+	}
+	lt.release <- struct{}{}
+	<-done1
+	if code, _ := lt.get("", "c"); code != 200 {
+		t.Errorf("request after another finished: status %d, want 200", code)
+	}
+	lt.release <- struct{}{}
+	<-done2
+	lt.wantReasons(LimitServerConcurrency)
+}
+
+func TestServerLimitsClientConcurrency(t *testing.T) {
+	run(t, testServerLimitsClientConcurrency, []testMode{http1Mode, http2Mode})
+}
+func testServerLimitsClientConcurrency(t *testing.T, mode testMode) {
+	lt := newLimitsTest(t, mode, &ServerLimits{
+		MaxConcurrentRequestsPerClient: 1,
+		ClientKey: func(r *Request) string {
+			return r.Header.Get("X-Client")
+		},
+		StatusCode: StatusServiceUnavailable,
+	})
+	done := lt.block("a")
+	if code, retryAfter := lt.get("", "a"); code != StatusServiceUnavailable || retryAfter != "1" {
+		t.Errorf("request beyond client limit: status %d, Retry-After %q; want 503, 1", code, retryAfter)
+	}
+	if code, _ := lt.get("", "b"); code != 200 {
+		t.Errorf("request from another client: status %d, want 200", code)
+	}
+	lt.release <- struct{}{}
+	<-done
+	if code, _ := lt.get("", "a"); code != 200 {
+		t.Errorf("request after client's request finished: status %d, want 200", code)
+	}
+	lt.wantReasons(LimitClientConcurrency)
+}
+
+// A rejected HTTP/2 request does not shut down its connection.
+func TestServerLimitsRejectKeepsConn(t *testing.T) {
+	run(t, testServerLimitsRejectKeepsConn, []testMode{http2Mode})
+}
+func testServerLimitsRejectKeepsConn(t *testing.T, mode testMode) {
+	lt := newLimitsTest(t, mode, &ServerLimits{
+		MaxConcurrentRequestsPerClient: 1,
+	})
+	done := lt.block("")
+	if code, _ := lt.get("", ""); code != StatusTooManyRequests {
+		t.Errorf("request beyond client limit: status %d, want 429", code)
+	}
+	lt.release <- struct{}{}
+	<-done
+
+	var reused bool
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+	}
+	req, _ := NewRequestWithContext(httptrace.WithClientTrace(t.Context(), trace), "GET", lt.cst.ts.URL, nil)
+	res, err := lt.cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != 200 {
+		t.Errorf("request after rejection: status %d, want 200", res.StatusCode)
+	}
+	if !reused {
+		t.Errorf("request after rejection used a new connection, want the connection to be reused")
+	}
+	lt.wantReasons(LimitClientConcurrency)
+}
+
+func TestServerLimitsClientRate(t *testing.T) {
+	run(t, testServerLimitsClientRate, []testMode{http1Mode, http2Mode})
+}
+func testServerLimitsClientRate(t *testing.T, mode testMode) {
+	lt := newLimitsTest(t, mode, &ServerLimits{
+		ClientRate:  0.001,
+		ClientBurst: 2,
+	})
+	for i := range 2 {
+		if code, _ := lt.get("", ""); code != 200 {
+			t.Errorf("request %d within burst: status %d, want 200", i, code)
+		}
+	}
+	code, retryAfter := lt.get("", "")
+	if code != StatusTooManyRequests || retryAfter != "1000" {
+		t.Errorf("request beyond rate: status %d, Retry-After %q; want 429, 1000", code, retryAfter)
+	}
+	lt.wantReasons(LimitClientRate)
+}
+
+// ServerLimits.MaxConcurrentStreams limits the streams on an HTTP/2 connection.
+func TestServerLimitsMaxConcurrentStreams(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		dt := newTransportDialTester(t, http2UnencryptedMode, func(srv *Server) {
+			srv.Limits = &ServerLimits{MaxConcurrentStreams: 1}
+		})
+
+		rt1 := dt.roundTrip()
+		c1 := dt.wantDial()
+		c1.finish(nil)
+		rt1.wantDone(c1, "HTTP/2.0")
+
+		// The second request cannot use the first connection.
+		rt2 := dt.roundTrip()
+		c2 := dt.wantDial()
+		c2.finish(nil)
+		rt2.wantDone(c2, "HTTP/2.0")
+
+		rt1.finish()
+		rt2.finish()
+	})
+}
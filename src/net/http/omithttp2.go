@@ -23,6 +23,12 @@ func (s *Server) setHTTP2Config(conf http2ExternalServerConfig) {}
 func (s *Server) serveHTTP2Conn(ctx context.Context, nc net.Conn, h Handler, sawClientPreface bool, upgradeReq *Request, settings []byte) {
 }
 
+func http2ResponseStats(w ResponseWriter) (status int, written int64, ok bool) {
+	return 0, 0, false
+}
+
+func http2IsRetryableError(err error) bool { return false }
+
 func (t *Transport) configureHTTP2(protocols Protocols) {}
 func (t *Transport) http2AddConn(scheme, authority string, nc net.Conn) (RoundTripper, error) {
 	return nil, errors.ErrUnsupported
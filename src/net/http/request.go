@@ -341,6 +341,11 @@ type Request struct {
 	pat         *pattern          // the pattern that matched
 	matches     []string          // values for the matching wildcards in pat
 	otherValues map[string]string // for calls to SetPathValue that don't match a wildcard
+
+	// accessLog is the entry for the access log of the server that
+	// received the request, if any. It is shared by copies of the
+	// request, so that a ServeMux can record the matching pattern.
+	accessLog *AccessLogEntry
 }
 
 // Context returns the request's context. To change the context, use
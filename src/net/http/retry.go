@@ -0,0 +1,217 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+)
+
+// RetryTransport is a [RoundTripper] that retries requests that fail
+// with a transient error or with a response indicating a temporary condition,
+// waiting between attempts with exponential backoff and jitter.
+//
+// RetryTransport only retries requests that are idempotent and can be
+// sent again. A request is idempotent if its method is GET, HEAD, OPTIONS,
+// TRACE, PUT, or DELETE, or if its [Header] map contains an
+// "Idempotency-Key" or "X-Idempotency-Key" entry. A request can be sent
+// again if it has no body or if its [Request.GetBody] field is set,
+// in which case GetBody provides the body of each retry.
+//
+// If a 429 Too Many Requests or 503 Service Unavailable response has a
+// Retry-After header, RetryTransport waits for the time it specifies
+// instead. If that is longer than MaxDelay, the response is returned
+// without retrying.
+//
+// Before waiting to retry a request, RetryTransport calls the WillRetry
+// hook of the request's [httptrace.ClientTrace], if any. The body of the
+// response of the failed attempt is closed before the next attempt.
+// If the request's context is done while waiting, RoundTrip returns the
+// context's error.
+type RetryTransport struct {
+	// Transport is the RoundTripper used to send each attempt.
+	// If nil, DefaultTransport is used.
+	Transport RoundTripper
+
+	// MaxRetries is the maximum number of times a request is retried
+	// after the first attempt. If zero, 3 retries are made.
+	// If negative, requests are not retried.
+	MaxRetries int
+
+	// MinDelay is the delay before the first retry, which doubles
+	// with each subsequent retry. If zero, 100ms is used.
+	// The actual delay is chosen at random between half and
+	// all of the computed delay.
+	MinDelay time.Duration
+
+	// MaxDelay is the maximum delay between attempts.
+	// If zero, 10s is used.
+	MaxDelay time.Duration
+
+	// ShouldRetry reports whether a request should be retried after an
+	// attempt returned the given response or error. It is only called
+	// for requests that can be retried.
+	// If nil, a request is retried if the attempt failed with a transient
+	// error and the request's context is not done, or if the response has
+	// status 429, 502, 503, or 504. The transient errors are timeouts,
+	// reset or refused connections, and HTTP/2 refused streams and
+	// GOAWAY frames.
+	ShouldRetry func(req *Request, resp *Response, err error) bool
+}
+
+// RoundTrip implements [RoundTripper].
+func (t *RetryTransport) RoundTrip(req *Request) (*Response, error) {
+	rt := t.Transport
+	if rt == nil {
+		rt = DefaultTransport
+	}
+	if !req.isRetryable() || t.MaxRetries < 0 {
+		return rt.RoundTrip(req)
+	}
+	maxRetries := t.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 3
+	}
+	ctx := req.Context()
+	trace := httptrace.ContextClientTrace(ctx)
+
+	r := req
+	for attempt := 1; ; attempt++ {
+		resp, err := rt.RoundTrip(r)
+		if attempt > maxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		delay := t.backoff(attempt)
+		if resp != nil && (resp.StatusCode == StatusTooManyRequests || resp.StatusCode == StatusServiceUnavailable) {
+			if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				if d > t.maxDelay() {
+					return resp, err
+				}
+				delay = d
+			}
+		}
+
+		var next *Request
+		if req.GetBody != nil {
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				// Return the failed attempt rather than the error
+				// from GetBody, which the caller did not ask for.
+				return resp, err
+			}
+			next = new(Request)
+			*next = *req
+			next.Body = body
+		} else {
+			next = req
+		}
+
+		if trace != nil && trace.WillRetry != nil {
+			info := httptrace.RetryInfo{Attempt: attempt, Err: err, Delay: delay}
+			if resp != nil {
+				info.StatusCode = resp.StatusCode
+			}
+			trace.WillRetry(info)
+		}
+		if resp != nil {
+			// Read some of the body so the connection can be reused.
+			io.CopyN(io.Discard, resp.Body, 4<<10)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if next != req {
+				next.closeBody()
+			}
+			return nil, context.Cause(ctx)
+		case <-timer.C:
+		}
+		r = next
+	}
+}
+
+// isRetryable reports whether r is idempotent and can be sent again.
+func (r *Request) isRetryable() bool {
+	if r.Body != nil && r.Body != NoBody && r.GetBody == nil {
+		return false
+	}
+	switch valueOrDefault(r.Method, "GET") {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return r.Header.has("Idempotency-Key") || r.Header.has("X-Idempotency-Key")
+}
+
+func (t *RetryTransport) shouldRetry(req *Request, resp *Response, err error) bool {
+	if t.ShouldRetry != nil {
+		return t.ShouldRetry(req, resp, err)
+	}
+	if err != nil {
+		return req.Context().Err() == nil && isTransientError(err)
+	}
+	switch resp.StatusCode {
+	case StatusTooManyRequests, StatusBadGateway, StatusServiceUnavailable, StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransientError reports whether a request that failed with err
+// may succeed if it is sent again.
+func isTransientError(err error) bool {
+	if ne, ok := errors.AsType[net.Error](err); ok && ne.Timeout() {
+		return true
+	}
+	return isConnResetOrRefused(err) || http2IsRetryableError(err)
+}
+
+func (t *RetryTransport) maxDelay() time.Duration {
+	if t.MaxDelay > 0 {
+		return t.MaxDelay
+	}
+	return 10 * time.Second
+}
+
+// backoff returns the delay after the given failed attempt.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	d := t.MinDelay
+	if d <= 0 {
+		d = 100 * time.Millisecond
+	}
+	maxDelay := t.maxDelay()
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, maxDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses the value of a Retry-After header,
+// which is either a number of seconds or an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(min(secs, 1<<32)) * time.Second, true
+	}
+	t, err := ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(t), 0), true
+}
@@ -0,0 +1,18 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !plan9
+
+package http
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isConnResetOrRefused reports whether err is a connection reset or
+// a refused connection.
+func isConnResetOrRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
@@ -0,0 +1,11 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+// isConnResetOrRefused reports whether err is a connection reset or
+// a refused connection. Plan 9 has no errors to identify them by.
+func isConnResetOrRefused(err error) bool {
+	return false
+}
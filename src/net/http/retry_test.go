@@ -0,0 +1,197 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	. "net/http"
+	"net/http/httptrace"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	run(t, testRetryTransport, http3SkippedMode)
+}
+func testRetryTransport(t *testing.T, mode testMode) {
+	var calls atomic.Int32
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		n := calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != r.URL.Query().Get("body") {
+			t.Errorf("attempt %d: body = %q", n, body)
+		}
+		if r.URL.Query().Get("fail") == "all" || n < 3 {
+			w.WriteHeader(StatusServiceUnavailable)
+			io.WriteString(w, "unavailable")
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+
+	var retries []httptrace.RetryInfo
+	trace := &httptrace.ClientTrace{
+		WillRetry: func(info httptrace.RetryInfo) { retries = append(retries, info) },
+	}
+	ctx := httptrace.WithClientTrace(context.Background(), trace)
+	c := &Client{Transport: &RetryTransport{
+		Transport: cst.tr,
+		MinDelay:  time.Millisecond,
+	}}
+
+	for _, tt := range []struct {
+		name       string
+		method     string
+		query      string
+		body       string
+		header     Header
+		wantCalls  int32
+		wantStatus int
+	}{
+		{name: "GET", method: "GET", wantCalls: 3, wantStatus: 200},
+		{name: "PUT with body", method: "PUT", query: "body=data", body: "data", wantCalls: 3, wantStatus: 200},
+		{name: "POST", method: "POST", query: "body=data", body: "data", wantCalls: 1, wantStatus: 503},
+		{name: "POST with Idempotency-Key", method: "POST", query: "body=data", body: "data",
+			header: Header{"Idempotency-Key": {"k"}}, wantCalls: 3, wantStatus: 200},
+		{name: "exhausted", method: "GET", query: "fail=all", wantCalls: 4, wantStatus: 503},
+	} {
+		calls.Store(0)
+		retries = nil
+		var body io.Reader
+		if tt.body != "" {
+			body = strings.NewReader(tt.body)
+		}
+		req, _ := NewRequestWithContext(ctx, tt.method, cst.ts.URL+"/?"+tt.query, body)
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+		res, err := c.Do(req)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		b, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		if got := calls.Load(); got != tt.wantCalls || res.StatusCode != tt.wantStatus {
+			t.Errorf("%s: %d calls, status %d (%q); want %d calls, status %d", tt.name, got, res.StatusCode, b, tt.wantCalls, tt.wantStatus)
+		}
+		if len(retries) != int(tt.wantCalls-1) {
+			t.Errorf("%s: WillRetry called %d times, want %d", tt.name, len(retries), tt.wantCalls-1)
+		}
+		for i, info := range retries {
+			if info.Attempt != i+1 || info.StatusCode != 503 || info.Err != nil || info.Delay <= 0 {
+				t.Errorf("%s: retry %d: %+v", tt.name, i, info)
+			}
+		}
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	cst := newClientServerTest(t, http1Mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", r.URL.Query().Get("after"))
+			w.WriteHeader(StatusTooManyRequests)
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	var delay time.Duration
+	trace := &httptrace.ClientTrace{
+		WillRetry: func(info httptrace.RetryInfo) { delay = info.Delay },
+	}
+	ctx := httptrace.WithClientTrace(context.Background(), trace)
+	c := &Client{Transport: &RetryTransport{
+		Transport: cst.tr,
+		MinDelay:  time.Millisecond,
+		MaxDelay:  2 * time.Second,
+	}}
+
+	// A Retry-After within MaxDelay is honored.
+	req, _ := NewRequestWithContext(ctx, "GET", cst.ts.URL+"/?after=1", nil)
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != 200 || delay != time.Second {
+		t.Errorf("Retry-After 1: status %d, delay %v; want 200, 1s", res.StatusCode, delay)
+	}
+
+	// A Retry-After beyond MaxDelay is returned to the caller.
+	calls.Store(0)
+	req, _ = NewRequestWithContext(ctx, "GET", cst.ts.URL+"/?after=3600", nil)
+	res, err = c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != StatusTooManyRequests || calls.Load() != 1 {
+		t.Errorf("Retry-After 3600: status %d after %d calls; want 429 after 1", res.StatusCode, calls.Load())
+	}
+}
+
+type errorRoundTripper struct {
+	calls int
+	err   error
+}
+
+func (rt *errorRoundTripper) RoundTrip(req *Request) (*Response, error) {
+	rt.calls++
+	return nil, rt.err
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	errFail := &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}
+	rt := &errorRoundTripper{err: errFail}
+	tr := &RetryTransport{Transport: rt, MaxRetries: 2, MinDelay: time.Millisecond}
+	req, _ := NewRequest("GET", "http://example.com/", nil)
+	if _, err := tr.RoundTrip(req); err != errFail || rt.calls != 3 {
+		t.Errorf("RoundTrip = %v after %d calls, want %v after 3", err, rt.calls, errFail)
+	}
+
+	// Errors that are not transient are not retried.
+	errPermanent := errors.New("certificate is not trusted")
+	rt2 := &errorRoundTripper{err: errPermanent}
+	tr2 := &RetryTransport{Transport: rt2, MaxRetries: 2, MinDelay: time.Millisecond}
+	if _, err := tr2.RoundTrip(req); err != errPermanent || rt2.calls != 1 {
+		t.Errorf("RoundTrip with permanent error = %v after %d calls, want 1 call", err, rt2.calls)
+	}
+
+	// A body without GetBody cannot be replayed.
+	rt.calls = 0
+	req, _ = NewRequest("PUT", "http://example.com/", io.NopCloser(strings.NewReader("x")))
+	if _, err := tr.RoundTrip(req); err != errFail || rt.calls != 1 {
+		t.Errorf("RoundTrip without GetBody = %v after %d calls, want 1 call", err, rt.calls)
+	}
+
+	// Canceling the context stops retrying.
+	rt.calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	tr.MinDelay = time.Hour
+	tr.MaxDelay = time.Hour
+	tr.ShouldRetry = func(*Request, *Response, error) bool {
+		cancel()
+		return true
+	}
+	req, _ = NewRequestWithContext(ctx, "GET", "http://example.com/", nil)
+	if _, err := tr.RoundTrip(req); err != context.Canceled || rt.calls != 1 {
+		t.Errorf("RoundTrip with canceled context = %v after %d calls, want Canceled after 1", err, rt.calls)
+	}
+
+	// Negative MaxRetries disables retries.
+	rt.calls = 0
+	tr = &RetryTransport{Transport: rt, MaxRetries: -1}
+	req, _ = NewRequest("GET", "http://example.com/", nil)
+	if _, err := tr.RoundTrip(req); err != errFail || rt.calls != 1 {
+		t.Errorf("RoundTrip with MaxRetries -1 = %v after %d calls, want 1 call", err, rt.calls)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"math/rand/v2"
 	"net"
 	"net/http/internal"
+	"net/http/tracecontext"
 	"net/textproto"
 	"net/url"
 	urlpkg "net/url"
@@ -306,6 +307,11 @@ type conn struct {
 	// by a Handler with the Hijacker interface.
 	// It is guarded by mu.
 	hijackedv bool
+
+	// hijackedc is the connection returned by Hijack when the
+	// server has an AccessLog.
+	// It is guarded by mu.
+	hijackedc *hijackedConn
 }
 
 func (c *conn) hijacked() bool {
@@ -314,6 +320,14 @@ func (c *conn) hijacked() bool {
 	return c.hijackedv
 }
 
+// hijackedConn returns the connection returned by Hijack,
+// if the connection has been hijacked and the server has an AccessLog.
+func (c *conn) hijackedConn() *hijackedConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hijackedc
+}
+
 // c.mu must be held.
 func (c *conn) hijackLocked() (rwc net.Conn, buf *bufio.ReadWriter, err error) {
 	if c.hijackedv {
@@ -330,10 +344,14 @@ func (c *conn) hijackLocked() (rwc net.Conn, buf *bufio.ReadWriter, err error) {
 			return nil, nil, fmt.Errorf("unexpected Peek failure reading buffered byte: %v", err)
 		}
 	}
+	c.setState(rwc, StateHijacked, runHooks)
+	if c.server.AccessLog != nil {
+		// Count the bytes written for the access log.
+		c.hijackedc = &hijackedConn{Conn: rwc}
+		rwc = c.hijackedc
+	}
 	c.bufw.Reset(rwc)
 	buf = bufio.NewReadWriter(c.bufr, c.bufw)
-
-	c.setState(rwc, StateHijacked, runHooks)
 	return
 }
 
@@ -2897,6 +2915,9 @@ func (mux *ServeMux) ServeHTTP(w ResponseWriter, r *Request) {
 		h, _ = mux.mux121.findHandler(r)
 	} else {
 		h, r.Pattern, r.pat, r.matches = mux.findHandler(r)
+		if r.accessLog != nil && r.Pattern != "" {
+			r.accessLog.Pattern = r.Pattern
+		}
 	}
 	h.ServeHTTP(w, r)
 }
@@ -3167,6 +3188,32 @@ type Server struct {
 	// prioritization.
 	DisableClientPriority bool
 
+	// Limits optionally limits the number of requests the server
+	// handles at once and the rate of requests from each client.
+	// See [ServerLimits]. Limits must not be modified after the
+	// server starts.
+	Limits *ServerLimits
+
+	// AccessLog, if non-nil, is called with an entry describing each
+	// request the server handles, after its handler returns or panics,
+	// or after a connection hijacked by the handler is closed.
+	// See [AccessLogEntry]. It is called concurrently from multiple
+	// goroutines, and must not retain the entry. The net/http/httplog
+	// package provides AccessLog functions that write [log/slog] records.
+	//
+	// When AccessLog is set, the net.Conn returned by Hijack counts the
+	// bytes written to it, and is not of the connection's underlying
+	// type, such as *net.TCPConn or *tls.Conn.
+	AccessLog func(*AccessLogEntry)
+
+	// TraceContext specifies whether to accept W3C trace context.
+	// If true, and a request has a valid Traceparent header, the server
+	// adds the span context in its Traceparent and Tracestate headers
+	// to the request's context, where it can be retrieved with
+	// [tracecontext.FromContext]. The headers come from the client, and
+	// should only be trusted as far as the client is.
+	TraceContext bool
+
 	inShutdown atomic.Bool // true when server is in shutdown
 
 	disableKeepAlives atomic.Bool
@@ -3182,6 +3229,9 @@ type Server struct {
 	h2IdleTimeout time.Duration
 	h3Server      http3Server
 
+	limiterOnce  sync.Once
+	limiterState *serverLimiter
+
 	listenerGroup sync.WaitGroup
 }
 
@@ -3412,6 +3462,24 @@ func (sh serverHandler) ServeHTTP(rw ResponseWriter, req *Request) {
 	if !sh.srv.DisableGeneralOptionsHandler && req.RequestURI == "*" && req.Method == "OPTIONS" {
 		handler = globalOptionsHandler{}
 	}
+	var entry *AccessLogEntry
+	if log := sh.srv.AccessLog; log != nil {
+		entry = startAccessLog(req)
+		defer finishAccessLog(log, rw, entry)
+	}
+	if sh.srv.Limits != nil {
+		l := sh.srv.limiter()
+		key, ok := l.admit(rw, req)
+		if !ok {
+			return
+		}
+		defer l.done(key)
+	}
+	if sh.srv.TraceContext {
+		if sc, ok := headerSpanContext(req.Header); ok {
+			req.ctx = tracecontext.NewContext(req.ctx, sc)
+		}
+	}
 
 	defer func() {
 		if req.MultipartForm != nil {
@@ -3419,6 +3487,9 @@ func (sh serverHandler) ServeHTTP(rw ResponseWriter, req *Request) {
 		}
 	}()
 	handler.ServeHTTP(rw, req)
+	if entry != nil {
+		entry.handled = true
+	}
 }
 
 func badServeHTTP(serverHandler, ResponseWriter, *Request)
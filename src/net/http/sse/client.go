@@ -0,0 +1,164 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// defaultRetry is the default reconnection time of a Client.
+const defaultRetry = 3 * time.Second
+
+// A Client reads the events of event streams, reconnecting to a
+// stream when its connection is lost, as done by the EventSource API.
+type Client struct {
+	// HTTPClient is used to make requests.
+	// If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Retry is the time to wait before reconnecting to a stream,
+	// until the stream sets it with a "retry" field.
+	// If zero, 3 seconds is used.
+	Retry time.Duration
+}
+
+// Events returns an iterator over the events of the event stream
+// requested by req.
+//
+// Each request sent by the iterator is a clone of req with an
+// Accept header of text/event-stream. When the response body ends or
+// reading from it fails, the iterator waits for the reconnection time
+// and requests the stream again, with a Last-Event-ID header holding
+// the last event ID set by the stream, if any. If req has a body,
+// its GetBody field must be set for the iterator to reconnect.
+//
+// Errors sending a request or reading a response are yielded, after
+// which the iterator reconnects unless the loop ends. Iteration stops
+// after yielding an error for a response whose status is not
+// 200 OK or whose media type is not text/event-stream, or when the
+// context of req is done. A 204 No Content response ends the stream
+// without an error.
+func (c *Client) Events(req *http.Request) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		client := c.HTTPClient
+		if client == nil {
+			client = http.DefaultClient
+		}
+		retry := c.Retry
+		if retry <= 0 {
+			retry = defaultRetry
+		}
+		ctx := req.Context()
+		lastEventID := req.Header.Get("Last-Event-ID")
+		for attempt := 0; ; attempt++ {
+			if attempt > 0 {
+				if !sleep(ctx, retry) {
+					yield(Event{}, context.Cause(ctx))
+					return
+				}
+			}
+			r, err := c.newRequest(req, attempt, lastEventID)
+			if err != nil {
+				yield(Event{}, err)
+				return
+			}
+			resp, err := client.Do(r)
+			if err != nil {
+				if !yield(Event{}, err) || ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+			if err := checkResponse(resp); err != nil {
+				resp.Body.Close()
+				if err != errNoContent {
+					yield(Event{}, err)
+				}
+				return
+			}
+
+			p := newParser(resp.Body)
+			p.lastEventID = lastEventID
+			for {
+				e, err := p.next()
+				lastEventID = p.lastEventID
+				if p.retry > 0 {
+					retry = p.retry
+				}
+				if err == io.EOF {
+					break
+				}
+				if !yield(e, err) || err != nil && ctx.Err() != nil {
+					resp.Body.Close()
+					return
+				}
+				if err != nil {
+					break
+				}
+			}
+			resp.Body.Close()
+		}
+	}
+}
+
+// newRequest returns the request for the given attempt
+// to read the stream requested by req.
+func (c *Client) newRequest(req *http.Request, attempt int, lastEventID string) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, errors.New("sse: cannot reconnect: request body cannot be resent")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	r.Header.Set("Accept", ContentType)
+	if _, ok := r.Header["Cache-Control"]; !ok {
+		r.Header.Set("Cache-Control", "no-store")
+	}
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return r, nil
+}
+
+var errNoContent = errors.New("sse: no content")
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNoContent {
+		return errNoContent
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sse: unexpected response status %s", resp.Status)
+	}
+	ct := resp.Header.Get("Content-Type")
+	if mt, _, err := mime.ParseMediaType(ct); err != nil || mt != ContentType {
+		return fmt.Errorf("sse: unexpected response content type %q", ct)
+	}
+	return nil
+}
+
+// sleep waits for d, and reports whether it did so
+// before ctx was done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
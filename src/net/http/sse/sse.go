@@ -0,0 +1,186 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sse implements server-sent events, as specified by the
+// HTML Living Standard's "Server-sent events" section.
+//
+// An event stream is an HTTP response with the media type
+// text/event-stream whose body is a sequence of events. On the server,
+// a [Writer] writes events to an [http.ResponseWriter]. On the client,
+// [Read] parses the events in a response body, and a [Client] reads
+// the events of a stream, reconnecting when the connection is lost.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An Event is a server-sent event.
+type Event struct {
+	// Type is the event type, from the "event" field.
+	// An empty Type is equivalent to "message".
+	Type string
+
+	// ID is the event ID, from the "id" field.
+	// For events read from a stream, ID is the last event ID set by the
+	// stream, which may have been set by an earlier event.
+	ID string
+
+	// Data is the event data, from the "data" fields.
+	// Lines in the data are separated by "\n".
+	Data string
+
+	// Retry is the reconnection time, from the "retry" field.
+	// Zero means the field is not present.
+	Retry time.Duration
+}
+
+// ContentType is the media type of an event stream.
+const ContentType = "text/event-stream"
+
+// Read returns an iterator over the events in the event stream r.
+// Iteration stops at the end of r, or after yielding an error
+// reading from r. An incomplete event at the end of r is discarded.
+//
+// As in the EventSource API, a block of fields with no "data" field
+// is not an event: it may set the last event ID, which is reported
+// in the ID of later events, but it is not yielded.
+func Read(r io.Reader) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		p := newParser(r)
+		for {
+			e, err := p.next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(e, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// A parser reads the events in an event stream.
+type parser struct {
+	br          *bufio.Reader
+	line        []byte
+	started     bool // whether the byte order mark has been checked
+	skipLF      bool // whether the last line ended with a CR
+	lastEventID string
+	retry       time.Duration // last reconnection time, or 0
+}
+
+func newParser(r io.Reader) *parser {
+	return &parser{br: bufio.NewReader(r)}
+}
+
+// next returns the next event in the stream.
+func (p *parser) next() (Event, error) {
+	var (
+		typ     string
+		data    strings.Builder
+		hasData bool
+		retry   time.Duration
+	)
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+		if len(line) == 0 {
+			if !hasData {
+				typ, retry = "", 0
+				continue
+			}
+			return Event{
+				Type:  typ,
+				ID:    p.lastEventID,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+				Retry: retry,
+			}, nil
+		}
+		if line[0] == ':' {
+			continue // comment
+		}
+		name, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(name) {
+		case "event":
+			typ = string(value)
+		case "data":
+			data.Write(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				p.lastEventID = string(value)
+			}
+		case "retry":
+			if ms, ok := parseDigits(value); ok {
+				retry = time.Duration(ms) * time.Millisecond
+				p.retry = retry
+			}
+		}
+	}
+}
+
+// parseDigits parses a non-empty string of ASCII digits.
+func parseDigits(b []byte) (int64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		// Too large; use the maximum duration instead.
+		return int64(1<<63-1) / int64(time.Millisecond), true
+	}
+	return min(n, int64(1<<63-1)/int64(time.Millisecond)), true
+}
+
+// readLine reads a line ending in CRLF, LF, or CR.
+// The returned slice is valid until the next call.
+func (p *parser) readLine() ([]byte, error) {
+	p.line = p.line[:0]
+	for {
+		c, err := p.br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if p.skipLF {
+			p.skipLF = false
+			if c == '\n' {
+				continue
+			}
+		}
+		if !p.started {
+			p.started = true
+			// Skip a UTF-8 byte order mark at the start of the stream.
+			if c == 0xEF {
+				if b, err := p.br.Peek(2); err == nil && b[0] == 0xBB && b[1] == 0xBF {
+					p.br.Discard(2)
+					continue
+				}
+			}
+		}
+		switch c {
+		case '\r':
+			p.skipLF = true
+			return p.line, nil
+		case '\n':
+			return p.line, nil
+		}
+		p.line = append(p.line, c)
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/sse"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func readAll(t *testing.T, s string) []sse.Event {
+	t.Helper()
+	var events []sse.Event
+	for e, err := range sse.Read(strings.NewReader(s)) {
+		if err != nil {
+			t.Fatalf("Read(%q): %v", s, err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestRead(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		input string
+		want  []sse.Event
+	}{
+		{
+			name:  "simple",
+			input: "data: hello\n\n",
+			want:  []sse.Event{{Data: "hello"}},
+		},
+		{
+			name:  "multiline",
+			input: "data: a\ndata:b\ndata:  c\n\n",
+			want:  []sse.Event{{Data: "a\nb\n c"}},
+		},
+		{
+			name:  "fields",
+			input: "event: update\nid: 7\nretry: 1500\ndata: x\n\n",
+			want:  []sse.Event{{Type: "update", ID: "7", Data: "x", Retry: 1500 * time.Millisecond}},
+		},
+		{
+			name:  "id persists",
+			input: "id: 1\ndata: a\n\ndata: b\n\nid\ndata: c\n\n",
+			want:  []sse.Event{{ID: "1", Data: "a"}, {ID: "1", Data: "b"}, {Data: "c"}},
+		},
+		{
+			name:  "id with NUL ignored",
+			input: "id: 1\ndata: a\n\nid: 2\x00\ndata: b\n\n",
+			want:  []sse.Event{{ID: "1", Data: "a"}, {ID: "1", Data: "b"}},
+		},
+		{
+			name:  "block without data",
+			input: "event: x\nid: 5\n\ndata: a\n\n",
+			want:  []sse.Event{{ID: "5", Data: "a"}},
+		},
+		{
+			name:  "empty data",
+			input: "data\n\ndata:\ndata:\n\n",
+			want:  []sse.Event{{Data: ""}, {Data: "\n"}},
+		},
+		{
+			name:  "comments and unknown fields",
+			input: ": comment\nfoo: bar\ndata: a\n:\n\n",
+			want:  []sse.Event{{Data: "a"}},
+		},
+		{
+			name:  "line endings",
+			input: "data: a\r\ndata: b\rdata: c\n\r\ndata: d\r\r",
+			want:  []sse.Event{{Data: "a\nb\nc"}, {Data: "d"}},
+		},
+		{
+			name:  "byte order mark",
+			input: "\ufeffdata: a\n\n",
+			want:  []sse.Event{{Data: "a"}},
+		},
+		{
+			name:  "invalid retry",
+			input: "retry: 1s\ndata: a\n\n",
+			want:  []sse.Event{{Data: "a"}},
+		},
+		{
+			name:  "incomplete event discarded",
+			input: "data: a\n\ndata: b\n",
+			want:  []sse.Event{{Data: "a"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readAll(t, tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := sse.NewWriter(rec, nil)
+	events := []sse.Event{
+		{Data: "hello"},
+		{Type: "update", ID: "42", Data: "line 1\nline 2\r\nline 3\rline 4"},
+		{Data: " leading space", Retry: 2 * time.Second},
+		{Data: ""},
+	}
+	for _, e := range events {
+		if err := w.Send(e); err != nil {
+			t.Fatalf("Send(%+v): %v", e, err)
+		}
+	}
+	if err := w.Comment("note\nmore"); err != nil {
+		t.Fatal(err)
+	}
+	const want = "data: hello\n\n" +
+		"id: 42\nevent: update\ndata: line 1\ndata: line 2\ndata: line 3\ndata: line 4\n\n" +
+		"retry: 2000\ndata:  leading space\n\n" +
+		"data: \n\n" +
+		": note\n: more\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body:\n%q\nwant:\n%q", got, want)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !rec.Flushed {
+		t.Errorf("response not flushed")
+	}
+
+	// Reading the stream returns the events.
+	events[1].Data = "line 1\nline 2\nline 3\nline 4"
+	events[3].ID = "42"
+	events[2].ID = "42"
+	if got := readAll(t, rec.Body.String()); !reflect.DeepEqual(got, events) {
+		t.Errorf("events read:\n%+v\nwant:\n%+v", got, events)
+	}
+
+	for _, e := range []sse.Event{
+		{Type: "a\nb"},
+		{ID: "a\rb"},
+		{ID: "a\x00b"},
+	} {
+		if err := w.Send(e); err != sse.ErrInvalidEvent {
+			t.Errorf("Send(%+v) = %v, want ErrInvalidEvent", e, err)
+		}
+	}
+
+	w.Close()
+	if err := w.Send(sse.Event{Data: "x"}); err != sse.ErrWriterClosed {
+		t.Errorf("Send after Close = %v, want ErrWriterClosed", err)
+	}
+}
+
+// lockedRecorder is a ResponseRecorder that is safe for concurrent use.
+type lockedRecorder struct {
+	mu sync.Mutex
+	*httptest.ResponseRecorder
+}
+
+func (r *lockedRecorder) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Write(b)
+}
+
+func (r *lockedRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Body.String()
+}
+
+func TestWriterKeepAlive(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		rec := &lockedRecorder{ResponseRecorder: httptest.NewRecorder()}
+		w := sse.NewWriter(rec, &sse.WriterOptions{KeepAlive: 10 * time.Second})
+		defer w.Close()
+
+		time.Sleep(25 * time.Second)
+		synctest.Wait()
+		if got, want := rec.body(), ":\n\n:\n\n"; got != want {
+			t.Errorf("after 25s, body = %q, want %q", got, want)
+		}
+
+		// Sending an event postpones the next keep-alive.
+		time.Sleep(4 * time.Second)
+		w.Send(sse.Event{Data: "x"})
+		time.Sleep(9 * time.Second)
+		synctest.Wait()
+		if got, want := rec.body(), ":\n\n:\n\ndata: x\n\n"; got != want {
+			t.Errorf("after event, body = %q, want %q", got, want)
+		}
+
+		w.Close()
+		time.Sleep(time.Minute)
+		if got, want := rec.body(), ":\n\n:\n\ndata: x\n\n"; got != want {
+			t.Errorf("after Close, body = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestClientReconnect(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		lastEventIDs []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := len(lastEventIDs)
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		mu.Unlock()
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		switch n {
+		case 0:
+			w := sse.NewWriter(rw, nil)
+			w.Send(sse.Event{ID: "1", Data: "one", Retry: time.Millisecond})
+			w.Send(sse.Event{ID: "2", Data: "two"})
+		case 1:
+			w := sse.NewWriter(rw, nil)
+			w.Send(sse.Event{ID: "3", Data: "three"})
+		default:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	c := &sse.Client{HTTPClient: ts.Client()}
+	var data []string
+	for e, err := range c.Events(req) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, e.ID+":"+e.Data)
+	}
+	if want := []string{"1:one", "2:two", "3:three"}; !reflect.DeepEqual(data, want) {
+		t.Errorf("events = %q, want %q", data, want)
+	}
+	if want := []string{"", "2", "3"}; !reflect.DeepEqual(lastEventIDs, want) {
+		t.Errorf("Last-Event-ID headers = %q, want %q", lastEventIDs, want)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/status":
+			http.Error(w, "no", http.StatusForbidden)
+		case "/type":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, "{}")
+		}
+	}))
+	defer ts.Close()
+
+	c := &sse.Client{HTTPClient: ts.Client(), Retry: time.Millisecond}
+	for _, path := range []string{"/status", "/type"} {
+		req, _ := http.NewRequest("GET", ts.URL+path, nil)
+		var errs []error
+		for _, err := range c.Events(req) {
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || errs[0] == nil {
+			t.Errorf("%s: errors %v, want one error", path, errs)
+		}
+	}
+
+	// Connection errors are yielded and the client reconnects,
+	// until the loop ends or the context is done.
+	ts.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	n := 0
+	for _, err := range c.Events(req) {
+		if err == nil {
+			t.Fatal("got event from closed server")
+		}
+		if n++; n == 3 {
+			cancel()
+		}
+		if errors.Is(err, context.Canceled) {
+			break
+		}
+		if n > 10 {
+			t.Fatal("iteration did not stop after context was canceled")
+		}
+	}
+	if n < 3 {
+		t.Errorf("got %d errors, want at least 3", n)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sse
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidEvent is returned by [Writer.Send] for an event whose
+// Type or ID contains a line break, or whose ID contains a NUL byte.
+var ErrInvalidEvent = errors.New("sse: invalid event")
+
+// ErrWriterClosed is returned by the methods of a [Writer]
+// that has been closed.
+var ErrWriterClosed = errors.New("sse: writer closed")
+
+// WriterOptions are options for a [Writer].
+type WriterOptions struct {
+	// KeepAlive is the maximum time the Writer waits without writing
+	// before it writes a comment, to keep the connection from being
+	// closed by intermediaries as idle.
+	// If zero, the Writer does not write keep-alive comments.
+	KeepAlive time.Duration
+}
+
+// A Writer writes an event stream to an [http.ResponseWriter].
+//
+// Each method of a Writer writes to the underlying ResponseWriter and
+// flushes it using an [http.ResponseController]. After a write fails,
+// the methods of the Writer return the error.
+//
+// A Writer is safe for concurrent use by multiple goroutines.
+type Writer struct {
+	rw http.ResponseWriter
+	rc *http.ResponseController
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+	timer  *time.Timer   // keep-alive timer, or nil
+	idle   time.Duration // keep-alive interval
+}
+
+// NewWriter returns a Writer writing an event stream to w.
+//
+// NewWriter sets the Content-Type header to text/event-stream and the
+// Cache-Control header to no-cache, unless it is already set, and writes
+// the response header with status 200 OK.
+//
+// If opts.KeepAlive is set, the Writer writes keep-alive comments from
+// another goroutine, and the caller must call [Writer.Close] before the
+// handler returns.
+// If opts is nil, the default options are used.
+func NewWriter(w http.ResponseWriter, opts *WriterOptions) *Writer {
+	sw := &Writer{rw: w, rc: http.NewResponseController(w)}
+	h := w.Header()
+	h.Set("Content-Type", ContentType)
+	if _, ok := h["Cache-Control"]; !ok {
+		h.Set("Cache-Control", "no-cache")
+	}
+	h.Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+	sw.err = sw.rc.Flush()
+	if opts != nil && opts.KeepAlive > 0 && sw.err == nil {
+		sw.mu.Lock()
+		sw.idle = opts.KeepAlive
+		sw.timer = time.AfterFunc(sw.idle, sw.keepAlive)
+		sw.mu.Unlock()
+	}
+	return sw
+}
+
+// Send writes an event and flushes it to the client.
+// The event's Data is written as one "data" field per line; any of
+// "\r\n", "\n", and "\r" separates lines. A zero Retry is not written.
+func (w *Writer) Send(e Event) error {
+	if strings.ContainsAny(e.Type, "\r\n") || strings.ContainsAny(e.ID, "\r\n\x00") {
+		return ErrInvalidEvent
+	}
+	b := make([]byte, 0, len(e.Data)+64)
+	if e.ID != "" {
+		b = appendField(b, "id", e.ID)
+	}
+	if e.Type != "" {
+		b = appendField(b, "event", e.Type)
+	}
+	if e.Retry > 0 {
+		b = appendField(b, "retry", strconv.FormatInt(e.Retry.Milliseconds(), 10))
+	}
+	b = appendLines(b, "data: ", e.Data)
+	b = append(b, '\n')
+	return w.write(b)
+}
+
+func appendField(b []byte, name, value string) []byte {
+	b = append(b, name...)
+	b = append(b, ": "...)
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// appendLines appends each line of s to b, preceded by prefix.
+// Any of "\r\n", "\n", and "\r" ends a line.
+func appendLines(b []byte, prefix, s string) []byte {
+	for {
+		i := strings.IndexAny(s, "\r\n")
+		if i < 0 {
+			b = append(b, prefix...)
+			b = append(b, s...)
+			return append(b, '\n')
+		}
+		b = append(b, prefix...)
+		b = append(b, s[:i]...)
+		b = append(b, '\n')
+		if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+			i++
+		}
+		s = s[i+1:]
+	}
+}
+
+// Comment writes a comment, which clients ignore, and flushes it.
+// Each line of text is written as a separate comment line.
+func (w *Writer) Comment(text string) error {
+	return w.write(appendLines(nil, ": ", text))
+}
+
+func (w *Writer) write(b []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if _, err := w.rw.Write(b); err != nil {
+		w.fail(err)
+		return err
+	}
+	if err := w.rc.Flush(); err != nil {
+		w.fail(err)
+		return err
+	}
+	if w.timer != nil {
+		w.timer.Reset(w.idle)
+	}
+	return nil
+}
+
+// fail records a write error and stops keep-alives.
+func (w *Writer) fail(err error) {
+	w.err = err
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+func (w *Writer) keepAlive() {
+	w.write([]byte(":\n\n"))
+}
+
+// Close stops the Writer's keep-alive comments and waits for a
+// comment being written to finish. After Close, the methods of the
+// Writer return ErrWriterClosed. Close does not close the connection.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	return nil
+}
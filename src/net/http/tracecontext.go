@@ -0,0 +1,45 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http/tracecontext"
+	"strings"
+)
+
+// headerSpanContext returns the span context in the traceparent
+// and tracestate headers of h, if there is a valid one.
+func headerSpanContext(h Header) (tracecontext.SpanContext, bool) {
+	tp := h[tracecontext.TraceparentHeader]
+	if len(tp) != 1 {
+		return tracecontext.SpanContext{}, false
+	}
+	sc, err := tracecontext.Parse(tp[0], strings.Join(h[tracecontext.TracestateHeader], ","))
+	return sc, err == nil
+}
+
+// withTraceContext returns req, or a copy of req with traceparent and
+// tracestate headers for a new child of the span context in its context.
+// Headers set by the caller take precedence.
+func withTraceContext(req *Request) *Request {
+	sc, ok := tracecontext.FromContext(req.Context())
+	if !ok || !sc.IsValid() {
+		return req
+	}
+	if _, ok := req.Header[tracecontext.TraceparentHeader]; ok {
+		return req
+	}
+	// The parent of the server's span is the span of this request,
+	// not the span that made it.
+	sc = sc.NewChild()
+	r2 := new(Request)
+	*r2 = *req
+	r2.Header = req.Header.Clone()
+	r2.Header.Set(tracecontext.TraceparentHeader, sc.Traceparent())
+	if _, ok := req.Header[tracecontext.TracestateHeader]; !ok && sc.TraceState != "" {
+		r2.Header.Set(tracecontext.TracestateHeader, sc.TraceState)
+	}
+	return r2
+}
@@ -0,0 +1,307 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tracecontext implements the propagation of trace identifiers
+// as defined by the W3C Trace Context specification.
+//
+// A [SpanContext] identifies a trace and a span within it.
+// It is carried between services in the traceparent and tracestate
+// HTTP headers, and within a program in a [context.Context].
+//
+// The net/http package propagates span contexts when enabled:
+// a [net/http.Server] with TraceContext set adds the span context of an
+// incoming request to the request's context, and a [net/http.Transport]
+// with TraceContext set adds the headers for a new child of the span
+// context in a request's context to the outgoing request.
+//
+// The package does not record or export spans; it only
+// propagates identifiers.
+//
+// See https://www.w3.org/TR/trace-context/.
+package tracecontext
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+)
+
+// Header names used to propagate a [SpanContext], in canonical form.
+const (
+	TraceparentHeader = "Traceparent"
+	TracestateHeader  = "Tracestate"
+)
+
+// A TraceID identifies a trace. A valid TraceID has at least one non-zero byte.
+type TraceID [16]byte
+
+// IsValid reports whether t is a valid trace ID.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns t as 32 lowercase hexadecimal digits.
+func (t TraceID) String() string {
+	return string(appendHex(nil, t[:]))
+}
+
+// A SpanID identifies a span within a trace.
+// A valid SpanID has at least one non-zero byte.
+type SpanID [8]byte
+
+// IsValid reports whether s is a valid span ID.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String returns s as 16 lowercase hexadecimal digits.
+func (s SpanID) String() string {
+	return string(appendHex(nil, s[:]))
+}
+
+// Flags are the trace flags of a [SpanContext].
+type Flags byte
+
+// FlagSampled records that the caller may have recorded the trace.
+const FlagSampled Flags = 0x01
+
+// A SpanContext identifies a span and the trace to which it belongs.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Flags   Flags
+
+	// TraceState holds vendor-specific trace information,
+	// in the format of the tracestate header.
+	TraceState string
+}
+
+// New returns a span context for the root span of a new trace,
+// with random trace and span IDs and no flags set.
+func New() SpanContext {
+	var sc SpanContext
+	for !sc.TraceID.IsValid() {
+		putUint64(sc.TraceID[:8], rand.Uint64())
+		putUint64(sc.TraceID[8:], rand.Uint64())
+	}
+	sc.SpanID = newSpanID()
+	return sc
+}
+
+// NewChild returns a span context for a new span in the same trace as sc,
+// with a random span ID. The flags and trace state are copied from sc.
+func (sc SpanContext) NewChild() SpanContext {
+	sc.SpanID = newSpanID()
+	return sc
+}
+
+func newSpanID() SpanID {
+	var s SpanID
+	for !s.IsValid() {
+		putUint64(s[:], rand.Uint64())
+	}
+	return s
+}
+
+func putUint64(b []byte, v uint64) {
+	for i := range 8 {
+		b[i] = byte(v >> (56 - 8*i))
+	}
+}
+
+// IsValid reports whether sc has a valid trace ID and span ID.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled reports whether the [FlagSampled] flag is set in sc.
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// Traceparent returns the value of the traceparent header for sc.
+func (sc SpanContext) Traceparent() string {
+	b := make([]byte, 0, traceparentLen)
+	b = append(b, "00-"...)
+	b = appendHex(b, sc.TraceID[:])
+	b = append(b, '-')
+	b = appendHex(b, sc.SpanID[:])
+	b = append(b, '-')
+	b = appendHex(b, []byte{byte(sc.Flags)})
+	return string(b)
+}
+
+// traceparentLen is the length of a version 00 traceparent header.
+const traceparentLen = len("00-") + 32 + len("-") + 16 + len("-") + 2
+
+var errInvalidTraceparent = errors.New("tracecontext: invalid traceparent")
+
+// Parse parses the values of the traceparent and tracestate headers.
+// It returns an error if traceparent is not valid.
+// If tracestate is not valid, it is discarded, and the returned
+// span context has no trace state.
+//
+// A traceparent with a version later than 00 is accepted if it
+// begins with a valid version 00 traceparent, as the specification requires.
+func Parse(traceparent, tracestate string) (SpanContext, error) {
+	var sc SpanContext
+	s := traceparent
+	if len(s) < traceparentLen || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	version, ok := parseHex(s[0:2])
+	if !ok || version[0] == 0xff {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	if len(s) > traceparentLen && (version[0] == 0 || s[traceparentLen] != '-') {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	traceID, ok1 := parseHex(s[3:35])
+	spanID, ok2 := parseHex(s[36:52])
+	flags, ok3 := parseHex(s[53:55])
+	if !ok1 || !ok2 || !ok3 {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = Flags(flags[0])
+	if !sc.IsValid() {
+		return SpanContext{}, errInvalidTraceparent
+	}
+	if validTraceState(tracestate) {
+		sc.TraceState = strings.Trim(tracestate, " \t")
+	}
+	return sc, nil
+}
+
+// maxTraceStateMembers is the maximum number of list members in a tracestate.
+const maxTraceStateMembers = 32
+
+// validTraceState reports whether s is a valid tracestate header value.
+func validTraceState(s string) bool {
+	n := 0
+	var keys []string
+	for member := range strings.SplitSeq(s, ",") {
+		member = strings.Trim(member, " \t")
+		if member == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || !validTraceStateKey(key) || !validTraceStateValue(value) {
+			return false
+		}
+		for _, k := range keys {
+			if k == key {
+				return false
+			}
+		}
+		keys = append(keys, key)
+		n++
+	}
+	return n > 0 && n <= maxTraceStateMembers
+}
+
+func validTraceStateKey(key string) bool {
+	tenant, system, multi := strings.Cut(key, "@")
+	if !multi {
+		return validKeyPart(key, 256, true)
+	}
+	return validKeyPart(tenant, 241, false) && validKeyPart(system, 14, true)
+}
+
+// validKeyPart reports whether s is a valid tracestate key or key part
+// with at most maxLen characters. If lcalpha is true, s must start with
+// a lowercase letter; otherwise it may also start with a digit.
+func validKeyPart(s string, maxLen int, lcalpha bool) bool {
+	if s == "" || len(s) > maxLen {
+		return false
+	}
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z':
+		case '0' <= c && c <= '9':
+			if i == 0 && lcalpha {
+				return false
+			}
+		case c == '_' || c == '-' || c == '*' || c == '/':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func validTraceStateValue(v string) bool {
+	if v == "" || len(v) > 256 || v[len(v)-1] == ' ' {
+		return false
+	}
+	for i := range len(v) {
+		if c := v[i]; c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendHex(dst, src []byte) []byte {
+	for _, b := range src {
+		dst = append(dst, hexDigits[b>>4], hexDigits[b&0xf])
+	}
+	return dst
+}
+
+// parseHex decodes s, which must consist of lowercase hexadecimal digits.
+func parseHex(s string) ([]byte, bool) {
+	b := make([]byte, len(s)/2)
+	for i := range b {
+		hi, ok1 := fromHex(s[2*i])
+		lo, ok2 := fromHex(s[2*i+1])
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		b[i] = hi<<4 | lo
+	}
+	return b, true
+}
+
+func fromHex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	}
+	return 0, false
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries sc.
+func NewContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, sc)
+}
+
+// FromContext returns the span context carried by ctx, if any.
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(contextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// IDs returns the trace and span IDs of the valid span context carried
+// by ctx, as hexadecimal strings, or empty strings if there is none.
+// It can be used as the TraceIDs function of [log/slog.HandlerOptions].
+func IDs(ctx context.Context) (traceID, spanID string) {
+	sc, ok := FromContext(ctx)
+	if !ok || !sc.IsValid() {
+		return "", ""
+	}
+	return sc.TraceID.String(), sc.SpanID.String()
+}
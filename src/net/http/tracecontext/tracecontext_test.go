@@ -0,0 +1,135 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tracecontext
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	for _, tt := range []struct {
+		traceparent string
+		ok          bool
+		flags       Flags
+	}{
+		{"00-" + traceID + "-" + spanID + "-01", true, FlagSampled},
+		{"00-" + traceID + "-" + spanID + "-00", true, 0},
+		{"00-" + traceID + "-" + spanID + "-09", true, 9},
+		{"01-" + traceID + "-" + spanID + "-01", true, FlagSampled},
+		{"01-" + traceID + "-" + spanID + "-01-future", true, FlagSampled},
+		{"01-" + traceID + "-" + spanID + "-01future", false, 0},
+		{"00-" + traceID + "-" + spanID + "-01-", false, 0},
+		{"ff-" + traceID + "-" + spanID + "-01", false, 0},
+		{"00-" + strings.ToUpper(traceID) + "-" + spanID + "-01", false, 0},
+		{"00-00000000000000000000000000000000-" + spanID + "-01", false, 0},
+		{"00-" + traceID + "-0000000000000000-01", false, 0},
+		{"00-" + traceID + "-" + spanID + "-0x", false, 0},
+		{"00_" + traceID + "-" + spanID + "-01", false, 0},
+		{"00-" + traceID + "-" + spanID, false, 0},
+		{"", false, 0},
+	} {
+		sc, err := Parse(tt.traceparent, "")
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("Parse(%q) error = %v, want ok %v", tt.traceparent, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID || sc.Flags != tt.flags {
+			t.Errorf("Parse(%q) = %+v", tt.traceparent, sc)
+		}
+		if want := "00-" + traceID + "-" + spanID + "-" + tt.traceparent[53:55]; sc.Traceparent() != want {
+			t.Errorf("Traceparent() = %q, want %q", sc.Traceparent(), want)
+		}
+	}
+}
+
+func TestParseTraceState(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, tt := range []struct {
+		tracestate string
+		want       string
+	}{
+		{"", ""},
+		{"vendor=value", "vendor=value"},
+		{" a=1 , b=2\t", "a=1 , b=2"},
+		{"a=1,,b=2", "a=1,,b=2"},
+		{"tenant@sys=x", "tenant@sys=x"},
+		{"1tenant@sys=x", "1tenant@sys=x"},
+		{"a/b_c-d*e=x y", "a/b_c-d*e=x y"},
+		{"A=1", ""},
+		{"1a=1", ""},
+		{"a=", ""},
+		{"a=1=2", ""},
+		{"a=1 ", "a=1"},
+		{"a=1,a=2", ""},
+		{"a@b@c=1", ""},
+		{"a=\x01", ""},
+	} {
+		sc, err := Parse(traceparent, tt.tracestate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sc.TraceState != tt.want {
+			t.Errorf("Parse tracestate %q = %q, want %q", tt.tracestate, sc.TraceState, tt.want)
+		}
+	}
+
+	var members []string
+	for i := range 33 {
+		members = append(members, string(rune('a'+i%26))+strings.Repeat("x", i)+"=v")
+	}
+	if sc, _ := Parse(traceparent, strings.Join(members[:32], ",")); sc.TraceState == "" {
+		t.Errorf("tracestate with 32 members was discarded")
+	}
+	if sc, _ := Parse(traceparent, strings.Join(members, ",")); sc.TraceState != "" {
+		t.Errorf("tracestate with 33 members was kept")
+	}
+}
+
+func TestNew(t *testing.T) {
+	sc := New()
+	if !sc.IsValid() || sc.IsSampled() {
+		t.Errorf("New() = %+v, want valid and not sampled", sc)
+	}
+	sc.Flags = FlagSampled
+	sc.TraceState = "a=b"
+	child := sc.NewChild()
+	if child.TraceID != sc.TraceID || child.SpanID == sc.SpanID || child.Flags != sc.Flags || child.TraceState != sc.TraceState {
+		t.Errorf("NewChild() = %+v, parent %+v", child, sc)
+	}
+	if New().TraceID == sc.TraceID {
+		t.Errorf("New returned the same trace ID twice")
+	}
+	got, err := Parse(sc.Traceparent(), sc.TraceState)
+	if err != nil || got != sc {
+		t.Errorf("Parse(Traceparent()) = %+v, %v; want %+v", got, err, sc)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := FromContext(ctx); ok {
+		t.Errorf("FromContext of empty context succeeded")
+	}
+	sc := New()
+	if got, ok := FromContext(NewContext(ctx, sc)); !ok || got != sc {
+		t.Errorf("FromContext(NewContext(sc)) = %+v, %v; want %+v", got, ok, sc)
+	}
+	if traceID, spanID := IDs(ctx); traceID != "" || spanID != "" {
+		t.Errorf("IDs of empty context = %q, %q; want empty", traceID, spanID)
+	}
+	traceID, spanID := IDs(NewContext(ctx, sc))
+	if traceID != sc.TraceID.String() || spanID != sc.SpanID.String() {
+		t.Errorf("IDs(NewContext(sc)) = %q, %q; want %v, %v", traceID, spanID, sc.TraceID, sc.SpanID)
+	}
+}
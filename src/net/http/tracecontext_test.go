@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package http_test
+
+import (
+	"context"
+	. "net/http"
+	"net/http/tracecontext"
+	"testing"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	run(t, testTraceContextPropagation, http3SkippedMode)
+}
+func testTraceContextPropagation(t *testing.T, mode testMode) {
+	type result struct {
+		sc          tracecontext.SpanContext
+		ok          bool
+		traceparent string
+	}
+	got := make(chan result, 1)
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		sc, ok := tracecontext.FromContext(r.Context())
+		got <- result{sc, ok, r.Header.Get("Traceparent")}
+	}), func(s *Server) {
+		s.TraceContext = true
+	}, func(tr *Transport) {
+		tr.TraceContext = true
+	})
+
+	do := func(ctx context.Context, header Header) result {
+		t.Helper()
+		req, _ := NewRequestWithContext(ctx, "GET", cst.ts.URL, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		res, err := cst.c.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if _, ok := req.Header["Traceparent"]; ok != (header["Traceparent"] != nil) {
+			t.Errorf("Transport modified the request headers")
+		}
+		return <-got
+	}
+
+	// No trace context.
+	if r := do(context.Background(), nil); r.ok || r.traceparent != "" {
+		t.Errorf("without trace context: server got %+v", r)
+	}
+
+	// The trace context in the server request's context is a child
+	// of the one in the client request's context.
+	sc := tracecontext.New()
+	sc.Flags = tracecontext.FlagSampled
+	sc.TraceState = "vendor=value"
+	r := do(tracecontext.NewContext(context.Background(), sc), nil)
+	want := r.sc
+	want.SpanID = sc.SpanID
+	if !r.ok || want != sc || r.sc.SpanID == sc.SpanID {
+		t.Errorf("server got %+v, want child of %+v", r.sc, sc)
+	}
+
+	// A Traceparent header set by the caller takes precedence.
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+	r = do(tracecontext.NewContext(context.Background(), sc), Header{"Traceparent": {tp}})
+	if r.traceparent != tp || r.sc.Traceparent() != tp || r.sc.TraceState != "" {
+		t.Errorf("server got traceparent %q, trace context %+v; want %q", r.traceparent, r.sc, tp)
+	}
+
+	// A Tracestate header set by the caller is kept.
+	r = do(tracecontext.NewContext(context.Background(), sc), Header{"Tracestate": {"other=1"}})
+	if r.sc.TraceID != sc.TraceID || r.sc.TraceState != "other=1" {
+		t.Errorf("server got trace context %+v; want trace ID %v, trace state %q", r.sc, sc.TraceID, "other=1")
+	}
+
+	// An invalid Traceparent header is ignored.
+	if r := do(context.Background(), Header{"Traceparent": {"00-00000000000000000000000000000000-00f067aa0ba902b7-00"}}); r.ok {
+		t.Errorf("server got trace context %+v from invalid header", r.sc)
+	}
+}
+
+func TestTraceContextDisabled(t *testing.T) {
+	run(t, testTraceContextDisabled, http3SkippedMode)
+}
+func testTraceContextDisabled(t *testing.T, mode testMode) {
+	cst := newClientServerTest(t, mode, HandlerFunc(func(w ResponseWriter, r *Request) {
+		if tp := r.Header.Get("Traceparent"); tp != "" && r.URL.Path == "/inject" {
+			t.Errorf("Traceparent = %q, want none", tp)
+		}
+		if sc, ok := tracecontext.FromContext(r.Context()); ok {
+			t.Errorf("server got trace context %+v", sc)
+		}
+	}))
+
+	// The Transport does not send the trace context.
+	ctx := tracecontext.NewContext(context.Background(), tracecontext.New())
+	req, _ := NewRequestWithContext(ctx, "GET", cst.ts.URL+"/inject", nil)
+	res, err := cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	// The Server does not parse the trace context.
+	req, _ = NewRequest("GET", cst.ts.URL, nil)
+	req.Header.Set("Traceparent", tracecontext.New().Traceparent())
+	res, err = cst.c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
@@ -13,6 +13,7 @@ import (
 	"bufio"
 	"compress/flate"
 	"compress/gzip"
+	"compress/zstd"
 	"container/list"
 	"context"
 	"crypto/tls"
@@ -26,6 +27,7 @@ import (
 	"net/http/httptrace"
 	"net/http/internal"
 	"net/http/internal/ascii"
+	"net/http/internal/httpcommon"
 	"net/textproto"
 	"net/url"
 	"reflect"
@@ -198,13 +200,16 @@ type Transport struct {
 	DisableKeepAlives bool
 
 	// DisableCompression, if true, prevents the Transport from
-	// requesting compression with an "Accept-Encoding: gzip"
+	// requesting compression with an "Accept-Encoding: gzip, zstd"
 	// request header when the Request contains no existing
-	// Accept-Encoding value. If the Transport requests gzip on
-	// its own and gets a gzipped response, it's transparently
-	// decoded in the Response.Body. However, if the user
-	// explicitly requested gzip it is not automatically
+	// Accept-Encoding value. If the Transport requests compression
+	// on its own and gets a gzip or zstd compressed response, it's
+	// transparently decoded in the Response.Body. However, if the
+	// user explicitly requested compression it is not automatically
 	// uncompressed.
+	//
+	// Setting GODEBUG=httpzstd=0 makes the Transport request
+	// only gzip compression.
 	DisableCompression bool
 
 	// MaxIdleConns controls the maximum number of idle (keep-alive)
@@ -318,6 +323,14 @@ type Transport struct {
 	// If ForceAttemptHTTP2 is true, or if TLSNextProto contains an "h2" entry,
 	// the default is HTTP/1 and HTTP/2.
 	Protocols *Protocols
+
+	// TraceContext specifies whether to propagate W3C trace context.
+	// If true, and a request's context carries a valid span context
+	// (see [net/http/tracecontext.NewContext]) and the request has no
+	// Traceparent header, the Transport sends a Traceparent header for a
+	// new child of that span, and a Tracestate header with its trace
+	// state unless the request already has one.
+	TraceContext bool
 }
 
 func (t *Transport) writeBufferSize() int {
@@ -366,6 +379,7 @@ func (t *Transport) Clone() *Transport {
 		ForceAttemptHTTP2:      t.ForceAttemptHTTP2,
 		WriteBufferSize:        t.WriteBufferSize,
 		ReadBufferSize:         t.ReadBufferSize,
+		TraceContext:           t.TraceContext,
 	}
 	if t.TLSClientConfig != nil {
 		t2.TLSClientConfig = t.TLSClientConfig.Clone()
@@ -641,6 +655,9 @@ func (t *Transport) roundTrip(req *Request) (_ *Response, err error) {
 
 	origReq := req
 	req = setupRewindBody(req)
+	if t.TraceContext {
+		req = withTraceContext(req)
+	}
 
 	if altRT := t.alternateRoundTripper(req); altRT != nil {
 		if resp, err := altRT.RoundTrip(req); err != ErrSkipAltProtocol {
@@ -2602,8 +2619,9 @@ func (pc *persistConn) readLoop() {
 		}
 
 		resp.Body = body
-		if rc.addedGzip && ascii.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
-			resp.Body = &gzipReader{body: body}
+		if ce := resp.Header.Get("Content-Encoding"); rc.addedGzip && ascii.EqualFold(ce, "gzip") ||
+			rc.addedZstd && ascii.EqualFold(ce, "zstd") {
+			resp.Body = &decompressReader{body: body, zstd: ascii.EqualFold(ce, "zstd")}
 			resp.Header.Del("Content-Encoding")
 			resp.Header.Del("Content-Length")
 			resp.ContentLength = -1
@@ -2913,9 +2931,10 @@ type requestAndChan struct {
 	ch   chan responseAndError // unbuffered; always send in select on callerGone
 
 	// whether the Transport (as opposed to the user client code)
-	// added the Accept-Encoding gzip header. If the Transport
-	// set it, only then do we transparently decode the gzip.
+	// added the Accept-Encoding gzip (and zstd) header. If the Transport
+	// set it, only then do we transparently decode the response.
 	addedGzip bool
+	addedZstd bool
 
 	// Optional blocking chan for Expect: 100-continue (for send).
 	// If the request has an "Expect: 100-continue" header and
@@ -3012,14 +3031,14 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 
 	// Ask for a compressed version if the caller didn't set their
 	// own value for Accept-Encoding. We only attempt to
-	// uncompress the gzip stream if we were the layer that
+	// uncompress the gzip or zstd stream if we were the layer that
 	// requested it.
-	requestedGzip := false
+	requestedGzip, requestedZstd := false, false
 	if !pc.t.DisableCompression &&
 		req.Header.Get("Accept-Encoding") == "" &&
 		req.Header.Get("Range") == "" &&
 		req.Method != "HEAD" {
-		// Request gzip only, not deflate. Deflate is ambiguous and
+		// Request gzip and zstd, not deflate. Deflate is ambiguous and
 		// not as universally supported anyway.
 		// See: https://zlib.net/zlib_faq.html#faq39
 		//
@@ -3028,11 +3047,13 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 		//   https://trac.nginx.org/nginx/ticket/358
 		//   https://golang.org/issue/5522
 		//
-		// We don't request gzip if the request is for a range, since
-		// auto-decoding a portion of a gzipped document will just fail
+		// We don't request compression if the request is for a range, since
+		// auto-decoding a portion of a compressed document will just fail
 		// anyway. See https://golang.org/issue/8923
+		ae := httpcommon.AcceptEncoding()
 		requestedGzip = true
-		req.extraHeaders().Set("Accept-Encoding", "gzip")
+		requestedZstd = strings.Contains(ae, "zstd")
+		req.extraHeaders().Set("Accept-Encoding", ae)
 	}
 
 	var continueCh chan struct{}
@@ -3063,6 +3084,7 @@ func (pc *persistConn) roundTrip(req *transportRequest) (resp *Response, err err
 		treq:       req,
 		ch:         resc,
 		addedGzip:  requestedGzip,
+		addedZstd:  requestedZstd,
 		continueCh: continueCh,
 		callerGone: gone,
 	}
@@ -3290,16 +3312,17 @@ func (es *bodyEOFSignal) condfn(err error) error {
 	return err
 }
 
-// gzipReader wraps a response body so it can lazily
-// get gzip.Reader from the pool on the first call to Read.
-// After Close is called it puts gzip.Reader to the pool immediately
+// decompressReader wraps a response body so it can lazily
+// get a gzip.Reader or zstd.Reader from a pool on the first call to Read.
+// After Close is called it puts the reader to the pool immediately
 // if there is no Read in progress or later when Read completes.
-type gzipReader struct {
+type decompressReader struct {
 	_    incomparable
 	body *bodyEOFSignal // underlying HTTP/1 response body framing
+	zstd bool           // body is zstd rather than gzip compressed
 	mu   sync.Mutex     // guards zr and zerr
-	zr   *gzip.Reader   // stores gzip reader from the pool between reads
-	zerr error          // sticky gzip reader init error or sentinel value to detect concurrent read and read after close
+	zr   io.Reader      // stores *gzip.Reader or *zstd.Reader from the pool between reads
+	zerr error          // sticky reader init error or sentinel value to detect concurrent read and read after close
 }
 
 type eofReader struct{}
@@ -3307,98 +3330,113 @@ type eofReader struct{}
 func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
 func (eofReader) ReadByte() (byte, error)  { return 0, io.EOF }
 
-var gzipPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
+var (
+	gzipPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
+	zstdPool = sync.Pool{New: func() any { return zstd.NewReader(nil) }}
+)
 
-// gzipPoolGet gets a gzip.Reader from the pool and resets it to read from r.
-func gzipPoolGet(r io.Reader) (*gzip.Reader, error) {
+// decompressPoolGet gets a gzip.Reader, or a zstd.Reader if isZstd
+// is true, from the pool and resets it to read from r.
+func decompressPoolGet(r io.Reader, isZstd bool) (io.Reader, error) {
+	if isZstd {
+		zr := zstdPool.Get().(*zstd.Reader)
+		zr.Reset(r)
+		return zr, nil
+	}
 	zr := gzipPool.Get().(*gzip.Reader)
 	if err := zr.Reset(r); err != nil {
-		gzipPoolPut(zr)
+		decompressPoolPut(zr)
 		return nil, err
 	}
 	return zr, nil
 }
 
-// gzipPoolPut puts a gzip.Reader back into the pool.
-func gzipPoolPut(zr *gzip.Reader) {
-	// Reset will allocate bufio.Reader if we pass it anything
-	// other than a flate.Reader, so ensure that it's getting one.
-	var r flate.Reader = eofReader{}
-	zr.Reset(r)
-	gzipPool.Put(zr)
+// decompressPoolPut puts a gzip.Reader or zstd.Reader back into its pool.
+func decompressPoolPut(zr io.Reader) {
+	switch zr := zr.(type) {
+	case *gzip.Reader:
+		// Reset will allocate bufio.Reader if we pass it anything
+		// other than a flate.Reader, so ensure that it's getting one.
+		var r flate.Reader = eofReader{}
+		zr.Reset(r)
+		gzipPool.Put(zr)
+	case *zstd.Reader:
+		zr.Reset(eofReader{})
+		zstdPool.Put(zr)
+	}
 }
 
-// acquire returns a gzip.Reader for reading response body.
+// acquire returns a reader for reading the decompressed response body.
 // The reader must be released after use.
-func (gz *gzipReader) acquire() (*gzip.Reader, error) {
-	gz.mu.Lock()
-	defer gz.mu.Unlock()
-	if gz.zerr != nil {
-		return nil, gz.zerr
-	}
-	if gz.zr == nil {
-		// gzipPoolGet might block indefinitely since it reads the gzip header.
-		// Therefore, drop mu temporarily when using gzipPoolGet.
+func (dr *decompressReader) acquire() (io.Reader, error) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.zerr != nil {
+		return nil, dr.zerr
+	}
+	if dr.zr == nil {
+		// decompressPoolGet might block indefinitely since it reads the gzip header.
+		// Therefore, drop mu temporarily when using decompressPoolGet.
 		// We set zerr to errConcurrentReadOnResBody to prevent concurrent read
 		// even when mu is temporarily dropped.
-		gz.zerr = errConcurrentReadOnResBody
-		gz.mu.Unlock()
-		zr, err := gzipPoolGet(gz.body)
-		gz.mu.Lock()
-		// Guard against Close being called while gzipPoolGet is running.
-		if gz.zerr != errConcurrentReadOnResBody {
+		dr.zerr = errConcurrentReadOnResBody
+		dr.mu.Unlock()
+		zr, err := decompressPoolGet(dr.body, dr.zstd)
+		dr.mu.Lock()
+		// Guard against Close being called while decompressPoolGet is running.
+		if dr.zerr != errConcurrentReadOnResBody {
 			if zr != nil {
-				gzipPoolPut(zr)
+				decompressPoolPut(zr)
 			}
-			return nil, gz.zerr
+			return nil, dr.zerr
 		}
-		gz.zr, gz.zerr = zr, err
-		if gz.zerr != nil {
-			return nil, gz.zerr
+		dr.zr, dr.zerr = zr, err
+		if dr.zerr != nil {
+			return nil, dr.zerr
 		}
 	}
-	ret := gz.zr
-	gz.zr, gz.zerr = nil, errConcurrentReadOnResBody
+	ret := dr.zr
+	dr.zr, dr.zerr = nil, errConcurrentReadOnResBody
 	return ret, nil
 }
 
-// release returns the gzip.Reader to the pool if Close was called during Read.
-func (gz *gzipReader) release(zr *gzip.Reader) {
-	gz.mu.Lock()
-	defer gz.mu.Unlock()
-	if gz.zerr == errConcurrentReadOnResBody {
-		gz.zr, gz.zerr = zr, nil
+// release returns the reader to the pool if Close was called during Read.
+func (dr *decompressReader) release(zr io.Reader) {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.zerr == errConcurrentReadOnResBody {
+		dr.zr, dr.zerr = zr, nil
 	} else { // errReadOnClosedResBody
-		gzipPoolPut(zr)
+		decompressPoolPut(zr)
 	}
 }
 
-// close returns the gzip.Reader to the pool immediately or
+// close returns the reader to the pool immediately or
 // signals release to do so after Read completes.
-func (gz *gzipReader) close() {
-	gz.mu.Lock()
-	defer gz.mu.Unlock()
-	if gz.zerr == nil && gz.zr != nil {
-		gzipPoolPut(gz.zr)
-		gz.zr = nil
+func (dr *decompressReader) close() {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+	if dr.zerr == nil && dr.zr != nil {
+		decompressPoolPut(dr.zr)
+		dr.zr = nil
 	}
-	gz.zerr = errReadOnClosedResBody
+	dr.zerr = errReadOnClosedResBody
 }
 
-func (gz *gzipReader) Read(p []byte) (n int, err error) {
-	zr, err := gz.acquire()
+func (dr *decompressReader) Read(p []byte) (n int, err error) {
+	zr, err := dr.acquire()
 	if err != nil {
 		return 0, err
 	}
-	defer gz.release(zr)
+	defer dr.release(zr)
 
 	return zr.Read(p)
 }
 
-func (gz *gzipReader) Close() error {
-	gz.close()
+func (dr *decompressReader) Close() error {
+	dr.close()
 
-	return gz.body.Close()
+	return dr.body.Close()
 }
 
 type tlsHandshakeTimeoutError struct{}
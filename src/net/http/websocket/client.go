@@ -0,0 +1,151 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// DialOptions are options for [Dial].
+type DialOptions struct {
+	// Client is the HTTP client used for the opening handshake.
+	// The handshake is sent by the Client's Transport, using its proxy,
+	// TLS, and dialing configuration, and follows redirects as the
+	// Client does. The Client's Timeout is not applied; use the context
+	// passed to Dial to limit the duration of the handshake.
+	// If nil, http.DefaultClient is used.
+	//
+	// The Transport must return a writable body in 101 Switching
+	// Protocols responses, as [http.Transport] does.
+	Client *http.Client
+
+	// Header holds additional header fields to send in the opening
+	// handshake, such as Origin or Authorization.
+	Header http.Header
+
+	// Subprotocols lists the application subprotocols requested
+	// by the client, in order of preference.
+	Subprotocols []string
+
+	// Compression enables the permessage-deflate extension,
+	// if the server accepts it.
+	Compression bool
+
+	// ReadLimit is the maximum size of a message read from the
+	// connection, as set by [Conn.SetReadLimit].
+	ReadLimit int64
+}
+
+// Dial opens a WebSocket connection to the server at urlStr, whose
+// scheme is "ws" or "wss" (or equivalently, "http" or "https").
+// The opening handshake is an HTTP/1.1 Upgrade request, even if the
+// Client's Transport would use HTTP/2 for the server: Dial does not
+// support the extended CONNECT requests of RFC 8441.
+//
+// Dial returns the response to the opening handshake. If the handshake
+// fails because of the server's response, Dial returns the response
+// with its body closed, and an error wrapping [ErrBadHandshake].
+// After Dial returns, ctx has no effect on the connection.
+func Dial(ctx context.Context, urlStr string, opts *DialOptions) (*Conn, *http.Response, error) {
+	if opts == nil {
+		opts = &DialOptions{}
+	}
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, nil, errors.New("websocket: unsupported URL scheme " + strconv.Quote(u.Scheme))
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, vv := range opts.Header {
+		req.Header[k] = slices.Clone(vv)
+	}
+	var b [16]byte
+	rand.Read(b[:])
+	key := base64.StdEncoding.EncodeToString(b[:])
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-Websocket-Key", key)
+	req.Header.Set("Sec-Websocket-Version", "13")
+	if len(opts.Subprotocols) > 0 {
+		req.Header.Set("Sec-Websocket-Protocol", strings.Join(opts.Subprotocols, ", "))
+	}
+	if opts.Compression {
+		req.Header.Set("Sec-Websocket-Extensions", clientDeflateOffer)
+	}
+
+	client := http.DefaultClient
+	if opts.Client != nil {
+		client = opts.Client
+	}
+	if client.Timeout != 0 {
+		// The Timeout would apply to the connection after the handshake.
+		c := *client
+		c.Timeout = 0
+		client = &c
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	copts, err := checkResponse(resp, key, opts)
+	if err != nil {
+		resp.Body.Close()
+		return nil, resp, err
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, resp, handshakeError("response body is not writable")
+	}
+	return newConn(rwc, nil, nil, nil, copts), resp, nil
+}
+
+// checkResponse checks the server's response to the opening handshake,
+// and returns the parameters of the connection.
+func checkResponse(resp *http.Response, key string, opts *DialOptions) (connOptions, error) {
+	copts := connOptions{client: true, readLimit: opts.ReadLimit}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return copts, handshakeError("unexpected status " + resp.Status)
+	}
+	if !httpguts.HeaderValuesContainsToken(resp.Header["Connection"], "upgrade") ||
+		!httpguts.HeaderValuesContainsToken(resp.Header["Upgrade"], "websocket") {
+		return copts, handshakeError("response does not upgrade to websocket")
+	}
+	if resp.Header.Get("Sec-Websocket-Accept") != acceptKey(key) {
+		return copts, handshakeError("invalid Sec-WebSocket-Accept")
+	}
+	if p := textproto.TrimString(resp.Header.Get("Sec-Websocket-Protocol")); p != "" {
+		if !slices.Contains(opts.Subprotocols, p) {
+			return copts, handshakeError("unexpected subprotocol " + p)
+		}
+		copts.subprotocol = p
+	}
+	var err error
+	copts.compress, copts.takeover, err = checkDeflateResponse(resp.Header, opts.Compression)
+	return copts, err
+}
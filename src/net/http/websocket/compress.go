@@ -0,0 +1,189 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"compress/flate"
+	"io"
+	"net/http"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The permessage-deflate extension is specified by RFC 7692.
+//
+// This package never compresses using context takeover: each message is
+// compressed independently, and the negotiated response always allows
+// that. Messages from the peer are decompressed using context takeover
+// unless the negotiation ruled it out.
+
+const deflateExtension = "permessage-deflate"
+
+// deflateTail is appended to the compressed data of each message before
+// it is decompressed. The first four bytes are the end of the empty
+// stored block that the sender removes (RFC 7692, Section 7.2.2); the
+// rest is an empty final block, which ends the flate stream.
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff}
+
+// maxWindow is the size of the LZ77 window used by compress/flate.
+const maxWindow = 1 << 15
+
+var (
+	flateWriterPool sync.Pool // *flate.Writer
+	flateReaderPool sync.Pool // io.ReadCloser, which is also a flate.Resetter
+)
+
+func getFlateWriter(w io.Writer) *flate.Writer {
+	if fw, ok := flateWriterPool.Get().(*flate.Writer); ok {
+		fw.Reset(w)
+		return fw
+	}
+	fw, _ := flate.NewWriter(w, flate.BestSpeed)
+	return fw
+}
+
+func putFlateWriter(fw *flate.Writer) {
+	fw.Reset(nil)
+	flateWriterPool.Put(fw)
+}
+
+func getFlateReader(r io.Reader, dict []byte) io.ReadCloser {
+	if fr, ok := flateReaderPool.Get().(io.ReadCloser); ok {
+		fr.(flate.Resetter).Reset(r, dict)
+		return fr
+	}
+	return flate.NewReaderDict(r, dict)
+}
+
+func putFlateReader(fr io.ReadCloser) {
+	flateReaderPool.Put(fr)
+}
+
+// A deflateOffer is a permessage-deflate extension offer or response.
+type deflateOffer struct {
+	serverNoContextTakeover bool
+	clientNoContextTakeover bool
+	serverMaxWindowBits     int  // 0 if not present
+	clientMaxWindowBits     bool // present, with or without a value
+}
+
+// parseExtensions parses the Sec-WebSocket-Extensions header fields in h.
+// It returns the extensions in order, each as its name followed by its
+// parameters. A parameter without a value has an empty value.
+func parseExtensions(h http.Header) [][][2]string {
+	var exts [][][2]string
+	for _, v := range h.Values("Sec-Websocket-Extensions") {
+		for ext := range strings.SplitSeq(v, ",") {
+			var params [][2]string
+			for p := range strings.SplitSeq(ext, ";") {
+				name, value, _ := strings.Cut(p, "=")
+				name = textproto.TrimString(name)
+				value = textproto.TrimString(value)
+				if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+					value = value[1 : len(value)-1]
+				}
+				params = append(params, [2]string{name, value})
+			}
+			if params[0][0] != "" {
+				exts = append(exts, params)
+			}
+		}
+	}
+	return exts
+}
+
+// parseDeflateOffer parses the parameters of a permessage-deflate
+// extension. It reports false if a parameter is unknown, repeated,
+// or has an invalid value.
+func parseDeflateOffer(params [][2]string) (deflateOffer, bool) {
+	var o deflateOffer
+	seen := make(map[string]bool)
+	for _, p := range params {
+		name, ok := ascii.ToLower(p[0])
+		if !ok || seen[name] {
+			return o, false
+		}
+		seen[name] = true
+		switch name {
+		case "server_no_context_takeover":
+			if p[1] != "" {
+				return o, false
+			}
+			o.serverNoContextTakeover = true
+		case "client_no_context_takeover":
+			if p[1] != "" {
+				return o, false
+			}
+			o.clientNoContextTakeover = true
+		case "server_max_window_bits":
+			bits, err := strconv.Atoi(p[1])
+			if err != nil || bits < 8 || bits > 15 {
+				return o, false
+			}
+			o.serverMaxWindowBits = bits
+		case "client_max_window_bits":
+			if p[1] != "" {
+				bits, err := strconv.Atoi(p[1])
+				if err != nil || bits < 8 || bits > 15 {
+					return o, false
+				}
+			}
+			o.clientMaxWindowBits = true
+		default:
+			return o, false
+		}
+	}
+	return o, true
+}
+
+// acceptDeflate selects the first permessage-deflate offer in the
+// client's header h that the server can accept. It returns the
+// extension response and whether the client may compress messages
+// using context takeover.
+func acceptDeflate(h http.Header) (response string, takeover, ok bool) {
+	for _, ext := range parseExtensions(h) {
+		if !ascii.EqualFold(ext[0][0], deflateExtension) {
+			continue
+		}
+		o, valid := parseDeflateOffer(ext[1:])
+		// compress/flate always uses a 32KiB window, so an offer
+		// limiting the server's window cannot be accepted.
+		if !valid || o.serverMaxWindowBits != 0 {
+			continue
+		}
+		response = deflateExtension + "; server_no_context_takeover"
+		if o.clientNoContextTakeover {
+			response += "; client_no_context_takeover"
+		}
+		return response, !o.clientNoContextTakeover, true
+	}
+	return "", false, false
+}
+
+// clientDeflateOffer is the permessage-deflate offer sent by clients.
+const clientDeflateOffer = deflateExtension + "; client_no_context_takeover"
+
+// checkDeflateResponse checks the extensions accepted by the server in
+// its response header h. It reports whether permessage-deflate was
+// accepted, and whether the server may compress messages using context
+// takeover.
+func checkDeflateResponse(h http.Header, offered bool) (accepted, takeover bool, err error) {
+	for _, ext := range parseExtensions(h) {
+		if !offered || accepted || !ascii.EqualFold(ext[0][0], deflateExtension) {
+			return false, false, handshakeError("unexpected extension " + strconv.Quote(ext[0][0]))
+		}
+		o, valid := parseDeflateOffer(ext[1:])
+		// The client did not offer client_max_window_bits,
+		// so the server must not limit the client's window.
+		if !valid || o.clientMaxWindowBits {
+			return false, false, handshakeError("invalid permessage-deflate response")
+		}
+		accepted, takeover = true, !o.serverNoContextTakeover
+	}
+	return accepted, takeover, nil
+}
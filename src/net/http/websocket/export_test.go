@@ -0,0 +1,16 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import "io"
+
+// NewConn returns a Conn using rwc, which is already open.
+func NewConn(rwc io.ReadWriteCloser, client, compress bool) *Conn {
+	return newConn(rwc, nil, nil, nil, connOptions{
+		client:   client,
+		compress: compress,
+		takeover: compress,
+	})
+}
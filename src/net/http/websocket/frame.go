@@ -0,0 +1,187 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"unicode/utf8"
+)
+
+// Frame opcodes, from RFC 6455, Section 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// Bits of the first two bytes of a frame header.
+const (
+	finBit  = 0x80
+	rsv1Bit = 0x40 // set on the first frame of a compressed message
+	rsv2Bit = 0x20
+	rsv3Bit = 0x10
+	maskBit = 0x80
+)
+
+const (
+	// maxControlPayload is the maximum payload length of a control frame.
+	maxControlPayload = 125
+
+	// maxFrameHeader is the maximum length of a frame header.
+	maxFrameHeader = 14
+)
+
+// A frameHeader is the header of a frame.
+type frameHeader struct {
+	fin    bool
+	rsv1   bool
+	opcode byte
+	masked bool
+	length int64
+	key    [4]byte
+}
+
+// isControl reports whether op is the opcode of a control frame.
+func isControl(op byte) bool {
+	return op&0x8 != 0
+}
+
+// readFrameHeader reads a frame header from br. It returns io.EOF only
+// if br ends before the first byte of the header.
+func readFrameHeader(br *bufio.Reader) (frameHeader, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(br, b[:2]); err != nil {
+		return frameHeader{}, err
+	}
+	h := frameHeader{
+		fin:    b[0]&finBit != 0,
+		rsv1:   b[0]&rsv1Bit != 0,
+		opcode: b[0] & 0xf,
+		masked: b[1]&maskBit != 0,
+	}
+	if b[0]&(rsv2Bit|rsv3Bit) != 0 {
+		return h, protocolError("reserved bits set in frame header")
+	}
+	switch n := b[1] &^ maskBit; n {
+	case 126:
+		if _, err := io.ReadFull(br, b[:2]); err != nil {
+			return h, noEOF(err)
+		}
+		h.length = int64(binary.BigEndian.Uint16(b[:2]))
+	case 127:
+		if _, err := io.ReadFull(br, b[:8]); err != nil {
+			return h, noEOF(err)
+		}
+		n := binary.BigEndian.Uint64(b[:8])
+		if n>>63 != 0 {
+			return h, protocolError("invalid frame length")
+		}
+		h.length = int64(n)
+	default:
+		h.length = int64(n)
+	}
+	if h.masked {
+		if _, err := io.ReadFull(br, h.key[:]); err != nil {
+			return h, noEOF(err)
+		}
+	}
+	return h, nil
+}
+
+// appendFrameHeader appends a frame header to b.
+// If key is non-nil, the header has the mask bit set and the key.
+func appendFrameHeader(b []byte, fin, rsv1 bool, op byte, length int, key *[4]byte) []byte {
+	b0 := op
+	if fin {
+		b0 |= finBit
+	}
+	if rsv1 {
+		b0 |= rsv1Bit
+	}
+	var b1 byte
+	if key != nil {
+		b1 = maskBit
+	}
+	switch {
+	case length < 126:
+		b = append(b, b0, b1|byte(length))
+	case length <= 0xffff:
+		b = append(b, b0, b1|126)
+		b = binary.BigEndian.AppendUint16(b, uint16(length))
+	default:
+		b = append(b, b0, b1|127)
+		b = binary.BigEndian.AppendUint64(b, uint64(length))
+	}
+	if key != nil {
+		b = append(b, key[:]...)
+	}
+	return b
+}
+
+// mask applies the masking key to b, which starts at offset pos of
+// the payload, and returns the offset of the end of b.
+func mask(key [4]byte, pos int, b []byte) int {
+	for i := range b {
+		b[i] ^= key[(pos+i)&3]
+	}
+	return (pos + len(b)) & 3
+}
+
+// noEOF converts io.EOF to io.ErrUnexpectedEOF.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// A utf8Validator checks that a text message read in pieces is UTF-8.
+type utf8Validator struct {
+	buf [utf8.UTFMax]byte // incomplete rune at the end of the last piece
+	n   int
+}
+
+// write reports whether p may continue a valid UTF-8 sequence.
+func (v *utf8Validator) write(p []byte) bool {
+	for v.n > 0 && len(p) > 0 {
+		v.buf[v.n] = p[0]
+		v.n++
+		p = p[1:]
+		if utf8.FullRune(v.buf[:v.n]) {
+			if r, size := utf8.DecodeRune(v.buf[:v.n]); r == utf8.RuneError && size == 1 {
+				return false
+			}
+			v.n = 0
+		}
+	}
+	if v.n > 0 {
+		return true // p was part of the incomplete rune
+	}
+	// Hold back an incomplete rune at the end of p.
+	end := len(p)
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax+1; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+	if !utf8.Valid(p[:end]) {
+		return false
+	}
+	v.n = copy(v.buf[:], p[end:])
+	return true
+}
+
+// done reports whether the text ended at the end of a rune.
+func (v *utf8Validator) done() bool {
+	return v.n == 0
+}
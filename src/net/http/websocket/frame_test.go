@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFrameHeaderRoundTrip(t *testing.T) {
+	key := [4]byte{1, 2, 3, 4}
+	for _, length := range []int{0, 1, 125, 126, 0xffff, 0x10000, 1 << 20} {
+		for _, k := range []*[4]byte{nil, &key} {
+			b := appendFrameHeader(nil, true, true, opBinary, length, k)
+			h, err := readFrameHeader(bufio.NewReader(bytes.NewReader(b)))
+			if err != nil {
+				t.Fatalf("length %d: %v", length, err)
+			}
+			want := frameHeader{fin: true, rsv1: true, opcode: opBinary, masked: k != nil, length: int64(length)}
+			if k != nil {
+				want.key = key
+			}
+			if h != want {
+				t.Errorf("length %d: read %+v, want %+v", length, h, want)
+			}
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	key := [4]byte{0x12, 0x34, 0x56, 0x78}
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	want := bytes.Clone(data)
+	mask(key, 0, want)
+
+	// Masking in pieces gives the same result as masking at once.
+	got := bytes.Clone(data)
+	pos := 0
+	for i := 0; i < len(got); i += 5 {
+		pos = mask(key, pos, got[i:min(i+5, len(got))])
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("masking in pieces = %x, want %x", got, want)
+	}
+	mask(key, 0, got)
+	if !bytes.Equal(got, data) {
+		t.Errorf("unmasking = %q, want %q", got, data)
+	}
+}
+
+func TestUTF8Validator(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"hello",
+		"héllo, 世界 🌍",
+		"\xff",
+		"abc\xe4\xb8",      // truncated rune
+		"\xed\xa0\x80",     // surrogate
+		"\xf4\x90\x80\x80", // beyond U+10FFFF
+		"\xc0\xaf",         // overlong
+	} {
+		want := utf8.ValidString(s)
+		for split := range len(s) + 1 {
+			var v utf8Validator
+			got := v.write([]byte(s[:split])) && v.write([]byte(s[split:])) && v.done()
+			if got != want {
+				t.Errorf("%q split at %d: valid = %v, want %v", s, split, got, want)
+			}
+		}
+		// One byte at a time.
+		var v utf8Validator
+		got := true
+		for i := range len(s) {
+			got = got && v.write([]byte{s[i]})
+		}
+		if got = got && v.done(); got != want {
+			t.Errorf("%q bytewise: valid = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestAcceptDeflate(t *testing.T) {
+	for _, tt := range []struct {
+		offer    string
+		response string
+		takeover bool
+		ok       bool
+	}{
+		{"permessage-deflate", "permessage-deflate; server_no_context_takeover", true, true},
+		{"permessage-deflate; client_max_window_bits", "permessage-deflate; server_no_context_takeover", true, true},
+		{"permessage-deflate; client_no_context_takeover", "permessage-deflate; server_no_context_takeover; client_no_context_takeover", false, true},
+		{`permessage-deflate; client_max_window_bits="10"`, "permessage-deflate; server_no_context_takeover", true, true},
+		{"permessage-deflate; server_max_window_bits=10", "", false, false},
+		{"permessage-deflate; server_max_window_bits=10, permessage-deflate", "permessage-deflate; server_no_context_takeover", true, true},
+		{"permessage-deflate; unknown", "", false, false},
+		{"permessage-deflate; server_no_context_takeover; server_no_context_takeover", "", false, false},
+		{"x-webkit-deflate-frame", "", false, false},
+	} {
+		h := http.Header{"Sec-Websocket-Extensions": {tt.offer}}
+		response, takeover, ok := acceptDeflate(h)
+		if response != tt.response || takeover != tt.takeover || ok != tt.ok {
+			t.Errorf("acceptDeflate(%q) = %q, %v, %v; want %q, %v, %v", tt.offer, response, takeover, ok, tt.response, tt.takeover, tt.ok)
+		}
+	}
+}
+
+func TestCheckDeflateResponse(t *testing.T) {
+	for _, tt := range []struct {
+		response string
+		offered  bool
+		accepted bool
+		takeover bool
+		ok       bool
+	}{
+		{"", true, false, false, true},
+		{"permessage-deflate", true, true, true, true},
+		{"permessage-deflate; server_no_context_takeover", true, true, false, true},
+		{"permessage-deflate; server_max_window_bits=9", true, true, true, true},
+		{"permessage-deflate; client_max_window_bits=9", true, false, false, false},
+		{"permessage-deflate", false, false, false, false},
+		{"permessage-deflate, permessage-deflate", true, false, false, false},
+		{"other", true, false, false, false},
+	} {
+		var h http.Header
+		if tt.response != "" {
+			h = http.Header{"Sec-Websocket-Extensions": {tt.response}}
+		}
+		accepted, takeover, err := checkDeflateResponse(h, tt.offered)
+		if accepted != tt.accepted || takeover != tt.takeover || (err == nil) != tt.ok {
+			t.Errorf("checkDeflateResponse(%q, %v) = %v, %v, %v; want %v, %v, ok=%v", tt.response, tt.offered, accepted, takeover, err, tt.accepted, tt.takeover, tt.ok)
+		}
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455, Section 1.3.
+	if got, want := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("acceptKey = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/internal/ascii"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// acceptGUID is appended to the key of an opening handshake
+// to compute the Sec-WebSocket-Accept header field.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// acceptKey returns the Sec-WebSocket-Accept value for key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// UpgradeOptions are options for [Upgrade].
+type UpgradeOptions struct {
+	// Subprotocols lists the application subprotocols supported by
+	// the server, in order of preference. Upgrade selects the first
+	// one that the client requests. If the client requests none of
+	// them, no subprotocol is selected.
+	Subprotocols []string
+
+	// CheckOrigin reports whether to accept a request from a web page
+	// with the origin in r's Origin header field. If CheckOrigin is nil,
+	// requests are accepted if they have no Origin header field or if
+	// its host matches r.Host, to protect against cross-site WebSocket
+	// hijacking.
+	CheckOrigin func(r *http.Request) bool
+
+	// Compression enables the permessage-deflate extension,
+	// if the client offers it.
+	Compression bool
+
+	// ReadLimit is the maximum size of a message read from the
+	// connection, as set by [Conn.SetReadLimit].
+	ReadLimit int64
+}
+
+// Upgrade upgrades an HTTP request to the WebSocket protocol and returns
+// the connection. Header fields set in w.Header() are included in the
+// response to the opening handshake.
+//
+// If the request is not a valid opening handshake, Upgrade replies to it
+// with an HTTP error and returns an error wrapping [ErrBadHandshake].
+//
+// For an HTTP/1.1 request, Upgrade hijacks the connection, and the
+// handler may return while the Conn is in use. For an HTTP/2 extended
+// CONNECT request, the Conn uses the request's stream, which ends when
+// the handler returns: the handler must not return until it is done
+// with the Conn.
+func Upgrade(w http.ResponseWriter, r *http.Request, opts *UpgradeOptions) (*Conn, error) {
+	if opts == nil {
+		opts = &UpgradeOptions{}
+	}
+	var key string
+	switch {
+	case r.ProtoMajor == 1:
+		if r.Method != "GET" {
+			w.Header().Set("Allow", "GET")
+			return nil, upgradeError(w, http.StatusMethodNotAllowed, "method is not GET")
+		}
+		if !r.ProtoAtLeast(1, 1) {
+			return nil, upgradeError(w, http.StatusBadRequest, "protocol is not HTTP/1.1")
+		}
+		if !httpguts.HeaderValuesContainsToken(r.Header["Connection"], "upgrade") ||
+			!httpguts.HeaderValuesContainsToken(r.Header["Upgrade"], "websocket") {
+			w.Header().Set("Upgrade", "websocket")
+			w.Header().Set("Connection", "Upgrade")
+			return nil, upgradeError(w, http.StatusUpgradeRequired, "not a websocket upgrade request")
+		}
+		key = r.Header.Get("Sec-Websocket-Key")
+		if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+			return nil, upgradeError(w, http.StatusBadRequest, "invalid Sec-WebSocket-Key")
+		}
+	case r.Method == "CONNECT" && r.Header.Get(":protocol") == "websocket":
+		// RFC 8441 extended CONNECT.
+	default:
+		return nil, upgradeError(w, http.StatusBadRequest, "not a websocket request")
+	}
+	if r.Header.Get("Sec-Websocket-Version") != "13" {
+		w.Header().Set("Sec-Websocket-Version", "13")
+		return nil, upgradeError(w, http.StatusUpgradeRequired, "unsupported Sec-WebSocket-Version")
+	}
+	checkOrigin := opts.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(r) {
+		return nil, upgradeError(w, http.StatusForbidden, "origin not allowed")
+	}
+
+	copts := connOptions{readLimit: opts.ReadLimit}
+	h := w.Header()
+	if p := selectSubprotocol(r, opts.Subprotocols); p != "" {
+		copts.subprotocol = p
+		h.Set("Sec-Websocket-Protocol", p)
+	}
+	if opts.Compression {
+		if resp, takeover, ok := acceptDeflate(r.Header); ok {
+			copts.compress, copts.takeover = true, takeover
+			h.Set("Sec-Websocket-Extensions", resp)
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	if r.ProtoMajor != 1 {
+		// The stream is the connection.
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return nil, err
+		}
+		rwc := &streamConn{Reader: r.Body, Writer: w, body: r.Body}
+		return newConn(rwc, nil, nil, rc.Flush, copts), nil
+	}
+
+	netConn, brw, err := rc.Hijack()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, err
+	}
+	// Clear the deadlines set by the Server for HTTP requests.
+	netConn.SetDeadline(time.Time{})
+	h.Set("Upgrade", "websocket")
+	h.Set("Connection", "Upgrade")
+	h.Set("Sec-Websocket-Accept", acceptKey(key))
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	h.Write(brw)
+	brw.WriteString("\r\n")
+	if err := brw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, brw.Reader, brw.Writer, nil, copts), nil
+}
+
+// upgradeError replies to a request that cannot be upgraded.
+func upgradeError(w http.ResponseWriter, code int, msg string) error {
+	http.Error(w, "websocket: "+msg, code)
+	return handshakeError(msg)
+}
+
+// sameOrigin reports whether r has no Origin header field,
+// or has one whose host is r.Host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header["Origin"]
+	if len(origin) == 0 {
+		return true
+	}
+	u, err := url.Parse(origin[0])
+	if err != nil {
+		return false
+	}
+	return ascii.EqualFold(u.Host, r.Host)
+}
+
+// selectSubprotocol returns the first of the supported subprotocols
+// requested by r, or "".
+func selectSubprotocol(r *http.Request, supported []string) string {
+	var requested []string
+	for _, v := range r.Header.Values("Sec-Websocket-Protocol") {
+		for p := range strings.SplitSeq(v, ",") {
+			requested = append(requested, textproto.TrimString(p))
+		}
+	}
+	for _, p := range supported {
+		if slices.Contains(requested, p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// A streamConn is the connection of an HTTP/2 extended CONNECT request:
+// the request body and the response.
+type streamConn struct {
+	io.Reader
+	io.Writer
+	body io.Closer
+}
+
+func (c *streamConn) Close() error {
+	return c.body.Close()
+}
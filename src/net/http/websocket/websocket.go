@@ -0,0 +1,857 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package websocket implements the WebSocket protocol, as specified by
+// RFC 6455, with the permessage-deflate extension of RFC 7692.
+//
+// On the server, [Upgrade] establishes a connection from an HTTP request:
+// either an HTTP/1.1 Upgrade request, or an HTTP/2 extended CONNECT
+// request as specified by RFC 8441. On the client, [Dial] performs the
+// opening handshake using an [http.Client], so that the proxy, TLS, and
+// dialing configuration of its [http.Transport] apply. Dial only sends
+// HTTP/1.1 Upgrade requests: the client does not support RFC 8441.
+//
+// A [Conn] sends and receives messages, which may be split into several
+// frames. It answers pings from the peer, and implements the closing
+// handshake.
+//
+// The HTTP/2 server accepts extended CONNECT requests only when the
+// GODEBUG setting http2xconnect=1 is set.
+package websocket
+
+import (
+	"bufio"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// A MessageType is the type of a data message.
+type MessageType int
+
+const (
+	TextMessage   MessageType = opText   // UTF-8 text
+	BinaryMessage MessageType = opBinary // binary data
+)
+
+func (t MessageType) String() string {
+	switch t {
+	case TextMessage:
+		return "text"
+	case BinaryMessage:
+		return "binary"
+	}
+	return "MessageType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// A StatusCode is the status code of a close frame,
+// as defined by RFC 6455, Section 7.4.
+type StatusCode int
+
+const (
+	StatusNormalClosure      StatusCode = 1000
+	StatusGoingAway          StatusCode = 1001
+	StatusProtocolError      StatusCode = 1002
+	StatusUnsupportedData    StatusCode = 1003
+	StatusNoStatusReceived   StatusCode = 1005 // never sent; a close frame without a status code
+	StatusAbnormalClosure    StatusCode = 1006 // never sent; the connection closed without a close frame
+	StatusInvalidPayloadData StatusCode = 1007
+	StatusPolicyViolation    StatusCode = 1008
+	StatusMessageTooBig      StatusCode = 1009
+	StatusMandatoryExtension StatusCode = 1010
+	StatusInternalError      StatusCode = 1011
+)
+
+// validSend reports whether code may be sent in a close frame.
+func (code StatusCode) validSend() bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1011:
+		return true
+	case code >= 3000 && code <= 4999:
+		return true
+	}
+	return false
+}
+
+// A CloseError is returned by the methods of a [Conn] reading messages
+// after the peer has closed the connection with a close frame.
+type CloseError struct {
+	Code   StatusCode // StatusNoStatusReceived if the frame had no status code
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	s := "websocket: connection closed with status " + strconv.Itoa(int(e.Code))
+	if e.Reason != "" {
+		s += ": " + e.Reason
+	}
+	return s
+}
+
+// A connError is an error in the data received from the peer.
+// It fails the connection with a close frame with the given status code.
+type connError struct {
+	code StatusCode
+	msg  string
+}
+
+func (e *connError) Error() string {
+	return "websocket: " + e.msg
+}
+
+func protocolError(msg string) error {
+	return &connError{StatusProtocolError, msg}
+}
+
+var (
+	// ErrBadHandshake is returned by [Upgrade] and [Dial] for an
+	// opening handshake that does not conform to the protocol.
+	ErrBadHandshake = errors.New("websocket: bad handshake")
+
+	// ErrCloseSent is returned by the methods of a [Conn] writing
+	// messages after the Conn has sent a close frame.
+	ErrCloseSent = errors.New("websocket: close sent")
+)
+
+func handshakeError(msg string) error {
+	return fmt.Errorf("%w: %s", ErrBadHandshake, msg)
+}
+
+const (
+	// DefaultReadLimit is the default maximum size of a message read by a Conn.
+	DefaultReadLimit = 32 << 20
+
+	// fragmentSize is the maximum payload length of the frames
+	// of a message written by a Conn.
+	fragmentSize = 32 << 10
+
+	// closeTimeout is how long Close waits for the peer's close frame.
+	closeTimeout = 5 * time.Second
+)
+
+// A Conn is a WebSocket connection.
+//
+// At most one goroutine may read messages from a Conn at a time, and at
+// most one goroutine may write messages; a message being written is not
+// interrupted by other messages. Close, CloseNow, Ping, and Subprotocol
+// may be called concurrently with all other methods.
+type Conn struct {
+	rwc         io.ReadWriteCloser
+	flush       func() error // flushes the connection after each frame, or nil
+	client      bool
+	subprotocol string
+	compress    bool // permessage-deflate was negotiated
+	takeover    bool // the peer compresses using context takeover
+	readLimit   atomic.Int64
+
+	// Reading. readMu is held while reading a message.
+	readMu  sync.Mutex
+	br      *bufio.Reader
+	rerr    error          // sticky read error
+	active  *messageReader // message being read, or nil
+	window  []byte         // recent decompressed data, with takeover
+	ctrlBuf [maxControlPayload]byte
+	rkey    [4]byte // mask key of the current frame
+	rkeyPos int
+	rremain int64 // payload remaining in the current frame
+	rfin    bool  // current frame is the last of its message
+
+	// Writing. msgMu is held while writing a message;
+	// wmu is held while writing a frame.
+	msgMu     sync.Mutex
+	wmu       sync.Mutex
+	bw        *bufio.Writer
+	wbuf      []byte
+	closeSent bool
+
+	mu        sync.Mutex
+	pings     map[string]chan struct{} // pings awaiting a pong, by payload
+	pingCount int
+
+	closeRecv     chan struct{} // closed when a close frame is received
+	closed        chan struct{} // closed when rwc is closed
+	closeOnce     sync.Once
+	closeRecvOnce sync.Once
+}
+
+// connOptions are the parameters of a new Conn.
+type connOptions struct {
+	client      bool
+	subprotocol string
+	compress    bool
+	takeover    bool
+	readLimit   int64
+}
+
+func newConn(rwc io.ReadWriteCloser, br *bufio.Reader, bw *bufio.Writer, flush func() error, opts connOptions) *Conn {
+	if br == nil {
+		br = bufio.NewReader(rwc)
+	}
+	if bw == nil {
+		bw = bufio.NewWriter(rwc)
+	}
+	c := &Conn{
+		rwc:         rwc,
+		flush:       flush,
+		client:      opts.client,
+		subprotocol: opts.subprotocol,
+		compress:    opts.compress,
+		takeover:    opts.takeover,
+		br:          br,
+		bw:          bw,
+		closeRecv:   make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+	c.SetReadLimit(opts.readLimit)
+	return c
+}
+
+// Subprotocol returns the application subprotocol selected in the
+// opening handshake, or "" if none was.
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// SetReadLimit sets the maximum size of a message read from the
+// connection, after decompression. When a message exceeds the limit,
+// the connection is closed with StatusMessageTooBig.
+// If n is zero, DefaultReadLimit is used. If n is negative,
+// the size of messages is not limited.
+func (c *Conn) SetReadLimit(n int64) {
+	if n == 0 {
+		n = DefaultReadLimit
+	}
+	c.readLimit.Store(n)
+}
+
+// Read reads the next data message from the connection.
+//
+// Read answers ping and close frames received from the peer. When the
+// peer closes the connection with a close frame, Read answers it and
+// returns a [*CloseError]. If ctx is done before a message is read,
+// the connection is closed.
+func (c *Conn) Read(ctx context.Context) (MessageType, []byte, error) {
+	typ, r, err := c.Reader(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return typ, b, nil
+}
+
+// Reader returns a reader for the next data message from the
+// connection. The message must be read to the end before the next call
+// to Reader or Read; otherwise the rest of it is discarded by that call.
+//
+// Reader answers control frames as [Conn.Read] does. If ctx is done
+// before the message has been read to the end, the connection is closed.
+func (c *Conn) Reader(ctx context.Context) (MessageType, io.Reader, error) {
+	if r := c.active; r != nil {
+		// Discard the rest of the previous message.
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return 0, nil, err
+		}
+	}
+	c.readMu.Lock()
+	stop := context.AfterFunc(ctx, func() { c.CloseNow() })
+	typ, r, err := c.nextMessage()
+	if err != nil {
+		stop()
+		c.readMu.Unlock()
+		return 0, nil, ctxError(ctx, err)
+	}
+	r.ctx = ctx
+	r.done = func() {
+		stop()
+		c.active = nil
+		c.readMu.Unlock()
+	}
+	c.active = r
+	return typ, r, nil
+}
+
+// ctxError returns ctx's error if ctx is done,
+// which is what caused err, and otherwise err.
+func ctxError(ctx context.Context, err error) error {
+	if ctx != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// nextMessage reads frames until the first frame of a data message,
+// and returns a reader for the message. readMu must be held.
+func (c *Conn) nextMessage() (MessageType, *messageReader, error) {
+	if c.rerr != nil {
+		return 0, nil, c.rerr
+	}
+	h, err := c.nextDataFrame()
+	if err != nil {
+		return 0, nil, err
+	}
+	if h.opcode == opContinuation {
+		return 0, nil, c.fail(protocolError("unexpected continuation frame"))
+	}
+	r := &messageReader{
+		c:     c,
+		typ:   MessageType(h.opcode),
+		limit: c.readLimit.Load(),
+	}
+	r.src = r.readFrames
+	if h.rsv1 {
+		var dict []byte
+		if c.takeover {
+			dict = c.window
+		}
+		r.fr = getFlateReader(io.MultiReader(readerFunc(r.readFrames), readerFunc(r.readTail)), dict)
+		r.src = r.fr.Read
+	}
+	return r.typ, r, nil
+}
+
+// nextDataFrame reads frames until the header of a data frame,
+// handling control frames. readMu must be held.
+func (c *Conn) nextDataFrame() (frameHeader, error) {
+	for {
+		h, err := readFrameHeader(c.br)
+		if err != nil {
+			return h, c.fail(err)
+		}
+		if err := c.checkFrameHeader(h); err != nil {
+			return h, c.fail(err)
+		}
+		c.rkey, c.rkeyPos = h.key, 0
+		c.rremain, c.rfin = h.length, h.fin
+		if !isControl(h.opcode) {
+			return h, nil
+		}
+		if err := c.handleControl(h); err != nil {
+			return h, err
+		}
+	}
+}
+
+func (c *Conn) checkFrameHeader(h frameHeader) error {
+	if h.masked == c.client {
+		if c.client {
+			return protocolError("masked frame from server")
+		}
+		return protocolError("unmasked frame from client")
+	}
+	switch h.opcode {
+	case opContinuation, opText, opBinary:
+		if h.rsv1 && (!c.compress || h.opcode == opContinuation) {
+			return protocolError("reserved bits set in frame header")
+		}
+	case opClose, opPing, opPong:
+		if h.rsv1 {
+			return protocolError("reserved bits set in frame header")
+		}
+		if !h.fin {
+			return protocolError("fragmented control frame")
+		}
+		if h.length > maxControlPayload {
+			return protocolError("control frame too long")
+		}
+	default:
+		return protocolError("unknown opcode " + strconv.Itoa(int(h.opcode)))
+	}
+	return nil
+}
+
+// readPayload reads from the payload of the current frame.
+func (c *Conn) readPayload(p []byte) (int, error) {
+	if int64(len(p)) > c.rremain {
+		p = p[:c.rremain]
+	}
+	n, err := c.br.Read(p)
+	c.rremain -= int64(n)
+	if !c.client {
+		c.rkeyPos = mask(c.rkey, c.rkeyPos, p[:n])
+	}
+	return n, err
+}
+
+// handleControl reads the payload of a control frame and acts on it.
+func (c *Conn) handleControl(h frameHeader) error {
+	p := c.ctrlBuf[:h.length]
+	for n := 0; n < len(p); {
+		m, err := c.readPayload(p[n:])
+		n += m
+		if err != nil && n < len(p) {
+			return c.fail(noEOF(err))
+		}
+	}
+	switch h.opcode {
+	case opPing:
+		err := c.writeFrame(true, false, opPong, p)
+		if err != nil && err != ErrCloseSent {
+			return c.fail(err)
+		}
+	case opPong:
+		c.mu.Lock()
+		if ch := c.pings[string(p)]; ch != nil {
+			delete(c.pings, string(p))
+			close(ch)
+		}
+		c.mu.Unlock()
+	case opClose:
+		ce := &CloseError{Code: StatusNoStatusReceived}
+		switch {
+		case len(p) == 1:
+			return c.fail(protocolError("invalid close frame"))
+		case len(p) >= 2:
+			ce.Code = StatusCode(int(p[0])<<8 | int(p[1]))
+			ce.Reason = string(p[2:])
+			if !ce.Code.validSend() {
+				return c.fail(protocolError("invalid close status " + strconv.Itoa(int(ce.Code))))
+			}
+			if !utf8.ValidString(ce.Reason) {
+				return c.fail(&connError{StatusInvalidPayloadData, "invalid UTF-8 in close reason"})
+			}
+		}
+		c.rerr = ce
+		c.closeRecvOnce.Do(func() { close(c.closeRecv) })
+		// Echo the status code, as is usual, if this is not
+		// the reply to a close frame sent earlier.
+		c.writeClose(ce.Code, "")
+		c.CloseNow()
+		return ce
+	}
+	return nil
+}
+
+// fail fails the connection because of err, which was encountered
+// reading from it, and returns the error for the reader to return.
+// If err is a connError, the peer is sent a close frame.
+// readMu must be held.
+func (c *Conn) fail(err error) error {
+	if c.rerr != nil {
+		return c.rerr
+	}
+	if ce, ok := err.(*connError); ok {
+		// Don't wait for a message being written.
+		if c.wmu.TryLock() {
+			if !c.closeSent {
+				c.closeSent = true
+				c.writeFrameLocked(true, false, opClose, closePayload(ce.code, ce.msg))
+			}
+			c.wmu.Unlock()
+		}
+	} else if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	select {
+	case <-c.closed:
+		err = net.ErrClosed
+	default:
+	}
+	c.rerr = err
+	c.CloseNow()
+	return err
+}
+
+// A messageReader reads a data message.
+type messageReader struct {
+	c     *Conn
+	ctx   context.Context
+	typ   MessageType
+	src   func([]byte) (int, error) // readFrames, or fr.Read
+	fr    io.ReadCloser             // decompressor, or nil
+	tail  int                       // bytes of deflateTail read
+	limit int64
+	n     int64 // bytes read
+	utf8  utf8Validator
+	err   error
+	done  func() // called when the message has been read, or nil
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+func (r *messageReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.src(p)
+	r.n += int64(n)
+	if r.limit >= 0 && r.n > r.limit {
+		err = r.c.fail(&connError{StatusMessageTooBig, "message too big"})
+	} else if r.typ == TextMessage && (!r.utf8.write(p[:n]) || err == io.EOF && !r.utf8.done()) {
+		err = r.c.fail(&connError{StatusInvalidPayloadData, "invalid UTF-8 in text message"})
+	} else if err != nil && err != io.EOF {
+		// Errors from the decompressor are not connErrors.
+		if r.fr != nil && r.c.rerr == nil {
+			err = &connError{StatusInvalidPayloadData, "invalid compressed data: " + err.Error()}
+		}
+		err = r.c.fail(err)
+	}
+	if r.fr != nil && r.c.takeover {
+		r.c.window = appendWindow(r.c.window, p[:n])
+	}
+	if err == io.EOF && r.fr != nil {
+		// The compressed data may end before the last frame does.
+		if _, derr := io.Copy(io.Discard, readerFunc(r.readFrames)); derr != nil {
+			err = derr
+		}
+	}
+	if err != nil {
+		r.finish(err)
+		if err != io.EOF {
+			err = ctxError(r.ctx, err)
+		}
+	}
+	return n, err
+}
+
+// finish records the end of the message.
+func (r *messageReader) finish(err error) {
+	r.err = err
+	if r.fr != nil {
+		putFlateReader(r.fr)
+		r.fr = nil
+	}
+	if r.done != nil {
+		r.done()
+		r.done = nil
+	}
+}
+
+// appendWindow appends p to the window of recent data,
+// keeping only the last maxWindow bytes.
+func appendWindow(window, p []byte) []byte {
+	if len(p) >= maxWindow {
+		return append(window[:0], p[len(p)-maxWindow:]...)
+	}
+	if len(window)+len(p) > maxWindow {
+		window = window[:copy(window, window[len(window)+len(p)-maxWindow:])]
+	}
+	return append(window, p...)
+}
+
+// readFrames reads the payload of the frames of the message.
+func (r *messageReader) readFrames(p []byte) (int, error) {
+	c := r.c
+	for c.rremain == 0 {
+		if c.rfin {
+			return 0, io.EOF
+		}
+		h, err := c.nextDataFrame()
+		if err != nil {
+			return 0, err
+		}
+		if h.opcode != opContinuation {
+			return 0, c.fail(protocolError("expected continuation frame"))
+		}
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := c.readPayload(p)
+	if err == io.EOF && c.rremain > 0 {
+		return n, c.fail(io.ErrUnexpectedEOF)
+	}
+	if err != nil && err != io.EOF {
+		return n, c.fail(err)
+	}
+	return n, nil
+}
+
+// readTail reads deflateTail, after the compressed data of the message.
+func (r *messageReader) readTail(p []byte) (int, error) {
+	n := copy(p, deflateTail[r.tail:])
+	r.tail += n
+	if r.tail == len(deflateTail) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Write writes a data message to the connection.
+// If ctx is done before the message is written, the connection is closed.
+func (c *Conn) Write(ctx context.Context, typ MessageType, p []byte) error {
+	w, err := c.Writer(ctx, typ)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(p); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Writer returns a writer for a data message. The message is sent in
+// frames as it is written, and ends when the writer is closed; until
+// then, other messages cannot be written. Text messages must be UTF-8.
+//
+// If ctx is done before the writer is closed, the connection is closed.
+func (c *Conn) Writer(ctx context.Context, typ MessageType) (io.WriteCloser, error) {
+	if typ != TextMessage && typ != BinaryMessage {
+		return nil, errors.New("websocket: invalid message type " + typ.String())
+	}
+	c.msgMu.Lock()
+	w := &messageWriter{
+		c:    c,
+		ctx:  ctx,
+		op:   byte(typ),
+		stop: context.AfterFunc(ctx, func() { c.CloseNow() }),
+	}
+	if c.compress {
+		w.fw = getFlateWriter((*messageBuffer)(w))
+	}
+	return w, nil
+}
+
+// A messageWriter writes a data message.
+type messageWriter struct {
+	c      *Conn
+	ctx    context.Context
+	op     byte // opcode of the next frame
+	buf    []byte
+	fw     *flate.Writer // compressor, or nil
+	rsv1   bool          // the first frame has been written
+	err    error
+	stop   func() bool
+	closed bool
+}
+
+// A messageBuffer is the destination of a messageWriter's compressor.
+type messageBuffer messageWriter
+
+func (b *messageBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("websocket: write to closed message writer")
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.fw != nil {
+		w.fw.Write(p)
+	} else {
+		w.buf = append(w.buf, p...)
+	}
+	// With compression, hold back the last four bytes, which may be
+	// the end of the block that the compressor writes when flushed.
+	hold := 0
+	if w.fw != nil {
+		hold = 4
+	}
+	off := 0
+	for len(w.buf)-off > fragmentSize+hold {
+		if err := w.writeFrame(false, w.buf[off:off+fragmentSize]); err != nil {
+			return 0, err
+		}
+		off += fragmentSize
+	}
+	w.buf = w.buf[:copy(w.buf, w.buf[off:])]
+	return len(p), nil
+}
+
+func (w *messageWriter) writeFrame(fin bool, p []byte) error {
+	err := w.c.writeFrame(fin, w.fw != nil && w.op != opContinuation, w.op, p)
+	if err != nil {
+		w.err = ctxError(w.ctx, err)
+		return w.err
+	}
+	w.op = opContinuation
+	return nil
+}
+
+// Close writes the last frame of the message.
+func (w *messageWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	defer w.c.msgMu.Unlock()
+	defer w.stop()
+	if w.fw != nil {
+		w.fw.Flush()
+		putFlateWriter(w.fw)
+		// Remove the end of the empty stored block written by Flush
+		// (RFC 7692, Section 7.2.1).
+		w.buf = w.buf[:len(w.buf)-4]
+	}
+	if w.err != nil {
+		return w.err
+	}
+	return w.writeFrame(true, w.buf)
+}
+
+// writeFrame writes a frame.
+func (c *Conn) writeFrame(fin, rsv1 bool, op byte, p []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return ErrCloseSent
+	}
+	if op == opClose {
+		c.closeSent = true
+	}
+	return c.writeFrameLocked(fin, rsv1, op, p)
+}
+
+func (c *Conn) writeFrameLocked(fin, rsv1 bool, op byte, p []byte) error {
+	select {
+	case <-c.closed:
+		return net.ErrClosed
+	default:
+	}
+	var key *[4]byte
+	if c.client {
+		key = new([4]byte)
+		rand.Read(key[:])
+	}
+	var hdr [maxFrameHeader]byte
+	if _, err := c.bw.Write(appendFrameHeader(hdr[:0], fin, rsv1, op, len(p), key)); err != nil {
+		return err
+	}
+	if key == nil {
+		if _, err := c.bw.Write(p); err != nil {
+			return err
+		}
+	} else {
+		// Mask a copy of p, a piece at a time.
+		if c.wbuf == nil {
+			c.wbuf = make([]byte, 4096)
+		}
+		pos := 0
+		for len(p) > 0 {
+			n := copy(c.wbuf, p)
+			p = p[n:]
+			pos = mask(*key, pos, c.wbuf[:n])
+			if _, err := c.bw.Write(c.wbuf[:n]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.bw.Flush(); err != nil {
+		return err
+	}
+	if c.flush != nil {
+		return c.flush()
+	}
+	return nil
+}
+
+// writeClose writes a close frame, unless one has already been sent.
+func (c *Conn) writeClose(code StatusCode, reason string) error {
+	return c.writeFrame(true, false, opClose, closePayload(code, reason))
+}
+
+// closePayload returns the payload of a close frame.
+func closePayload(code StatusCode, reason string) []byte {
+	if code == StatusNoStatusReceived {
+		return nil
+	}
+	if len(reason) > maxControlPayload-2 {
+		reason = reason[:maxControlPayload-2]
+	}
+	p := []byte{byte(code >> 8), byte(code)}
+	return append(p, reason...)
+}
+
+// Ping sends a ping to the peer and waits for its pong.
+// The pong is received by a concurrent call to Read or Reader,
+// which must be in progress for Ping to return.
+func (c *Conn) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	c.pingCount++
+	payload := strconv.Itoa(c.pingCount)
+	ch := make(chan struct{})
+	if c.pings == nil {
+		c.pings = make(map[string]chan struct{})
+	}
+	c.pings[payload] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pings, payload)
+		c.mu.Unlock()
+	}()
+
+	if err := c.writeFrame(true, false, opPing, []byte(payload)); err != nil {
+		return err
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-c.closed:
+		return net.ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close performs the closing handshake. It sends a close frame with
+// the given status code and reason, waits for the peer's close frame,
+// and closes the connection. If the peer does not answer within five
+// seconds, Close closes the connection and returns an error.
+//
+// The reason must be UTF-8, and no longer than 123 bytes.
+// Data messages received before the peer's close frame are discarded,
+// unless a call to Read or Reader is in progress.
+func (c *Conn) Close(code StatusCode, reason string) error {
+	if !code.validSend() {
+		return errors.New("websocket: invalid close status " + strconv.Itoa(int(code)))
+	}
+	if len(reason) > maxControlPayload-2 || !utf8.ValidString(reason) {
+		return errors.New("websocket: invalid close reason")
+	}
+	if err := c.writeClose(code, reason); err != nil {
+		c.CloseNow()
+		return err
+	}
+	timer := time.AfterFunc(closeTimeout, func() { c.CloseNow() })
+	defer timer.Stop()
+	if c.readMu.TryLock() {
+		for c.rerr == nil {
+			if _, r, err := c.nextMessage(); err == nil {
+				io.Copy(io.Discard, r)
+			}
+		}
+		c.readMu.Unlock()
+	} else {
+		select {
+		case <-c.closeRecv:
+		case <-c.closed:
+		}
+	}
+	c.CloseNow()
+	select {
+	case <-c.closeRecv:
+		return nil
+	default:
+		return errors.New("websocket: peer did not complete the closing handshake")
+	}
+}
+
+// CloseNow closes the connection without a closing handshake.
+func (c *Conn) CloseNow() error {
+	err := net.ErrClosed
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.rwc.Close()
+	})
+	return err
+}
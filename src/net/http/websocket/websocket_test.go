@@ -0,0 +1,529 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package websocket_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "net/http/websocket"
+)
+
+// echoHandler returns a handler echoing the messages it receives.
+// Errors are sent to errc, which may be nil.
+func echoHandler(t *testing.T, opts *UpgradeOptions, errc chan<- error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r, opts)
+		if err != nil {
+			t.Logf("Upgrade: %v", err)
+			return
+		}
+		defer c.CloseNow()
+		ctx := context.Background()
+		for {
+			typ, b, err := c.Read(ctx)
+			if err != nil {
+				if errc != nil {
+					errc <- err
+				}
+				return
+			}
+			if err := c.Write(ctx, typ, b); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func wsURL(s *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func dial(t *testing.T, s *httptest.Server, opts *DialOptions) *Conn {
+	t.Helper()
+	if opts == nil {
+		opts = &DialOptions{}
+	}
+	if opts.Client == nil {
+		opts.Client = s.Client()
+	}
+	c, resp, err := Dial(context.Background(), wsURL(s), opts)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Dial: status = %v, want 101", resp.Status)
+	}
+	t.Cleanup(func() { c.CloseNow() })
+	return c
+}
+
+func TestEcho(t *testing.T) {
+	big := bytes.Repeat([]byte("0123456789abcdef"), 10000) // spans several frames
+	for _, tt := range []struct {
+		name     string
+		tls      bool
+		compress bool
+	}{
+		{"plain", false, false},
+		{"deflate", false, true},
+		{"tls", true, false},
+		{"tls-deflate", true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := echoHandler(t, &UpgradeOptions{Compression: true}, nil)
+			var s *httptest.Server
+			if tt.tls {
+				s = httptest.NewUnstartedServer(h)
+				s.EnableHTTP2 = true
+				s.StartTLS()
+			} else {
+				s = httptest.NewServer(h)
+			}
+			defer s.Close()
+			c, resp, err := Dial(context.Background(), wsURL(s), &DialOptions{
+				Client:      s.Client(),
+				Compression: tt.compress,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.CloseNow()
+			if got := resp.Header.Get("Sec-Websocket-Extensions") != ""; got != tt.compress {
+				t.Errorf("permessage-deflate negotiated = %v, want %v", got, tt.compress)
+			}
+
+			ctx := context.Background()
+			for _, m := range []struct {
+				typ  MessageType
+				data []byte
+			}{
+				{TextMessage, []byte("hello, 世界")},
+				{BinaryMessage, []byte{0, 1, 2, 0xff}},
+				{TextMessage, nil},
+				{BinaryMessage, big},
+				{TextMessage, []byte("hello again")},
+			} {
+				if err := c.Write(ctx, m.typ, m.data); err != nil {
+					t.Fatalf("Write: %v", err)
+				}
+				typ, b, err := c.Read(ctx)
+				if err != nil {
+					t.Fatalf("Read: %v", err)
+				}
+				if typ != m.typ || !bytes.Equal(b, m.data) {
+					t.Fatalf("echo of %v message of %d bytes = %v message of %d bytes", m.typ, len(m.data), typ, len(b))
+				}
+			}
+
+			// A message written in pieces is received whole.
+			w, err := c.Writer(ctx, BinaryMessage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < len(big); i += 1000 {
+				w.Write(big[i:min(i+1000, len(big))])
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			typ, r, err := c.Reader(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := io.ReadAll(r)
+			if typ != BinaryMessage || !bytes.Equal(b, big) || err != nil {
+				t.Fatalf("echo of streamed message = %v message of %d bytes, %v", typ, len(b), err)
+			}
+
+			if err := c.Close(StatusNormalClosure, ""); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	errc := make(chan error, 1)
+	s := httptest.NewServer(echoHandler(t, nil, errc))
+	defer s.Close()
+	c := dial(t, s, nil)
+	if err := c.Close(StatusGoingAway, "bye"); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	err := <-errc
+	if ce, ok := err.(*CloseError); !ok || ce.Code != StatusGoingAway || ce.Reason != "bye" {
+		t.Errorf("server Read error = %v, want close status 1001 with reason bye", err)
+	}
+	if err := c.Write(context.Background(), TextMessage, []byte("x")); err != ErrCloseSent {
+		t.Errorf("Write after Close = %v, want ErrCloseSent", err)
+	}
+	if _, _, err := c.Read(context.Background()); err == nil {
+		t.Errorf("Read after Close succeeded")
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	closed := make(chan error, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		closed <- c.Close(StatusCode(4000), "done")
+	}))
+	defer s.Close()
+	c := dial(t, s, nil)
+	_, _, err := c.Read(context.Background())
+	if ce, ok := err.(*CloseError); !ok || ce.Code != 4000 || ce.Reason != "done" {
+		t.Errorf("Read error = %v, want close status 4000 with reason done", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("server Close: %v", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	s := httptest.NewServer(echoHandler(t, nil, nil))
+	defer s.Close()
+	c := dial(t, s, nil)
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		c.Read(ctx)
+	})
+	for range 3 {
+		if err := c.Ping(ctx); err != nil {
+			t.Fatalf("Ping: %v", err)
+		}
+	}
+	c.Close(StatusNormalClosure, "")
+	wg.Wait()
+}
+
+func TestReadLimit(t *testing.T) {
+	errc := make(chan error, 1)
+	s := httptest.NewServer(echoHandler(t, &UpgradeOptions{ReadLimit: 10}, errc))
+	defer s.Close()
+	c := dial(t, s, nil)
+	ctx := context.Background()
+	if err := c.Write(ctx, BinaryMessage, []byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Read(ctx); err != nil {
+		t.Fatalf("message at limit: %v", err)
+	}
+	c.Write(ctx, BinaryMessage, []byte("0123456789a"))
+	_, _, err := c.Read(ctx)
+	if ce, ok := err.(*CloseError); !ok || ce.Code != StatusMessageTooBig {
+		t.Errorf("Read error = %v, want close status 1009", err)
+	}
+	if err := <-errc; err == nil {
+		t.Errorf("server read message over limit")
+	}
+}
+
+func TestSubprotocol(t *testing.T) {
+	opts := &UpgradeOptions{Subprotocols: []string{"v2", "v1"}}
+	s := httptest.NewServer(echoHandler(t, opts, nil))
+	defer s.Close()
+	for _, tt := range []struct {
+		requested []string
+		want      string
+	}{
+		{nil, ""},
+		{[]string{"v1", "v2"}, "v2"},
+		{[]string{"v1"}, "v1"},
+		{[]string{"v3"}, ""},
+	} {
+		c := dial(t, s, &DialOptions{Subprotocols: tt.requested})
+		if got := c.Subprotocol(); got != tt.want {
+			t.Errorf("requesting %q: Subprotocol() = %q, want %q", tt.requested, got, tt.want)
+		}
+		c.CloseNow()
+	}
+}
+
+func TestBadHandshake(t *testing.T) {
+	s := httptest.NewServer(echoHandler(t, nil, nil))
+	defer s.Close()
+
+	// A plain HTTP request.
+	resp, err := s.Client().Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("GET without upgrade: status %v, want 426", resp.Status)
+	}
+
+	// A cross-origin request.
+	_, resp, err = Dial(context.Background(), wsURL(s), &DialOptions{
+		Header: http.Header{"Origin": {"https://evil.example"}},
+	})
+	if !errors.Is(err, ErrBadHandshake) || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("cross-origin Dial: %v, %v; want 403 and ErrBadHandshake", resp, err)
+	}
+
+	// A same-origin request.
+	c, _, err := Dial(context.Background(), wsURL(s), &DialOptions{
+		Header: http.Header{"Origin": {s.URL}},
+	})
+	if err != nil {
+		t.Errorf("same-origin Dial: %v", err)
+	} else {
+		c.CloseNow()
+	}
+
+	// A server that does not speak WebSocket.
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	_, resp, err = Dial(context.Background(), wsURL(plain), nil)
+	if !errors.Is(err, ErrBadHandshake) || resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Dial to HTTP server: %v, %v; want 200 and ErrBadHandshake", resp, err)
+	}
+
+	if _, _, err := Dial(context.Background(), "ftp://example.com/", nil); err == nil {
+		t.Errorf("Dial with ftp URL succeeded")
+	}
+}
+
+func TestDialProxy(t *testing.T) {
+	s := httptest.NewTLSServer(echoHandler(t, nil, nil))
+	defer s.Close()
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "CONNECT" {
+			http.Error(w, "not CONNECT", http.StatusMethodNotAllowed)
+			return
+		}
+		proxied = append(proxied, r.Host)
+		dst, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		src, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			dst.Close()
+			return
+		}
+		go func() {
+			io.Copy(dst, brw)
+			dst.Close()
+		}()
+		io.Copy(src, dst)
+		src.Close()
+	}))
+	defer proxy.Close()
+
+	tr := s.Client().Transport.(*http.Transport).Clone()
+	proxyURL, _ := url.Parse(proxy.URL)
+	tr.Proxy = http.ProxyURL(proxyURL)
+	defer tr.CloseIdleConnections()
+	c := dial(t, s, &DialOptions{Client: &http.Client{Transport: tr}})
+	ctx := context.Background()
+	if err := c.Write(ctx, TextMessage, []byte("via proxy")); err != nil {
+		t.Fatal(err)
+	}
+	if _, b, err := c.Read(ctx); err != nil || string(b) != "via proxy" {
+		t.Fatalf("Read = %q, %v", b, err)
+	}
+	c.Close(StatusNormalClosure, "")
+	if want := strings.TrimPrefix(s.URL, "https://"); len(proxied) != 1 || proxied[0] != want {
+		t.Errorf("proxied connections = %q, want [%q]", proxied, want)
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	s := httptest.NewServer(echoHandler(t, nil, nil))
+	defer s.Close()
+	c := dial(t, s, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.Read(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Read = %v, want context.DeadlineExceeded", err)
+	}
+	// The connection is closed.
+	if err := c.Write(context.Background(), TextMessage, nil); err == nil {
+		t.Errorf("Write after canceled Read succeeded")
+	}
+}
+
+// A flushRecorder is an HTTP/2 ResponseWriter writing to a pipe.
+type flushRecorder struct {
+	header http.Header
+	code   int
+	w      *io.PipeWriter
+}
+
+func (r *flushRecorder) Header() http.Header         { return r.header }
+func (r *flushRecorder) WriteHeader(code int)        { r.code = code }
+func (r *flushRecorder) Write(p []byte) (int, error) { return r.w.Write(p) }
+func (r *flushRecorder) Flush()                      {}
+
+func TestUpgradeHTTP2(t *testing.T) {
+	// The stream of an extended CONNECT request (RFC 8441).
+	reqBody, clientOut := io.Pipe()
+	clientIn, respBody := io.Pipe()
+	req := httptest.NewRequest("CONNECT", "https://example.com/chat", reqBody)
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/2.0", 2, 0
+	req.Header.Set(":protocol", "websocket")
+	req.Header.Set("Sec-Websocket-Version", "13")
+	req.Header.Set("Sec-Websocket-Protocol", "chat")
+	rec := &flushRecorder{header: make(http.Header), w: respBody}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c, err := Upgrade(rec, req, &UpgradeOptions{Subprotocols: []string{"chat"}})
+		if err != nil {
+			t.Errorf("Upgrade: %v", err)
+			return
+		}
+		if rec.code != http.StatusOK || rec.header.Get("Sec-Websocket-Protocol") != "chat" {
+			t.Errorf("response: %d %v, want 200 with subprotocol chat", rec.code, rec.header)
+		}
+		ctx := context.Background()
+		typ, b, err := c.Read(ctx)
+		if err != nil {
+			t.Errorf("server Read: %v", err)
+			return
+		}
+		c.Write(ctx, typ, b)
+		c.Read(ctx)
+		respBody.Close()
+	}()
+
+	c := NewConn(struct {
+		io.Reader
+		io.Writer
+		io.Closer
+	}{clientIn, clientOut, clientOut}, true, false)
+	ctx := context.Background()
+	if err := c.Write(ctx, TextMessage, []byte("over h2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, b, err := c.Read(ctx); err != nil || string(b) != "over h2" {
+		t.Errorf("Read = %q, %v", b, err)
+	}
+	c.CloseNow()
+	<-done
+}
+
+// TestProtocolErrors checks that invalid frames fail the connection
+// with the appropriate status code.
+func TestProtocolErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		frame []byte // sent by the client, unmasked unless the mask bit is set
+		code  StatusCode
+	}{
+		{"unmasked", []byte{0x81, 0x01, 'a'}, StatusProtocolError},
+		{"reserved bits", []byte{0xa1, 0x80, 0, 0, 0, 0}, StatusProtocolError},
+		{"unknown opcode", []byte{0x83, 0x80, 0, 0, 0, 0}, StatusProtocolError},
+		{"continuation", []byte{0x80, 0x80, 0, 0, 0, 0}, StatusProtocolError},
+		{"fragmented ping", []byte{0x09, 0x80, 0, 0, 0, 0}, StatusProtocolError},
+		{"invalid UTF-8", []byte{0x81, 0x81, 0, 0, 0, 0, 0xff}, StatusInvalidPayloadData},
+		{"compressed", []byte{0xc1, 0x80, 0, 0, 0, 0}, StatusProtocolError},
+		{"invalid close code", []byte{0x88, 0x82, 0, 0, 0, 0, 0x03, 0xed}, StatusProtocolError},
+		{"interleaved message", []byte{0x01, 0x80, 0, 0, 0, 0, 0x81, 0x80, 0, 0, 0, 0}, StatusProtocolError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			c := NewConn(server, false, false)
+			defer c.CloseNow()
+			go client.Write(tt.frame)
+			errc := make(chan error, 1)
+			go func() {
+				_, _, err := c.Read(context.Background())
+				errc <- err
+			}()
+			// The server sends a close frame with the status code.
+			var hdr [2]byte
+			if _, err := io.ReadFull(client, hdr[:]); err != nil {
+				t.Fatalf("reading close frame: %v", err)
+			}
+			payload := make([]byte, hdr[1])
+			if _, err := io.ReadFull(client, payload); err != nil {
+				t.Fatalf("reading close frame: %v", err)
+			}
+			if err := <-errc; err == nil {
+				t.Errorf("Read succeeded")
+			}
+			if hdr[0] != 0x88 || len(payload) < 2 {
+				t.Fatalf("server sent frame %x %x, want close frame", hdr, payload)
+			}
+			if code := StatusCode(int(payload[0])<<8 | int(payload[1])); code != tt.code {
+				t.Errorf("close status = %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
+
+// TestFragmentedMessage checks that a message sent in several frames,
+// interleaved with a ping, is received whole and the ping answered.
+func TestFragmentedMessage(t *testing.T) {
+	server, client := net.Pipe()
+	c := NewConn(server, false, false)
+	defer c.CloseNow()
+	go client.Write([]byte{
+		0x01, 0x83, 0, 0, 0, 0, 'a', 'b', 'c', // text, not final
+		0x89, 0x82, 0, 0, 0, 0, 'h', 'i', // ping
+		0x00, 0x80, 0, 0, 0, 0, // empty continuation
+		0x80, 0x82, 0, 0, 0, 0, 'd', 'e', // final continuation
+	})
+	errc := make(chan error, 1)
+	go func() {
+		typ, b, err := c.Read(context.Background())
+		if err == nil && (typ != TextMessage || string(b) != "abcde") {
+			err = errors.New("read " + typ.String() + " message " + string(b))
+		}
+		errc <- err
+	}()
+	br := bufio.NewReader(client)
+	pong := make([]byte, 4)
+	if _, err := io.ReadFull(br, pong); err != nil {
+		t.Fatal(err)
+	}
+	if string(pong) != "\x8a\x02hi" {
+		t.Errorf("reply to ping = %q, want pong", pong)
+	}
+	if err := <-errc; err != nil {
+		t.Error(err)
+	}
+}
+
+// TestCompressedContextTakeover checks that compressed messages from a
+// peer using context takeover are decompressed.
+func TestCompressedContextTakeover(t *testing.T) {
+	server, client := net.Pipe()
+	c := NewConn(server, false, true)
+	defer c.CloseNow()
+	// "Hello" twice, compressed with context takeover, from the
+	// example in RFC 7692, Section 7.2.3.2.
+	go client.Write([]byte{
+		0xc1, 0x87, 0, 0, 0, 0, 0xf2, 0x48, 0xcd, 0xc9, 0xc9, 0x07, 0x00,
+		0xc1, 0x85, 0, 0, 0, 0, 0xf2, 0x00, 0x11, 0x00, 0x00,
+	})
+	for range 2 {
+		_, b, err := c.Read(context.Background())
+		if err != nil || string(b) != "Hello" {
+			t.Fatalf("Read = %q, %v; want Hello", b, err)
+		}
+	}
+}
@@ -738,8 +738,10 @@ func (f *File) Fd() uintptr {
 //
 // The directory dir must not be "".
 //
-// The result implements [io/fs.StatFS], [io/fs.ReadFileFS], [io/fs.ReadDirFS], and
-// [io/fs.ReadLinkFS].
+// The result implements [io/fs.StatFS], [io/fs.ReadFileFS], [io/fs.ReadDirFS],
+// [io/fs.ReadLinkFS], and the write interfaces [io/fs.CreateFS], [io/fs.OpenFileFS],
+// [io/fs.WriteFileFS], [io/fs.MkdirFS], [io/fs.RemoveFS], [io/fs.RenameFS],
+// and [io/fs.ChmodFS].
 func DirFS(dir string) fs.FS {
 	return dirFS(dir)
 }
@@ -748,6 +750,13 @@ var _ fs.StatFS = dirFS("")
 var _ fs.ReadFileFS = dirFS("")
 var _ fs.ReadDirFS = dirFS("")
 var _ fs.ReadLinkFS = dirFS("")
+var _ fs.CreateFS = dirFS("")
+var _ fs.OpenFileFS = dirFS("")
+var _ fs.WriteFileFS = dirFS("")
+var _ fs.MkdirFS = dirFS("")
+var _ fs.RemoveFS = dirFS("")
+var _ fs.RenameFS = dirFS("")
+var _ fs.ChmodFS = dirFS("")
 
 type dirFS string
 
@@ -848,6 +857,103 @@ func (dir dirFS) ReadLink(name string) (string, error) {
 	return f, nil
 }
 
+func (dir dirFS) Create(name string) (fs.WritableFile, error) {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	f, err := Create(fullname)
+	if err != nil {
+		// See comment in dirFS.Open.
+		err.(*PathError).Path = name
+		return nil, err
+	}
+	return f, nil
+}
+
+func (dir dirFS) OpenFile(name string, flag int, perm FileMode) (fs.File, error) {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return nil, &PathError{Op: "open", Path: name, Err: err}
+	}
+	f, err := OpenFile(fullname, flag, perm)
+	if err != nil {
+		// See comment in dirFS.Open.
+		err.(*PathError).Path = name
+		return nil, err
+	}
+	return f, nil
+}
+
+func (dir dirFS) WriteFile(name string, data []byte, perm FileMode) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "open", Path: name, Err: err}
+	}
+	err = WriteFile(fullname, data, perm)
+	if e, ok := err.(*PathError); ok {
+		// See comment in dirFS.Open.
+		e.Path = name
+	}
+	return err
+}
+
+func (dir dirFS) Mkdir(name string, perm FileMode) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	err = Mkdir(fullname, perm)
+	if e, ok := err.(*PathError); ok {
+		// See comment in dirFS.Open.
+		e.Path = name
+	}
+	return err
+}
+
+func (dir dirFS) Remove(name string) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "remove", Path: name, Err: err}
+	}
+	err = Remove(fullname)
+	if e, ok := err.(*PathError); ok {
+		// See comment in dirFS.Open.
+		e.Path = name
+	}
+	return err
+}
+
+func (dir dirFS) Rename(oldname, newname string) error {
+	fulloldname, err := dir.join(oldname)
+	if err != nil {
+		return &LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	fullnewname, err := dir.join(newname)
+	if err != nil {
+		return &LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	err = Rename(fulloldname, fullnewname)
+	if e, ok := err.(*LinkError); ok {
+		// See comment in dirFS.Open.
+		e.Old, e.New = oldname, newname
+	}
+	return err
+}
+
+func (dir dirFS) Chmod(name string, mode FileMode) error {
+	fullname, err := dir.join(name)
+	if err != nil {
+		return &PathError{Op: "chmod", Path: name, Err: err}
+	}
+	err = Chmod(fullname, mode)
+	if e, ok := err.(*PathError); ok {
+		// See comment in dirFS.Open.
+		e.Path = name
+	}
+	return err
+}
+
 // join returns the path for name in dir.
 func (dir dirFS) join(name string) (string, error) {
 	if dir == "" {
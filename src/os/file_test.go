@@ -5,10 +5,12 @@
 package os_test
 
 import (
+	"errors"
 	"internal/testenv"
 	"io/fs"
 	. "os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -180,3 +182,88 @@ func TestDirFSWalkDir(t *testing.T) {
 		}
 	})
 }
+
+func TestDirFSWrite(t *testing.T) {
+	dir := t.TempDir()
+	testFSWrite(t, dir, DirFS(dir))
+}
+
+func TestRootFSWrite(t *testing.T) {
+	dir := t.TempDir()
+	root, err := OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	testFSWrite(t, dir, root.FS())
+
+	if err := fs.WriteFile(root.FS(), "../escape", nil, 0o666); err == nil {
+		t.Errorf("WriteFile outside of root succeeded")
+	}
+}
+
+// testFSWrite tests the write methods of fsys, which is rooted at dir.
+func testFSWrite(t *testing.T, dir string, fsys fs.FS) {
+	if err := fs.MkdirAll(fsys, "a/b", 0o777); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fs.Create(fsys, "a/b/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	af, err := fs.OpenFile(fsys, "a/b/file", O_WRONLY|O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := af.(fs.WritableFile).Write([]byte(", world")); err != nil {
+		t.Fatal(err)
+	}
+	af.Close()
+	if err := fs.Rename(fsys, "a/b/file", "a/renamed"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ReadFile(filepath.Join(dir, "a", "renamed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "hello, world"; got != want {
+		t.Errorf("a/renamed contains %q, want %q", got, want)
+	}
+
+	if err := fs.WriteFile(fsys, "a/w", []byte("data"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Chmod(fsys, "a/w", 0o400); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "wasip1" {
+		info, err := Stat(filepath.Join(dir, "a", "w"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != 0o400 {
+			t.Errorf("after Chmod: mode = %v, want %v", got, fs.FileMode(0o400))
+		}
+	}
+
+	if err := fs.Remove(fsys, "a/b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Remove(fsys, "a/missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove of missing file: %v, want ErrNotExist", err)
+	} else if pe, ok := err.(*PathError); !ok || pe.Path != "a/missing" {
+		t.Errorf("Remove of missing file: error %v, want PathError with path %q", err, "a/missing")
+	}
+	if _, err := fs.Create(fsys, "/abs"); err == nil {
+		t.Errorf("Create of absolute path succeeded")
+	}
+	if _, err := Stat(filepath.Join(dir, "a", "b")); !IsNotExist(err) {
+		t.Errorf("a/b exists after Remove: %v", err)
+	}
+}
@@ -259,6 +259,121 @@ func (r *Root) ReadFile(name string) ([]byte, error) {
 	return readFileContents(statOrZero(f), f.Read)
 }
 
+// ReadDir reads the named directory in the root,
+// returning all its directory entries sorted by filename.
+// See [ReadDir] for more details.
+func (r *Root) ReadDir(name string) ([]DirEntry, error) {
+	// This isn't efficient: We just open a regular file and ReadDir it.
+	// Ideally, we would skip creating a *File entirely and operate directly
+	// on the file descriptor, but that will require some extensive reworking
+	// of directory reading in general.
+	//
+	// This suffices for the moment.
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dirs, err := f.ReadDir(-1)
+	slices.SortFunc(dirs, func(a, b DirEntry) int {
+		return bytealg.CompareString(a.Name(), b.Name())
+	})
+	return dirs, err
+}
+
+// WalkDir walks the file tree rooted at name in the root,
+// calling fn for each file or directory in the tree, including name.
+// See [io/fs.WalkDir] for details of fn and of the order of the walk.
+//
+// WalkDir does not follow symbolic links, including name itself if it
+// is a symbolic link. A symbolic link is passed to fn as an entry of
+// type [ModeSymlink] and is not descended into, even if it refers
+// to a directory within the root. If a directory is replaced during
+// the walk, WalkDir reports an error for it to fn rather than
+// reading the replacement.
+//
+// Like [path/filepath.WalkDir], WalkDir calls fn with paths that use
+// the separator character appropriate for the operating system.
+// The paths are relative to the root, starting with name.
+func (r *Root) WalkDir(name string, fn fs.WalkDirFunc) error {
+	info, err := r.Lstat(name)
+	if err != nil {
+		err = fn(name, nil, err)
+	} else {
+		err = r.walkDir(name, fs.FileInfoToDirEntry(info), fn)
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkDir recursively descends name, calling fn.
+func (r *Root) walkDir(name string, d DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			// Successfully skipped directory.
+			err = nil
+		}
+		return err
+	}
+
+	dirs, err := r.readDirNoFollow(name, d)
+	if err != nil {
+		// Second call, to report ReadDir error.
+		err = fn(name, d, err)
+		if err != nil {
+			if err == fs.SkipDir && d.IsDir() {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, d1 := range dirs {
+		name1 := joinPath(name, d1.Name())
+		if name == "." {
+			name1 = d1.Name()
+		}
+		if err := r.walkDir(name1, d1, fn); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// readDirNoFollow is like ReadDir, but returns an error if name
+// is no longer the directory described by d, such as when it has
+// been replaced by a symbolic link after d was read.
+func (r *Root) readDirNoFollow(name string, d DirEntry) ([]DirEntry, error) {
+	want, err := d.Info()
+	if err != nil {
+		return nil, err
+	}
+	f, err := r.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	got, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !SameFile(want, got) {
+		return nil, &PathError{Op: "readdir", Path: name, Err: errDirChanged}
+	}
+	dirs, err := f.ReadDir(-1)
+	slices.SortFunc(dirs, func(a, b DirEntry) int {
+		return bytealg.CompareString(a.Name(), b.Name())
+	})
+	return dirs, err
+}
+
+var errDirChanged = errors.New("directory changed during walk")
+
 // WriteFile writes data to the named file in the root, creating it if necessary.
 // See [WriteFile] for more details.
 func (r *Root) WriteFile(name string, data []byte, perm FileMode) error {
@@ -355,7 +470,9 @@ func splitPathInRoot(s string, prefix, suffix []string) (_ []string, endsInSlash
 // FS returns a file system (an fs.FS) for the tree of files in the root.
 //
 // The result implements [io/fs.StatFS], [io/fs.ReadFileFS],
-// [io/fs.ReadDirFS], and [io/fs.ReadLinkFS].
+// [io/fs.ReadDirFS], [io/fs.ReadLinkFS], and the write interfaces
+// [io/fs.CreateFS], [io/fs.OpenFileFS], [io/fs.WriteFileFS],
+// [io/fs.MkdirFS], [io/fs.RemoveFS], [io/fs.RenameFS], and [io/fs.ChmodFS].
 func (r *Root) FS() fs.FS {
 	return (*rootFS)(r)
 }
@@ -379,23 +496,7 @@ func (rfs *rootFS) ReadDir(name string) ([]DirEntry, error) {
 	if !isValidRootFSPath(name) {
 		return nil, &PathError{Op: "readdir", Path: name, Err: ErrInvalid}
 	}
-
-	// This isn't efficient: We just open a regular file and ReadDir it.
-	// Ideally, we would skip creating a *File entirely and operate directly
-	// on the file descriptor, but that will require some extensive reworking
-	// of directory reading in general.
-	//
-	// This suffices for the moment.
-	f, err := r.Open(name)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	dirs, err := f.ReadDir(-1)
-	slices.SortFunc(dirs, func(a, b DirEntry) int {
-		return bytealg.CompareString(a.Name(), b.Name())
-	})
-	return dirs, err
+	return r.ReadDir(name)
 }
 
 func (rfs *rootFS) ReadFile(name string) ([]byte, error) {
@@ -435,6 +536,70 @@ func (rfs *rootFS) Lstat(name string) (FileInfo, error) {
 	return r.Lstat(name)
 }
 
+func (rfs *rootFS) Create(name string) (fs.WritableFile, error) {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return nil, &PathError{Op: "open", Path: name, Err: ErrInvalid}
+	}
+	f, err := r.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (rfs *rootFS) OpenFile(name string, flag int, perm FileMode) (fs.File, error) {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return nil, &PathError{Op: "open", Path: name, Err: ErrInvalid}
+	}
+	f, err := r.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (rfs *rootFS) WriteFile(name string, data []byte, perm FileMode) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "open", Path: name, Err: ErrInvalid}
+	}
+	return r.WriteFile(name, data, perm)
+}
+
+func (rfs *rootFS) Mkdir(name string, perm FileMode) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "mkdir", Path: name, Err: ErrInvalid}
+	}
+	return r.Mkdir(name, perm)
+}
+
+func (rfs *rootFS) Remove(name string) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "remove", Path: name, Err: ErrInvalid}
+	}
+	return r.Remove(name)
+}
+
+func (rfs *rootFS) Rename(oldname, newname string) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(oldname) || !isValidRootFSPath(newname) {
+		return &LinkError{Op: "rename", Old: oldname, New: newname, Err: ErrInvalid}
+	}
+	return r.Rename(oldname, newname)
+}
+
+func (rfs *rootFS) Chmod(name string, mode FileMode) error {
+	r := (*Root)(rfs)
+	if !isValidRootFSPath(name) {
+		return &PathError{Op: "chmod", Path: name, Err: ErrInvalid}
+	}
+	return r.Chmod(name, mode)
+}
+
 // isValidRootFSPath reports whether name is a valid filename to pass a Root.FS method.
 func isValidRootFSPath(name string) bool {
 	if !fs.ValidPath(name) {
@@ -1975,6 +1975,155 @@ func TestRootWriteReadFile(t *testing.T) {
 	}
 }
 
+func TestRootReadDir(t *testing.T) {
+	dir := makefs(t, []string{
+		"a/c",
+		"a/b",
+		"link => a",
+	})
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	for _, name := range []string{"a", "link"} {
+		entries, err := root.ReadDir(name)
+		if err != nil {
+			t.Fatalf("root.ReadDir(%q) = %v", name, err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if want := []string{"b", "c"}; !slices.Equal(names, want) {
+			t.Errorf("root.ReadDir(%q) = %v, want %v", name, names, want)
+		}
+	}
+	if _, err := root.ReadDir(".."); err == nil {
+		t.Errorf(`root.ReadDir("..") succeeded, want error`)
+	}
+}
+
+func TestRootWalkDir(t *testing.T) {
+	dir := makefs(t, []string{
+		"a/b/f",
+		"a/c/",
+		"inside => a",
+		"outside => ../",
+		"absolute => $ABS/a",
+	})
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	type visit struct {
+		path string
+		typ  fs.FileMode
+	}
+	walk := func(name string, skip string) []visit {
+		var got []visit
+		err := root.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				t.Errorf("%s: %v", path, err)
+				return err
+			}
+			got = append(got, visit{filepath.ToSlash(path), d.Type()})
+			if path == filepath.FromSlash(skip) {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("root.WalkDir(%q) = %v", name, err)
+		}
+		return got
+	}
+
+	got := walk(".", "")
+	want := []visit{
+		{".", fs.ModeDir},
+		{"a", fs.ModeDir},
+		{"a/b", fs.ModeDir},
+		{"a/b/f", 0},
+		{"a/c", fs.ModeDir},
+		{"absolute", fs.ModeSymlink},
+		{"inside", fs.ModeSymlink},
+		{"outside", fs.ModeSymlink},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("root.WalkDir(.) visited:\n%v\nwant:\n%v", got, want)
+	}
+
+	got = walk("a", "a/b")
+	want = []visit{
+		{"a", fs.ModeDir},
+		{"a/b", fs.ModeDir},
+		{"a/c", fs.ModeDir},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("root.WalkDir(a) with SkipDir visited:\n%v\nwant:\n%v", got, want)
+	}
+
+	// A symbolic link at the start of the walk is not followed.
+	got = walk("outside", "")
+	want = []visit{{"outside", fs.ModeSymlink}}
+	if !slices.Equal(got, want) {
+		t.Errorf("root.WalkDir(outside) visited:\n%v\nwant:\n%v", got, want)
+	}
+
+	err = root.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if err == nil {
+		t.Errorf(`root.WalkDir("..") succeeded, want error`)
+	}
+}
+
+func TestRootWalkDirReplaced(t *testing.T) {
+	testenv.MustHaveSymlink(t)
+	dir := makefs(t, []string{
+		"a/f",
+		"b/g",
+		"target/secret",
+	})
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+
+	// Replace b with a symbolic link after it has been read
+	// from its parent directory, but before it is walked.
+	var walkErr error
+	err = root.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			walkErr = err
+			return fs.SkipDir
+		}
+		if path == "a" {
+			if err := root.RemoveAll("b"); err != nil {
+				t.Fatal(err)
+			}
+			if err := root.Symlink("target", "b"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if path == filepath.Join("b", "secret") {
+			t.Errorf("walk followed replaced directory to %q", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if walkErr == nil {
+		t.Errorf("walk of replaced directory reported no error")
+	}
+}
+
 func TestRootName(t *testing.T) {
 	dir := t.TempDir()
 	root, err := os.OpenRoot(dir)
@@ -5,8 +5,10 @@
 package fstest
 
 import (
+	"errors"
 	"io"
 	"io/fs"
+	"os"
 	"path"
 	"slices"
 	"strings"
@@ -30,6 +32,11 @@ import (
 // Another implication is that opening or reading a directory requires
 // iterating over the entire map, so a MapFS should typically be used with not more
 // than a few hundred entries or directory reads.
+//
+// MapFS also implements the write interfaces of package fs, such as
+// [fs.CreateFS] and [fs.MkdirFS], by modifying the map. Like other
+// changes to the map, these must not run concurrently with other
+// file system operations.
 type MapFS map[string]*MapFile
 
 // A MapFile describes a single file in a [MapFS].
@@ -42,6 +49,13 @@ type MapFile struct {
 
 var _ fs.FS = MapFS(nil)
 var _ fs.ReadLinkFS = MapFS(nil)
+var _ fs.CreateFS = MapFS(nil)
+var _ fs.OpenFileFS = MapFS(nil)
+var _ fs.WriteFileFS = MapFS(nil)
+var _ fs.MkdirFS = MapFS(nil)
+var _ fs.RemoveFS = MapFS(nil)
+var _ fs.RenameFS = MapFS(nil)
+var _ fs.ChmodFS = MapFS(nil)
 var _ fs.File = (*openMapFile)(nil)
 
 // Open opens the named file after following any symbolic links.
@@ -341,3 +355,286 @@ func (d *mapDir) ReadDir(count int) ([]fs.DirEntry, error) {
 	d.offset += n
 	return list, nil
 }
+
+// errNotEmpty is returned when removing or replacing a directory that is not empty.
+var errNotEmpty = errors.New("directory not empty")
+
+// Create creates or truncates the named file, as described by [fs.CreateFS].
+func (fsys MapFS) Create(name string) (fs.WritableFile, error) {
+	f, err := fsys.openFile("open", name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// OpenFile opens the named file with the specified flag, as described
+// by [fs.OpenFileFS]. A file created by OpenFile is added to the map
+// with mode perm; there is no umask.
+func (fsys MapFS) OpenFile(name string, flag int, perm fs.FileMode) (fs.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) == 0 {
+		return fsys.Open(name)
+	}
+	f, err := fsys.openFile("open", name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (fsys MapFS) openFile(op, name string, flag int, perm fs.FileMode) (*mapWriteFile, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	realName, ok := fsys.resolveSymlinks(name)
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if fsys.isDir(realName) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	file := fsys[realName]
+	switch {
+	case file == nil:
+		if flag&os.O_CREATE == 0 || !fsys.isDir(path.Dir(realName)) {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		file = &MapFile{Mode: perm & fs.ModePerm, ModTime: time.Now()}
+		fsys[realName] = file
+	case flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL:
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrExist}
+	case flag&os.O_TRUNC != 0 && flag&(os.O_WRONLY|os.O_RDWR) != 0:
+		file.Data = nil
+		file.ModTime = time.Now()
+	}
+	return &mapWriteFile{openMapFile{name, mapFileInfo{path.Base(name), file}, 0}, flag}, nil
+}
+
+// WriteFile writes data to the named file, creating it if necessary,
+// as described by [fs.WriteFileFS].
+func (fsys MapFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	f, err := fsys.openFile("open", name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	f.f.Data = slices.Clone(data)
+	return nil
+}
+
+// Mkdir adds a directory with the given permission bits to the map.
+// The parent directory must already exist.
+func (fsys MapFS) Mkdir(name string, perm fs.FileMode) error {
+	realName, err := fsys.resolveParent(name)
+	if err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	if _, err := fsys.lstat(name); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if !fsys.isDir(path.Dir(realName)) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrNotExist}
+	}
+	fsys[realName] = &MapFile{Mode: fs.ModeDir | perm&fs.ModePerm, ModTime: time.Now()}
+	return nil
+}
+
+// Remove removes the named file or empty directory from the map.
+// A directory that is synthesized from the names of the files
+// it contains is never empty, and so cannot be removed.
+func (fsys MapFS) Remove(name string) error {
+	realName, err := fsys.resolveParent(name)
+	if err == nil && realName == "." {
+		err = fs.ErrInvalid
+	}
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if fsys.hasChildren(realName) {
+		return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	if fsys[realName] == nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(fsys, realName)
+	return nil
+}
+
+// Rename renames (moves) oldname to newname, along with
+// everything it contains if it is a directory.
+// If newname already exists and is not a directory, Rename replaces it.
+// If newname is an empty directory, Rename replaces it with
+// the directory oldname.
+// As with [os.Rename], errors are of type [*os.LinkError].
+func (fsys MapFS) Rename(oldname, newname string) error {
+	linkError := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	oldReal, err := fsys.resolveParent(oldname)
+	if err != nil {
+		return linkError(err)
+	}
+	newReal, err := fsys.resolveParent(newname)
+	if err != nil {
+		return linkError(err)
+	}
+	oldInfo, err := fsys.lstat(oldname)
+	if err != nil {
+		return linkError(err)
+	}
+	if oldReal == "." || newReal == "." || strings.HasPrefix(newReal, oldReal+"/") {
+		return linkError(fs.ErrInvalid)
+	}
+	if oldReal == newReal {
+		return nil
+	}
+	if !fsys.isDir(path.Dir(newReal)) {
+		return linkError(fs.ErrNotExist)
+	}
+	if newInfo, err := fsys.lstat(newname); err == nil {
+		switch {
+		case oldInfo.IsDir() && !newInfo.IsDir():
+			return linkError(fs.ErrExist)
+		case !oldInfo.IsDir() && newInfo.IsDir():
+			return linkError(fs.ErrExist)
+		case newInfo.IsDir() && fsys.hasChildren(newReal):
+			return linkError(errNotEmpty)
+		}
+		delete(fsys, newReal)
+	}
+
+	if file := fsys[oldReal]; file != nil {
+		fsys[newReal] = file
+		delete(fsys, oldReal)
+	}
+	if oldInfo.IsDir() {
+		prefix := oldReal + "/"
+		for fname, file := range fsys {
+			if strings.HasPrefix(fname, prefix) {
+				fsys[newReal+"/"+fname[len(prefix):]] = file
+				delete(fsys, fname)
+			}
+		}
+	}
+	return nil
+}
+
+// Chmod changes the mode of the named file to mode,
+// after following any symbolic links.
+// Only the permission bits and the [fs.ModeSetuid], [fs.ModeSetgid]
+// and [fs.ModeSticky] bits of mode are used.
+func (fsys MapFS) Chmod(name string, mode fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrInvalid}
+	}
+	realName, ok := fsys.resolveSymlinks(name)
+	if !ok {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+	}
+	const chmodMask = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+	file := fsys[realName]
+	if file == nil {
+		if !fsys.isDir(realName) {
+			return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+		}
+		// Make a synthesized directory explicit to record its mode.
+		file = &MapFile{Mode: fs.ModeDir}
+		fsys[realName] = file
+	}
+	file.Mode = file.Mode&^chmodMask | mode&chmodMask
+	return nil
+}
+
+// resolveParent returns the name in the map for name,
+// following symbolic links in all but the final element.
+func (fsys MapFS) resolveParent(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", fs.ErrInvalid
+	}
+	realDir, ok := fsys.resolveSymlinks(path.Dir(name))
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+	return path.Join(realDir, path.Base(name)), nil
+}
+
+// isDir reports whether name, with symbolic links already resolved,
+// is a directory, either in the map or synthesized.
+func (fsys MapFS) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+	if file := fsys[name]; file != nil {
+		return file.Mode.IsDir()
+	}
+	return fsys.hasChildren(name)
+}
+
+// hasChildren reports whether any names in the map are
+// in the directory name, with symbolic links already resolved.
+func (fsys MapFS) hasChildren(name string) bool {
+	prefix := name + "/"
+	for fname := range fsys {
+		if name == "." && fname != "." || strings.HasPrefix(fname, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// A mapWriteFile is a regular (non-directory) fs.File open for writing,
+// and possibly also for reading.
+type mapWriteFile struct {
+	openMapFile
+	flag int
+}
+
+// accessMode returns the O_RDONLY, O_WRONLY or O_RDWR bits of f.flag.
+func (f *mapWriteFile) accessMode() int {
+	return f.flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR)
+}
+
+func (f *mapWriteFile) Read(b []byte) (int, error) {
+	if f.accessMode() == os.O_WRONLY {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrPermission}
+	}
+	return f.openMapFile.Read(b)
+}
+
+func (f *mapWriteFile) ReadAt(b []byte, offset int64) (int, error) {
+	if f.accessMode() == os.O_WRONLY {
+		return 0, &fs.PathError{Op: "read", Path: f.path, Err: fs.ErrPermission}
+	}
+	return f.openMapFile.ReadAt(b, offset)
+}
+
+func (f *mapWriteFile) Write(b []byte) (int, error) {
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(f.f.Data))
+	}
+	n, err := f.WriteAt(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *mapWriteFile) WriteAt(b []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "write", Path: f.path, Err: fs.ErrInvalid}
+	}
+	if f.accessMode() == os.O_RDONLY {
+		return 0, &fs.PathError{Op: "write", Path: f.path, Err: fs.ErrPermission}
+	}
+	data := f.f.Data
+	if end := offset + int64(len(b)); end > int64(len(data)) {
+		// Clip data so that growing it never writes to spare
+		// capacity in a slice owned by the caller.
+		n := len(data)
+		data = slices.Grow(slices.Clip(data), int(end)-n)[:end]
+		if offset > int64(n) {
+			clear(data[n:offset])
+		}
+	}
+	copy(data[offset:], b)
+	f.f.Data = data
+	f.f.ModTime = time.Now()
+	return len(b), nil
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"os"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -144,3 +146,141 @@ func TestMapFSSeek(t *testing.T) {
 		t.Errorf("Seek: expected fs.ErrInvalid, got %v", err)
 	}
 }
+
+func TestMapFSWrite(t *testing.T) {
+	m := MapFS{
+		"dir/file": {Data: []byte("old")},
+		"link":     {Data: []byte("dir"), Mode: fs.ModeSymlink},
+	}
+
+	f, err := m.Create("link/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(m["dir/new"].Data); got != "hello" {
+		t.Errorf("after Create: dir/new = %q, want %q", got, "hello")
+	}
+
+	af, err := m.OpenFile("dir/file", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := af.(io.Writer).Write([]byte(" and new")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := af.Read(make([]byte, 1)); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Read of write-only file: %v, want ErrPermission", err)
+	}
+	af.Close()
+	if got := string(m["dir/file"].Data); got != "old and new" {
+		t.Errorf("after append: dir/file = %q, want %q", got, "old and new")
+	}
+
+	if _, err := m.OpenFile("dir/file", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("OpenFile with O_EXCL of existing file: %v, want ErrExist", err)
+	}
+	if _, err := m.Create("missing/file"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Create in missing directory: %v, want ErrNotExist", err)
+	}
+	if _, err := m.Create("dir"); err == nil {
+		t.Errorf("Create of directory succeeded")
+	}
+
+	data := []byte("written")
+	if err := m.WriteFile("dir/w", data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data[0] = 'X'
+	if got := string(m["dir/w"].Data); got != "written" {
+		t.Errorf("WriteFile did not copy data: got %q", got)
+	}
+	if err := m.Chmod("dir/w", 0o644|fs.ModeSetuid); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m["dir/w"].Mode, 0o644|fs.ModeSetuid; got != want {
+		t.Errorf("after Chmod: mode = %v, want %v", got, want)
+	}
+	if err := m.Chmod("dir", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m["dir"].Mode, fs.ModeDir|0o700; got != want {
+		t.Errorf("after Chmod of synthesized directory: mode = %v, want %v", got, want)
+	}
+
+	if err := m.Mkdir("dir/sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Mkdir("dir/sub", 0o755); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkdir of existing directory: %v, want ErrExist", err)
+	}
+	if err := m.Mkdir("a/b", 0o755); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Mkdir with missing parent: %v, want ErrNotExist", err)
+	}
+
+	if err := m.Rename("dir", "moved"); err != nil {
+		t.Fatal(err)
+	}
+	err = m.Rename("moved", "moved/sub/x")
+	if _, ok := err.(*os.LinkError); !ok || !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Rename into itself: %v, want *os.LinkError wrapping ErrInvalid", err)
+	}
+	if err := m.Rename("moved/new", "moved/file"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("moved"); err == nil {
+		t.Errorf("Remove of non-empty directory succeeded")
+	}
+	if err := m.Remove("moved/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("moved/sub"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("second Remove: %v, want ErrNotExist", err)
+	}
+	// Remove does not follow a final symbolic link.
+	if err := m.Remove("link"); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	want := []string{"moved", "moved/file", "moved/w"}
+	if !slices.Equal(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	if got := string(m["moved/file"].Data); got != "hello" {
+		t.Errorf("after Rename: moved/file = %q, want %q", got, "hello")
+	}
+	if err := TestFS(m, "moved/file", "moved/w"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMapFSWriteAtPastEOF(t *testing.T) {
+	// The file's data has spare capacity holding stale bytes,
+	// which must neither show in the gap nor be overwritten.
+	backing := []byte("abcXXXXXXX")
+	m := MapFS{"file": {Data: backing[:3]}}
+	f, err := m.OpenFile("file", os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.(io.WriterAt).WriteAt([]byte("yz"), 5); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(m["file"].Data), "abc\x00\x00yz"; got != want {
+		t.Errorf("after WriteAt: data = %q, want %q", got, want)
+	}
+	if got := string(backing); got != "abcXXXXXXX" {
+		t.Errorf("WriteAt wrote to the caller's backing array: %q", got)
+	}
+}